@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/climate"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+)
+
+func main() {
+	var (
+		file    = flag.String("file", "", "normals CSV in climate/normals.csv format (default: bundled dataset)")
+		dataset = flag.Bool("dataset", true, "load the normals dataset into climate_normals")
+		history = flag.Bool("history", true, "aggregate weather_data history into climate_normals")
+		minDays = flag.Int("min-days", climate.MinHistoryDays, "minimum observed days per month to store a history normal")
+		dryRun  = flag.Bool("dry-run", false, "parse and aggregate without writing to the database")
+	)
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "[CLIMATE] ", log.LstdFlags)
+
+	if *minDays < 1 {
+		logger.Fatalf("-min-days must be positive")
+	}
+
+	var normals []domain.ClimateNormal
+	if *dataset {
+		var err error
+		if *file == "" {
+			normals, err = climate.BundledNormals()
+		} else {
+			normals, err = loadFile(*file)
+		}
+		if err != nil {
+			logger.Fatalf("Failed to load normals dataset: %v", err)
+		}
+		logger.Printf("Dataset: %d location-month normals", len(normals))
+	}
+
+	if *dryRun && !*history {
+		return
+	}
+
+	ctx := context.Background()
+
+	dbConfig := config.LoadDatabaseConfig()
+	pool, err := pgxpool.New(ctx, dbConfig.DatabaseURL())
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		logger.Fatalf("Failed to ping database: %v", err)
+	}
+
+	repo := postgres.NewClimateNormalRepo(pool)
+
+	if *history {
+		observed, err := repo.AggregateHistory(ctx, *minDays)
+		if err != nil {
+			logger.Fatalf("Failed to aggregate weather history: %v", err)
+		}
+		logger.Printf("History: %d location-month normals with at least %d observed days", len(observed), *minDays)
+		normals = append(normals, observed...)
+	}
+
+	if *dryRun {
+		logger.Printf("Dry run: %d normals not written", len(normals))
+		return
+	}
+
+	if err := repo.UpsertMany(ctx, normals); err != nil {
+		logger.Fatalf("Failed to store climate normals: %v", err)
+	}
+	logger.Printf("Stored %d climate normals", len(normals))
+}
+
+func loadFile(path string) ([]domain.ClimateNormal, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return climate.ParseNormals(f)
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/catalog"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
+	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+)
+
+func main() {
+	var (
+		outPath      = flag.String("out", "-", "output file (\"-\" for stdout)")
+		formatName   = flag.String("format", "", "ndjson or csv (default: detect by extension, ndjson for stdout)")
+		sources      = flag.String("source", "", "comma-separated list of sources to export (e.g. manual,partner)")
+		categories   = flag.String("category", "", "comma-separated list of categories to export")
+		createdAfter = flag.String("created-after", "", "export only items created after this time (RFC3339 or YYYY-MM-DD)")
+		specsPath    = flag.String("specs", "", "also write referenced subcategory_specs to this NDJSON file")
+	)
+	flag.Parse()
+
+	// Логи в stderr, чтобы не смешивать их с выгрузкой в stdout
+	logger := log.New(os.Stderr, "[EXPORT] ", log.LstdFlags)
+
+	filter := repo.ClothingItemFilter{
+		Sources:    splitList(*sources),
+		Categories: splitList(*categories),
+	}
+	for _, s := range filter.Sources {
+		if !catalog.IsAllowed(s, catalog.Sources) {
+			logger.Fatalf("Unknown source %q (must be one of: %s)", s, strings.Join(catalog.Sources, ", "))
+		}
+	}
+	for _, c := range filter.Categories {
+		if !catalog.IsAllowed(c, catalog.Categories) {
+			logger.Fatalf("Unknown category %q (must be one of: %s)", c, strings.Join(catalog.Categories, ", "))
+		}
+	}
+	if *createdAfter != "" {
+		t, err := parseTime(*createdAfter)
+		if err != nil {
+			logger.Fatalf("Invalid -created-after: %v", err)
+		}
+		filter.CreatedAfter = t
+	}
+
+	format := catalog.FormatNDJSON
+	if *formatName != "" || *outPath != "-" {
+		f, err := catalog.ParseFormat(*formatName, *outPath)
+		if err != nil {
+			logger.Fatalf("Invalid format: %v", err)
+		}
+		format = f
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "-" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			logger.Fatalf("Failed to create output file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	writer, err := catalog.NewWriter(out, format)
+	if err != nil {
+		logger.Fatalf("Failed to create %s writer: %v", format, err)
+	}
+
+	ctx := context.Background()
+
+	dbConfig := config.LoadDatabaseConfig()
+	pool, err := pgxpool.New(ctx, dbConfig.DatabaseURL())
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		logger.Fatalf("Failed to ping database: %v", err)
+	}
+
+	// Пары category/subcategory, на которые ссылаются выгруженные вещи
+	referenced := make(map[[2]string]bool)
+	exported := 0
+
+	err = postgres.NewClothingItemRepo(pool).Stream(ctx, filter, func(it domain.ClothingItem) error {
+		if err := writer.Write(it); err != nil {
+			return fmt.Errorf("write item %d: %w", it.ID, err)
+		}
+		referenced[[2]string{it.Category, it.Subcategory}] = true
+		exported++
+		return nil
+	})
+	if err != nil {
+		logger.Fatalf("Export failed after %d items: %v", exported, err)
+	}
+	if err := writer.Flush(); err != nil {
+		logger.Fatalf("Failed to flush output: %v", err)
+	}
+
+	logger.Printf("Exported %d items (format=%s)", exported, format)
+
+	if *specsPath == "" {
+		return
+	}
+
+	all, err := postgres.NewSubcategorySpecRepo(pool).ListAll(ctx)
+	if err != nil {
+		logger.Fatalf("Failed to load subcategory specs: %v", err)
+	}
+
+	specs := make([]domain.SubcategorySpec, 0, len(referenced))
+	for _, spec := range all {
+		if referenced[[2]string{spec.Category, spec.Subcategory}] {
+			specs = append(specs, spec)
+		}
+	}
+	sort.Slice(specs, func(i, j int) bool {
+		if specs[i].Category != specs[j].Category {
+			return specs[i].Category < specs[j].Category
+		}
+		return specs[i].Subcategory < specs[j].Subcategory
+	})
+
+	sf, err := os.Create(*specsPath)
+	if err != nil {
+		logger.Fatalf("Failed to create specs file: %v", err)
+	}
+	defer sf.Close()
+
+	if err := catalog.WriteSpecs(sf, specs); err != nil {
+		logger.Fatalf("Failed to write specs: %v", err)
+	}
+	logger.Printf("Exported %d subcategory specs to %s", len(specs), *specsPath)
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
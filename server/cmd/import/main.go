@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/catalog"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+)
+
+// RejectedRow — строка отчёта об отклонённых записях (NDJSON).
+type RejectedRow struct {
+	Line   int      `json:"line"`
+	ID     int64    `json:"id,omitempty"`
+	Errors []string `json:"errors"`
+	Raw    string   `json:"raw"`
+}
+
+// ImportStats — итоги импорта.
+type ImportStats struct {
+	Read     int
+	Valid    int
+	Rejected int
+	Written  int64
+}
+
+// Importer потоково читает каталог, валидирует строки и пишет их пачками через COPY.
+type Importer struct {
+	repo      *postgres.ClothingItemRepo
+	validator *catalog.Validator
+	rejects   *json.Encoder
+	logger    *log.Logger
+
+	batchSize int
+	dryRun    bool
+	upsert    bool
+
+	seen map[int64]int // id -> номер строки, где он встретился впервые
+}
+
+// NewImporter creates a new importer
+func NewImporter(repo *postgres.ClothingItemRepo, validator *catalog.Validator, rejects io.Writer, logger *log.Logger, batchSize int, dryRun, upsert bool) *Importer {
+	return &Importer{
+		repo:      repo,
+		validator: validator,
+		rejects:   json.NewEncoder(rejects),
+		logger:    logger,
+		batchSize: batchSize,
+		dryRun:    dryRun,
+		upsert:    upsert,
+		seen:      make(map[int64]int),
+	}
+}
+
+// Run прогоняет весь файл. Ошибки отдельных строк попадают в отчёт, а не прерывают импорт.
+func (im *Importer) Run(ctx context.Context, reader catalog.Reader) (ImportStats, error) {
+	var stats ImportStats
+	batch := make([]domain.ClothingItem, 0, im.batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 || im.dryRun {
+			batch = batch[:0]
+			return nil
+		}
+		n, err := im.repo.CopyItems(ctx, batch, im.upsert)
+		if err != nil {
+			return err
+		}
+		stats.Written += n
+		im.logger.Printf("Written batch: %d rows (total %d)", n, stats.Written)
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		rec, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return stats, err
+		}
+		stats.Read++
+
+		if errs := im.check(&rec); len(errs) > 0 {
+			stats.Rejected++
+			if err := im.rejects.Encode(RejectedRow{Line: rec.Line, ID: rec.Item.ID, Errors: errs, Raw: rec.Raw}); err != nil {
+				return stats, fmt.Errorf("write rejects report: %w", err)
+			}
+			continue
+		}
+
+		stats.Valid++
+		batch = append(batch, rec.Item)
+		if len(batch) >= im.batchSize {
+			if err := flush(); err != nil {
+				return stats, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
+
+// check возвращает причины отклонения строки.
+func (im *Importer) check(rec *catalog.Record) []string {
+	if rec.Err != nil {
+		return []string{rec.Err.Error()}
+	}
+
+	catalog.ApplyDefaults(&rec.Item)
+	errs := im.validator.Validate(rec.Item)
+
+	if firstLine, dup := im.seen[rec.Item.ID]; dup && rec.Item.ID > 0 {
+		errs = append(errs, fmt.Sprintf("duplicate id %d (first seen on line %d)", rec.Item.ID, firstLine))
+	} else if len(errs) == 0 {
+		im.seen[rec.Item.ID] = rec.Line
+	}
+
+	return errs
+}
+
+func main() {
+	var (
+		filePath    = flag.String("file", "", "path to catalog file (NDJSON or CSV)")
+		formatName  = flag.String("format", "", "ndjson or csv (default: detect by extension)")
+		dryRun      = flag.Bool("dry-run", false, "validate only, do not write to the database")
+		upsert      = flag.Bool("upsert", false, "update existing items on id conflict instead of failing")
+		batchSize   = flag.Int("batch-size", 5000, "rows per COPY batch")
+		rejectsPath = flag.String("rejects", "rejected.ndjson", "path to rejected rows report")
+		specsPath   = flag.String("specs", "", "NDJSON file with subcategory_specs to upsert before items (see cmd/export -specs)")
+	)
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[IMPORT] ", log.LstdFlags)
+
+	if *filePath == "" {
+		log.Fatalf("-file is required")
+	}
+	if *batchSize <= 0 {
+		log.Fatalf("-batch-size must be positive")
+	}
+
+	format, err := catalog.ParseFormat(*formatName, *filePath)
+	if err != nil {
+		log.Fatalf("Invalid format: %v", err)
+	}
+
+	in, err := os.Open(*filePath)
+	if err != nil {
+		log.Fatalf("Failed to open catalog file: %v", err)
+	}
+	defer in.Close()
+
+	reader, err := catalog.NewReader(in, format)
+	if err != nil {
+		log.Fatalf("Failed to create %s reader: %v", format, err)
+	}
+
+	rejects, err := os.Create(*rejectsPath)
+	if err != nil {
+		log.Fatalf("Failed to create rejects report: %v", err)
+	}
+	defer rejects.Close()
+
+	ctx := context.Background()
+
+	dbConfig := config.LoadDatabaseConfig()
+	pool, err := pgxpool.New(ctx, dbConfig.DatabaseURL())
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		log.Fatalf("Failed to ping database: %v", err)
+	}
+
+	// Валидируем по живому словарю, а не по захардкоженному списку
+	specRepo := postgres.NewSubcategorySpecRepo(pool)
+	specs, err := specRepo.ListAll(ctx)
+	if err != nil {
+		log.Fatalf("Failed to load subcategory specs: %v", err)
+	}
+
+	validator := catalog.NewValidator(specs)
+
+	if *specsPath != "" {
+		extra, err := readSpecs(*specsPath)
+		if err != nil {
+			log.Fatalf("Failed to read specs file: %v", err)
+		}
+		validator.AddSpecs(extra)
+		if !*dryRun {
+			if err := specRepo.UpsertMany(ctx, extra); err != nil {
+				log.Fatalf("Failed to upsert subcategory specs: %v", err)
+			}
+		}
+		logger.Printf("Loaded %d subcategory specs from %s", len(extra), *specsPath)
+	}
+
+	importer := NewImporter(
+		postgres.NewClothingItemRepo(pool),
+		validator,
+		rejects,
+		logger,
+		*batchSize,
+		*dryRun,
+		*upsert,
+	)
+
+	logger.Printf("Importing %s (format=%s, dry-run=%t, upsert=%t)", *filePath, format, *dryRun, *upsert)
+
+	stats, err := importer.Run(ctx, reader)
+	if err != nil {
+		log.Fatalf("Import failed after %d rows: %v", stats.Read, err)
+	}
+
+	logger.Printf("Done: read=%d valid=%d rejected=%d written=%d", stats.Read, stats.Valid, stats.Rejected, stats.Written)
+	if stats.Rejected > 0 {
+		logger.Printf("Rejected rows report: %s", *rejectsPath)
+	}
+}
+
+func readSpecs(path string) ([]domain.SubcategorySpec, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return catalog.ReadSpecs(f)
+}
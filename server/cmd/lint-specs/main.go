@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/catalog"
+	"outfitstyle/server/internal/core/application/planner"
+	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+)
+
+// RequiredCategories — без этих категорий образ не собрать (верх, низ, обувь).
+var RequiredCategories = []string{"upper", "lower", "footwear"}
+
+// Finding — одна проблема в клетке сетки температура × погода.
+type Finding struct {
+	Condition planner.WeatherCondition
+	Temp      int
+	Category  string
+	Problem   string
+}
+
+// key группирует одинаковые проблемы соседних температур в диапазоны.
+func (f Finding) key() string {
+	return string(f.Condition) + "|" + f.Category + "|" + f.Problem
+}
+
+// Linter прогоняет планировщик по сетке и проверяет, что каждый план собирается из каталога.
+type Linter struct {
+	planner  *planner.OutfitPlanner
+	items    *postgres.ClothingItemRepo
+	minItems int
+}
+
+// NewLinter creates a new planner coverage linter; items == nil disables catalog checks
+func NewLinter(specRepo *postgres.SubcategorySpecRepo, items *postgres.ClothingItemRepo, minItems int) *Linter {
+	return &Linter{
+		planner:  planner.NewOutfitPlanner(specRepo),
+		items:    items,
+		minItems: minItems,
+	}
+}
+
+// Run проверяет все клетки от minTemp до maxTemp включительно.
+func (l *Linter) Run(ctx context.Context, minTemp, maxTemp int) ([]Finding, error) {
+	var findings []Finding
+
+	for _, cond := range planner.Conditions {
+		for t := minTemp; t <= maxTemp; t++ {
+			plan, err := l.planner.GeneratePlan(ctx, float64(t), string(cond), nil)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, category := range RequiredCategories {
+				if len(plan.Plan[category]) == 0 {
+					findings = append(findings, Finding{Condition: cond, Temp: t, Category: category, Problem: "no subcategory in plan"})
+				}
+			}
+
+			if l.items == nil {
+				continue
+			}
+
+			categories := make([]string, 0, len(plan.Plan))
+			for category := range plan.Plan {
+				categories = append(categories, category)
+			}
+			sort.Strings(categories)
+
+			for _, category := range categories {
+				specs := plan.Plan[category]
+				subcategories := make([]string, 0, len(specs))
+				// Та же минимальная теплота, что и в ClothingItemService.GetItemsForPlan
+				var warmthMin int16 = 10
+				for _, spec := range specs {
+					subcategories = append(subcategories, spec.Subcategory)
+					if spec.WarmthMin < warmthMin {
+						warmthMin = spec.WarmthMin
+					}
+				}
+
+				items, err := l.items.FindCandidatesByPlan(ctx, category, subcategories, warmthMin, int16(t), l.minItems)
+				if err != nil {
+					return nil, fmt.Errorf("find candidates for %s at %d°C/%s: %w", category, t, cond, err)
+				}
+				if len(items) < l.minItems {
+					findings = append(findings, Finding{
+						Condition: cond,
+						Temp:      t,
+						Category:  category,
+						Problem:   fmt.Sprintf("fewer than %d catalog items (%s)", l.minItems, strings.Join(subcategories, ", ")),
+					})
+				}
+			}
+		}
+	}
+
+	return findings, nil
+}
+
+// report сворачивает соседние температуры с одинаковой проблемой в диапазоны.
+func report(findings []Finding) []string {
+	var order []string
+	temps := make(map[string][]int)
+	first := make(map[string]Finding)
+	for _, f := range findings {
+		k := f.key()
+		if _, ok := temps[k]; !ok {
+			order = append(order, k)
+			first[k] = f
+		}
+		temps[k] = append(temps[k], f.Temp)
+	}
+
+	var lines []string
+	for _, k := range order {
+		f := first[k]
+		ts := temps[k]
+		for i := 0; i < len(ts); {
+			j := i
+			for j+1 < len(ts) && ts[j+1] == ts[j]+1 {
+				j++
+			}
+			span := fmt.Sprintf("%d°C", ts[i])
+			if j > i {
+				span = fmt.Sprintf("%d..%d°C", ts[i], ts[j])
+			}
+			lines = append(lines, fmt.Sprintf("%-13s %-12s %-9s %s", f.Condition, span, f.Category, f.Problem))
+			i = j + 1
+		}
+	}
+	return lines
+}
+
+func main() {
+	var (
+		minTemp   = flag.Int("min-temp", catalog.PlanSweepMinTemp, "lowest temperature to check, °C")
+		maxTemp   = flag.Int("max-temp", catalog.PlanSweepMaxTemp, "highest temperature to check, °C")
+		minItems  = flag.Int("min-items", 3, "flag plan cells where the catalog has fewer candidates than this")
+		specsOnly = flag.Bool("specs-only", false, "check only plan coverage, skip catalog queries")
+	)
+	flag.Parse()
+
+	logger := log.New(os.Stdout, "[LINT-SPECS] ", log.LstdFlags)
+
+	if *minTemp > *maxTemp {
+		logger.Fatalf("-min-temp must not be greater than -max-temp")
+	}
+	if *minItems <= 0 {
+		logger.Fatalf("-min-items must be positive")
+	}
+
+	ctx := context.Background()
+
+	dbConfig := config.LoadDatabaseConfig()
+	pool, err := pgxpool.New(ctx, dbConfig.DatabaseURL())
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		logger.Fatalf("Failed to ping database: %v", err)
+	}
+
+	var items *postgres.ClothingItemRepo
+	if !*specsOnly {
+		items = postgres.NewClothingItemRepo(pool)
+	}
+
+	linter := NewLinter(postgres.NewSubcategorySpecRepo(pool), items, *minItems)
+
+	cells := (*maxTemp - *minTemp + 1) * len(planner.Conditions)
+	logger.Printf("Checking %d plan cells (%d..%d°C × %d conditions)", cells, *minTemp, *maxTemp, len(planner.Conditions))
+
+	findings, err := linter.Run(ctx, *minTemp, *maxTemp)
+	if err != nil {
+		logger.Fatalf("Lint failed: %v", err)
+	}
+
+	if len(findings) == 0 {
+		logger.Printf("OK: every plan has %s and enough catalog items", strings.Join(RequiredCategories, ", "))
+		return
+	}
+
+	for _, line := range report(findings) {
+		fmt.Println(line)
+	}
+	logger.Printf("Found %d problems", len(findings))
+	// Ненулевой код, чтобы линтер можно было ставить в CI перед выкаткой норм
+	os.Exit(1)
+}
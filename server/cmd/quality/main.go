@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/catalog"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
+	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+)
+
+// Report — итог проверки каталога.
+type Report struct {
+	TotalItems           int             `json:"total_items"`
+	ItemsWithErrors      int             `json:"items_with_errors"`
+	ItemsWithWarnings    int             `json:"items_with_warnings"`
+	ByRule               map[string]int  `json:"by_rule"`
+	CategoryDistribution map[string]int  `json:"category_distribution"`
+	Quarantined          int64           `json:"quarantined"`
+	Issues               []catalog.Issue `json:"issues"`
+}
+
+// Build собирает отчёт по всем вещам выборки.
+func Build(items []domain.ClothingItem, validator *catalog.Validator, similarity float64) Report {
+	report := Report{
+		TotalItems:           len(items),
+		ByRule:               make(map[string]int),
+		CategoryDistribution: make(map[string]int),
+	}
+
+	for _, it := range items {
+		report.CategoryDistribution[it.Category]++
+		report.Issues = append(report.Issues, validator.CheckItem(it)...)
+	}
+	report.Issues = append(report.Issues, catalog.FindNearDuplicates(items, similarity)...)
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		return report.Issues[i].ItemID < report.Issues[j].ItemID
+	})
+
+	withErrors := make(map[int64]bool)
+	withWarnings := make(map[int64]bool)
+	for _, is := range report.Issues {
+		report.ByRule[is.Rule]++
+		if is.Severity == catalog.SeverityError {
+			withErrors[is.ItemID] = true
+		} else {
+			withWarnings[is.ItemID] = true
+		}
+	}
+	report.ItemsWithErrors = len(withErrors)
+	report.ItemsWithWarnings = len(withWarnings)
+	return report
+}
+
+// quarantineReasons выбирает вещи для карантина: все с ошибками и, если нужно, почти-дубликаты.
+func quarantineReasons(issues []catalog.Issue, duplicates bool) map[int64][]string {
+	reasons := make(map[int64][]string)
+	for _, is := range issues {
+		if is.Severity == catalog.SeverityError || (duplicates && is.Rule == catalog.RuleNearDuplicate) {
+			reasons[is.ItemID] = append(reasons[is.ItemID], is.Rule+": "+is.Message)
+		}
+	}
+	return reasons
+}
+
+func printText(w io.Writer, report Report) {
+	fmt.Fprintf(w, "Всего вещей: %d\n", report.TotalItems)
+	fmt.Fprintf(w, "С ошибками: %d, с предупреждениями: %d\n", report.ItemsWithErrors, report.ItemsWithWarnings)
+
+	fmt.Fprintln(w, "\nРаспределение по категориям:")
+	categories := make([]string, 0, len(report.CategoryDistribution))
+	for c := range report.CategoryDistribution {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+	for _, c := range categories {
+		n := report.CategoryDistribution[c]
+		fmt.Fprintf(w, "  %-10s %6d (%.1f%%)\n", c, n, float64(n)*100/float64(report.TotalItems))
+	}
+
+	if len(report.ByRule) > 0 {
+		fmt.Fprintln(w, "\nНаходки по правилам:")
+		rules := make([]string, 0, len(report.ByRule))
+		for r := range report.ByRule {
+			rules = append(rules, r)
+		}
+		sort.Strings(rules)
+		for _, r := range rules {
+			fmt.Fprintf(w, "  %-18s %6d\n", r, report.ByRule[r])
+		}
+
+		fmt.Fprintln(w, "\nНаходки:")
+		for _, is := range report.Issues {
+			fmt.Fprintf(w, "  item %-8d %-9s %-7s %-18s %s\n", is.ItemID, is.Source, is.Severity, is.Rule, is.Message)
+		}
+	}
+
+	if report.Quarantined > 0 {
+		fmt.Fprintf(w, "\nПомещено в карантин: %d\n", report.Quarantined)
+	}
+}
+
+func main() {
+	var (
+		outPath    = flag.String("out", "-", "report file (\"-\" for stdout)")
+		format     = flag.String("format", "text", "report format: text or json")
+		sources    = flag.String("source", "", "comma-separated list of sources to check")
+		categories = flag.String("category", "", "comma-separated list of categories to check")
+		similarity = flag.Float64("similarity", catalog.DefaultNameSimilarity, "name similarity threshold for near-duplicates (0..1)")
+		quarantine = flag.Bool("quarantine", false, "quarantine items with error-level findings")
+		quarDups   = flag.Bool("quarantine-duplicates", false, "with -quarantine, also quarantine near-duplicates")
+	)
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "[QUALITY] ", log.LstdFlags)
+
+	if *format != "text" && *format != "json" {
+		logger.Fatalf("Unsupported -format %q (must be text or json)", *format)
+	}
+	if *similarity <= 0 || *similarity > 1 {
+		logger.Fatalf("-similarity must be in (0, 1]")
+	}
+
+	var out io.Writer = os.Stdout
+	if *outPath != "-" {
+		f, err := os.Create(*outPath)
+		if err != nil {
+			logger.Fatalf("Failed to create report file: %v", err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	ctx := context.Background()
+
+	dbConfig := config.LoadDatabaseConfig()
+	pool, err := pgxpool.New(ctx, dbConfig.DatabaseURL())
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer pool.Close()
+
+	if err := pool.Ping(ctx); err != nil {
+		logger.Fatalf("Failed to ping database: %v", err)
+	}
+
+	// Те же правила, что и при импорте: живой словарь норм + перечисления схемы
+	specs, err := postgres.NewSubcategorySpecRepo(pool).ListAll(ctx)
+	if err != nil {
+		logger.Fatalf("Failed to load subcategory specs: %v", err)
+	}
+	validator := catalog.NewValidator(specs)
+
+	itemRepo := postgres.NewClothingItemRepo(pool)
+	filter := repo.ClothingItemFilter{
+		Sources:    splitList(*sources),
+		Categories: splitList(*categories),
+	}
+
+	// Поиск дубликатов сравнивает вещи между собой, поэтому выборку держим в памяти
+	var items []domain.ClothingItem
+	if err := itemRepo.Stream(ctx, filter, func(it domain.ClothingItem) error {
+		items = append(items, it)
+		return nil
+	}); err != nil {
+		logger.Fatalf("Failed to load clothing items: %v", err)
+	}
+
+	report := Build(items, validator, *similarity)
+
+	if *quarantine {
+		reasons := quarantineReasons(report.Issues, *quarDups)
+		if len(reasons) > 0 {
+			n, err := itemRepo.Quarantine(ctx, reasons)
+			if err != nil {
+				logger.Fatalf("Failed to quarantine items: %v", err)
+			}
+			report.Quarantined = n
+		}
+	}
+
+	switch *format {
+	case "json":
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			logger.Fatalf("Failed to write report: %v", err)
+		}
+	default:
+		printText(out, report)
+	}
+
+	logger.Printf("Checked %d items: %d with errors, %d with warnings", report.TotalItems, report.ItemsWithErrors, report.ItemsWithWarnings)
+	if report.ItemsWithErrors > 0 {
+		os.Exit(1)
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+// Command rebuild-stats пересчитывает user_stats из исходных таблиц — после миграции 0013,
+// ручной правки данных или если события статистики были потеряны.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+)
+
+func main() {
+	userID := flag.Int("user", 0, "rebuild stats of one user (default: all users)")
+	flag.Parse()
+
+	logger := log.New(os.Stderr, "[STATS] ", log.LstdFlags)
+
+	if *userID < 0 {
+		logger.Fatalf("-user must not be negative")
+	}
+
+	zapLogger, err := zap.NewDevelopment()
+	if err != nil {
+		logger.Fatalf("Failed to create logger: %v", err)
+	}
+	defer func() { _ = zapLogger.Sync() }()
+
+	dbConfig := config.LoadDatabaseConfig()
+	db, err := postgres.NewDB(dbConfig.DatabaseURL(), zapLogger)
+	if err != nil {
+		logger.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	statsService := services.NewStatsService(postgres.NewStatsRepository(db, zapLogger), zapLogger)
+
+	if *userID > 0 {
+		if err := statsService.Rebuild(ctx, *userID); err != nil {
+			logger.Fatalf("Failed to rebuild stats: %v", err)
+		}
+		logger.Printf("Rebuilt stats of user %d", *userID)
+		return
+	}
+
+	rebuilt, err := statsService.RebuildAll(ctx)
+	if err != nil {
+		logger.Fatalf("Rebuilt stats of %d users, then failed: %v", rebuilt, err)
+	}
+	logger.Printf("Rebuilt stats of %d users", rebuilt)
+}
@@ -7,6 +7,8 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"log"
 	stdhttp "net/http"
 	"os"
@@ -22,11 +24,20 @@ import (
 	"outfitstyle/server/internal/api/handlers"
 	"outfitstyle/server/internal/api/middleware"
 	"outfitstyle/server/internal/config"
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/application/catalog"
+	"outfitstyle/server/internal/core/application/climate"
+	"outfitstyle/server/internal/core/application/jobs"
+	"outfitstyle/server/internal/core/application/planner"
 	"outfitstyle/server/internal/core/application/services"
 	_ "outfitstyle/server/internal/docs"
+	"outfitstyle/server/internal/infrastructure/cache"
 	"outfitstyle/server/internal/infrastructure/external"
 	"outfitstyle/server/internal/infrastructure/persistence/postgres"
+	"outfitstyle/server/internal/infrastructure/storage"
+	"outfitstyle/server/internal/pkg/gazetteer"
 	"outfitstyle/server/internal/pkg/health"
+	pkghttp "outfitstyle/server/internal/pkg/http"
 )
 
 func main() {
@@ -55,19 +66,53 @@ func main() {
 	defer db.Close()
 
 	// ---------- Внешние сервисы ----------
-	weatherAPIKey := os.Getenv("WEATHER_API_KEY")
-	weatherBaseURL := os.Getenv("WEATHER_API_BASE_URL")
-	if weatherAPIKey == "" {
-		logger.Warn("WEATHER_API_KEY is not set")
+	weatherTimeout := time.Duration(cfg.WeatherAPI.Timeout) * time.Second
+	var weatherProviders []external.WeatherProvider
+	for _, name := range cfg.WeatherAPI.Providers {
+		switch name {
+		case config.WeatherProviderOpenWeatherMap:
+			weatherProviders = append(weatherProviders, external.NewOpenWeatherMapProvider(
+				cfg.WeatherAPI.Key,
+				cfg.WeatherAPI.BaseURL,
+				weatherTimeout,
+				logger,
+			))
+		case config.WeatherProviderOpenMeteo:
+			weatherProviders = append(weatherProviders, external.NewOpenMeteoProvider(
+				cfg.WeatherAPI.OpenMeteoURL,
+				cfg.WeatherAPI.OpenMeteoGeocodingURL,
+				weatherTimeout,
+				logger,
+			))
+		}
+	}
+	logger.Info("Weather providers configured", zap.Strings("providers", cfg.WeatherAPI.Providers))
+	weatherFailover := external.NewWeatherService(logger, weatherProviders...)
+
+	// ---------- Кэш ----------
+	var appCache cache.Cache = cache.NewMemoryCache()
+	checks := map[string]health.Checker{}
+	if cfg.Cache.Enabled && cfg.Cache.Backend == "redis" {
+		redisCache, err := cache.NewRedisCache(context.Background(), cfg.Cache.RedisURL, "outfitstyle:")
+		if err != nil {
+			logger.Warn("Redis is unavailable, falling back to in-memory cache", zap.Error(err))
+		} else {
+			defer redisCache.Close()
+			appCache = redisCache
+			checks["redis"] = redisCache
+		}
 	}
-	if weatherBaseURL == "" {
-		weatherBaseURL = "https://api.openweathermap.org/data/2.5"
+	weatherCacheTTL := time.Duration(cfg.Cache.Expiration) * time.Second
+	if !cfg.Cache.Enabled {
+		weatherCacheTTL = 0
 	}
 
-	weatherService := external.NewWeatherService(
-		weatherAPIKey,
-		weatherBaseURL,
-		10*time.Second,
+	// Кэш + singleflight + запись истории в weather_data поверх failover
+	weatherService := external.NewCachedWeatherProvider(
+		weatherFailover,
+		appCache,
+		postgres.NewWeatherRepository(db, logger),
+		weatherCacheTTL,
 		logger,
 	)
 
@@ -85,6 +130,43 @@ func main() {
 	userRepo := postgres.NewUserRepository(db, logger)
 	recommendationRepo := postgres.NewRecommendationRepository(db, logger)
 	clothingItemRepo := postgres.NewClothingItemRepository(db, logger)
+	specRepo := postgres.NewSubcategorySpecRepo(db.Pool())
+	catalogItemRepo := postgres.NewClothingItemRepo(db.Pool())
+	photoRepo := postgres.NewPhotoRepository(db, logger)
+	userLocationRepo := postgres.NewUserLocationRepository(db, logger)
+	weatherAlertRepo := postgres.NewWeatherAlertRepository(db, logger)
+	digestRepo := postgres.NewDigestRepository(db, logger)
+	webhookRepo := postgres.NewWebhookRepository(db, logger)
+	jobRepo := postgres.NewJobRepository(db, logger)
+	outboxRepo := postgres.NewOutboxRepository(db, logger)
+	achievementRepo := postgres.NewAchievementRepository(db, logger)
+	statsRepo := postgres.NewStatsRepository(db, logger)
+	challengeRepo := postgres.NewChallengeRepository(db, logger)
+
+	// ---------- Хранилище фото ----------
+	signingKey := cfg.Storage.SigningKey
+	if signingKey == "" && cfg.Storage.Backend == "local" {
+		// Только в development (в production конфиг это не пропустит): ссылки живут до перезапуска
+		logger.Warn("STORAGE_SIGNING_KEY is not set, using a random per-process key")
+		signingKey = randomKey()
+	}
+	photoStorage, err := storage.New(storage.Config{
+		Backend:       cfg.Storage.Backend,
+		LocalDir:      cfg.Storage.LocalDir,
+		PublicBaseURL: cfg.Storage.PublicBaseURL,
+		SigningKey:    signingKey,
+		S3: storage.S3Config{
+			Endpoint:  cfg.Storage.S3Endpoint,
+			Region:    cfg.Storage.S3Region,
+			Bucket:    cfg.Storage.S3Bucket,
+			AccessKey: cfg.Storage.S3AccessKey,
+			SecretKey: cfg.Storage.S3SecretKey,
+			PathStyle: cfg.Storage.S3PathStyle,
+		},
+	})
+	if err != nil {
+		logger.Fatal("Photo storage init failed", zap.Error(err))
+	}
 
 	// ---------- EmailService через cfg.Email ----------
 	var emailService services.EmailService
@@ -118,24 +200,59 @@ func main() {
 	tokenService := services.NewTokenService(
 		cfg.Security.JWTSecret,
 		time.Duration(cfg.Security.TokenExpiryHours)*time.Hour,
+	)
+	sessionService := services.NewSessionService(
+		postgres.NewSessionRepository(db, logger),
 		time.Duration(cfg.Security.RefreshTokenExpiryDays)*24*time.Hour,
+		logger,
 	)
+	twoFactorService := services.NewTwoFactorService(postgres.NewTwoFactorRepository(db, logger), userRepo, logger)
 
 	authConfig := services.AuthConfig{
 		TokenExpiryHours:       cfg.Security.TokenExpiryHours,
 		VerificationCodeExpiry: time.Duration(cfg.Security.VerificationCodeExpiry) * time.Minute,
 		MaxLoginAttempts:       cfg.Security.MaxLoginAttempts,
+		MaxLoginAttemptsPerIP:  cfg.Security.MaxLoginAttemptsPerIP,
 		BlockDuration:          time.Duration(cfg.Security.BlockDuration) * time.Minute,
 	}
 
 	authService := services.NewAuthService(
 		userRepo,
+		postgres.NewAuthStateRepository(db, logger),
 		emailService,
 		tokenService,
+		sessionService,
+		twoFactorService,
 		authConfig,
 	)
 
+	// ---------- Вебхуки ----------
+	webhookService := services.NewWebhookService(webhookRepo, services.WebhookConfig{
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		Timeout:      cfg.Webhooks.Timeout,
+		BaseBackoff:  cfg.Webhooks.BaseBackoff,
+		MaxBackoff:   cfg.Webhooks.MaxBackoff,
+		AllowPrivate: cfg.Webhooks.AllowPrivate,
+	}, logger)
+
+	// ---------- Очередь фоновых задач ----------
+	// Обработчики регистрируют сервисы (WithJobs), запуск — после их создания
+	jobQueue := jobs.NewQueue(jobRepo, jobs.Config{
+		Workers:      cfg.Jobs.Workers,
+		PollInterval: cfg.Jobs.PollInterval,
+		Lease:        cfg.Jobs.Lease,
+		DrainTimeout: cfg.Jobs.DrainTimeout,
+		BaseBackoff:  cfg.Jobs.BaseBackoff,
+		MaxBackoff:   cfg.Jobs.MaxBackoff,
+		Retention:    cfg.Jobs.Retention,
+	}, logger)
+
 	// ---------- Доменные сервисы ----------
+	// События пишутся в outbox; relay раздаёт их вебхукам и статистике
+	eventPublisher := services.NewOutboxPublisher(outboxRepo, logger)
+	statsService := services.NewStatsService(statsRepo, logger)
+	challengeService := services.NewChallengeService(challengeRepo, logger).WithEvents(eventPublisher)
+
 	recommendationService := services.NewRecommendationService(
 		recommendationRepo,
 		userRepo,
@@ -145,23 +262,113 @@ func main() {
 		logger,
 	)
 
-	userService := services.NewUserService(userRepo, logger)
+	userService := services.NewUserService(userRepo, logger).WithEvents(eventPublisher)
+	achievementService := services.NewAchievementService(achievementRepo, logger).WithEvents(eventPublisher).WithJobs(jobQueue)
+	specService := catalog.NewSpecService(specRepo, catalogItemRepo)
+	photoService := services.NewPhotoService(photoRepo, photoStorage, services.PhotoConfig{
+		MaxBytes:      int64(cfg.Storage.PhotoMaxMB) << 20,
+		ThumbnailSize: cfg.Storage.PhotoThumbnailSize,
+		URLTTL:        cfg.Storage.URLTTL,
+	}, logger)
+
+	// ---------- Справочник городов ----------
+	places, err := gazetteer.Bundled()
+	if err != nil {
+		logger.Fatal("Failed to load city gazetteer", zap.Error(err))
+	}
+	userLocationService := services.NewUserLocationService(userLocationRepo, places, logger)
+
+	// ---------- Предупреждения об опасной погоде ----------
+	alertRules := alerts.Rules{
+		ColdC:        float64(cfg.Alerts.ColdC),
+		ColdDangerC:  float64(cfg.Alerts.ColdDangerC),
+		HeatC:        float64(cfg.Alerts.HeatC),
+		HeatDangerC:  float64(cfg.Alerts.HeatDangerC),
+		WindMS:       float64(cfg.Alerts.WindMS),
+		WindDangerMS: float64(cfg.Alerts.WindDangerMS),
+	}
+	weatherAlertService := services.NewWeatherAlertService(weatherAlertRepo, weatherService, emailService, places, alertRules, logger)
+	recommendationService.WithAlerts(weatherAlertService, catalogItemRepo)
+
+	// Планы образов на дату: в пределах прогноза — по нему, дальше — по климатической норме.
+	outfitPlanner := planner.NewOutfitPlanner(specRepo).
+		WithAlertRules(alertRules).
+		WithWeather(
+			services.NewPlanForecast(weatherFailover),
+			climate.NewService(postgres.NewClimateNormalRepo(db.Pool())),
+		)
+	userService.WithPlanner(outfitPlanner)
+
+	// Фоновые задачи останавливаются вместе с сервером
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go authService.RunCleanup(bgCtx, cfg.Security.AuthCleanupInterval)
+	if cfg.Alerts.NotifyEnabled {
+		logger.Info("Weather alert notifications enabled", zap.Duration("interval", cfg.Alerts.CheckInterval))
+		go weatherAlertService.Run(bgCtx, cfg.Alerts.CheckInterval)
+	}
+
+	// ---------- Утренний дайджест ----------
+	digestService := services.NewDigestService(
+		digestRepo,
+		userRepo,
+		clothingItemRepo,
+		recommendationService,
+		weatherAlertService,
+		weatherService,
+		emailService,
+		places,
+		logger,
+	)
+	if cfg.Digest.Enabled {
+		logger.Info("Outfit digest enabled", zap.Duration("poll_interval", cfg.Digest.PollInterval))
+		go digestService.Run(bgCtx, cfg.Digest.PollInterval)
+	}
+	if cfg.Webhooks.Enabled {
+		go webhookService.Run(bgCtx, cfg.Webhooks.PollInterval)
+	}
+	// События из outbox превращаются в доставки вебхуков, обновляют статистику, серии и испытания
+	go services.NewOutboxRelay(outboxRepo, services.EventSinks{webhookService, statsService, challengeService}, logger).
+		Run(bgCtx, cfg.Webhooks.PollInterval)
+
+	// Очередь останавливается отдельно: после HTTP-сервера, чтобы принять задачи последних запросов
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	jobsDone := make(chan struct{})
+	go func() {
+		jobQueue.Run(jobsCtx)
+		close(jobsDone)
+	}()
 
 	// ---------- HTTP‑обработчики ----------
-	clothingItemHandler := handlers.NewClothingItemHandler(clothingItemService, logger)
-	recommendationHandler := handlers.NewRecommendationHandler(recommendationService, weatherService, logger)
+	clothingItemHandler := handlers.NewClothingItemHandler(clothingItemService, photoService, logger).
+		WithEvents(eventPublisher).
+		WithAchievements(achievementService)
+	recommendationHandler := handlers.NewRecommendationHandler(recommendationService, weatherService, userLocationService, weatherAlertService, places, logger).
+		WithAchievements(achievementService)
 	authHandler := handlers.NewAuthHandler(authService, googleAuth)
-	userHandler := handlers.NewUserHandler(userService, logger)
+	userHandler := handlers.NewUserHandler(userService, logger).WithStats(statsService)
+	specAdminHandler := handlers.NewSpecAdminHandler(specService, logger)
+	photoHandler := handlers.NewPhotoHandler(photoService, logger)
+	locationHandler := handlers.NewLocationHandler(places, logger)
+	userLocationHandler := handlers.NewUserLocationHandler(userLocationService, logger)
+	digestHandler := handlers.NewDigestHandler(digestService, logger)
+	webhookHandler := handlers.NewWebhookHandler(webhookService, logger)
+	challengeHandler := handlers.NewChallengeHandler(challengeService, logger)
+	sessionHandler := handlers.NewSessionHandler(sessionService, logger)
+	twoFactorHandler := handlers.NewTwoFactorHandler(twoFactorService, logger)
+
+	if cfg.Security.AdminToken == "" {
+		logger.Warn("ADMIN_API_TOKEN is not set, admin API is disabled")
+	}
 
 	// ---------- Роутер ----------
-	router := setupRouter(cfg, clothingItemHandler, recommendationHandler, authHandler, userHandler, logger)
+	router := setupRouter(cfg, clothingItemHandler, recommendationHandler, authHandler, userHandler, specAdminHandler, photoHandler, locationHandler, userLocationHandler, digestHandler, webhookHandler, challengeHandler, sessionHandler, twoFactorHandler, photoStorage, logger)
 
 	// ---------- Health checks ----------
-	checks := map[string]health.Checker{
-		"database": db,
-		"weather":  weatherService,
-		"ml":       mlService,
-	}
+	checks["database"] = db
+	checks["weather"] = weatherService
+	checks["ml"] = mlService
 	health.RegisterChecks(checks)
 
 	// ---------- HTTP‑сервер ----------
@@ -187,6 +394,7 @@ func main() {
 	signal.Notify(shutdown, syscall.SIGINT, syscall.SIGTERM)
 	<-shutdown
 	logger.Info("Shutting down server...")
+	stopBackground()
 
 	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
 	defer cancel()
@@ -195,9 +403,22 @@ func main() {
 		logger.Error("Server forced to shutdown", zap.Error(err))
 	}
 
+	// Дожидаемся выполняющихся задач; не успевшие вернутся в очередь
+	stopJobs()
+	<-jobsDone
+
 	logger.Info("Server stopped successfully")
 }
 
+// randomKey генерирует ключ подписи ссылок на время жизни процесса.
+func randomKey() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		log.Fatalf("Failed to generate signing key: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
 func setupLogger() (*zap.Logger, error) {
 	var cfg zap.Config
 	if os.Getenv("ENVIRONMENT") == "production" {
@@ -219,6 +440,16 @@ func setupRouter(
 	recommendationHandler *handlers.RecommendationHandler,
 	authHandler *handlers.AuthHandler,
 	userHandler *handlers.UserHandler,
+	specAdminHandler *handlers.SpecAdminHandler,
+	photoHandler *handlers.PhotoHandler,
+	locationHandler *handlers.LocationHandler,
+	userLocationHandler *handlers.UserLocationHandler,
+	digestHandler *handlers.DigestHandler,
+	webhookHandler *handlers.WebhookHandler,
+	challengeHandler *handlers.ChallengeHandler,
+	sessionHandler *handlers.SessionHandler,
+	twoFactorHandler *handlers.TwoFactorHandler,
+	photoStorage storage.Storage,
 	logger *zap.Logger,
 ) *mux.Router {
 	router := mux.NewRouter()
@@ -227,6 +458,7 @@ func setupRouter(
 	router.Use(
 		middleware.CORSMiddleware(cfg.Security.GetAllowedOrigins()),
 		middleware.LoggerMiddleware(logger),
+		pkghttp.ClientIPMiddleware(cfg.Security.GetTrustedProxies()),
 		middleware.RateLimitMiddleware(cfg.Security.RateLimit, time.Minute),
 	)
 
@@ -236,12 +468,20 @@ func setupRouter(
 	// Swagger UI: /swagger/index.html
 	router.PathPrefix("/swagger/").Handler(httpSwagger.WrapHandler)
 
+	// Фото гардероба по подписанным ссылкам (S3 отдаёт их сам)
+	if local, ok := photoStorage.(*storage.LocalStorage); ok {
+		router.PathPrefix(storage.MediaPathPrefix).Handler(local).Methods(stdhttp.MethodGet, stdhttp.MethodHead)
+	}
+
 	// API v1
 	api := router.PathPrefix("/api/v1").Subrouter()
 
 	// Auth routes: /api/v1/auth/...
 	authHandler.RegisterRoutes(api)
 
+	// Поиск города: справочник общий, авторизация не нужна
+	api.HandleFunc("/locations/search", locationHandler.SearchLocations).Methods(stdhttp.MethodGet)
+
 	// Protected routes (пока без auth‑middleware)
 	protected := api.PathPrefix("").Subrouter()
 
@@ -256,11 +496,36 @@ func setupRouter(
 	users.HandleFunc("/{id}/outfit-plans", userHandler.CreateOutfitPlan).Methods(stdhttp.MethodPost)
 	users.HandleFunc("/{id}/outfit-plans/{plan_id}", userHandler.DeleteOutfitPlan).Methods(stdhttp.MethodDelete)
 	users.HandleFunc("/{id}/stats", userHandler.GetUserStats).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/streak", challengeHandler.GetStreak).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/challenges", challengeHandler.ListChallenges).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/sessions", sessionHandler.ListSessions).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/sessions/{session_id:[0-9]+}", sessionHandler.RevokeSession).Methods(stdhttp.MethodDelete)
+	users.HandleFunc("/{id}/2fa", twoFactorHandler.GetTwoFactor).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/2fa/enroll", twoFactorHandler.EnrollTwoFactor).Methods(stdhttp.MethodPost)
+	users.HandleFunc("/{id}/2fa/confirm", twoFactorHandler.ConfirmTwoFactor).Methods(stdhttp.MethodPost)
+	users.HandleFunc("/{id}/2fa/disable", twoFactorHandler.DisableTwoFactor).Methods(stdhttp.MethodPost)
+	users.HandleFunc("/{id}/locations", userLocationHandler.ListLocations).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/locations", userLocationHandler.CreateLocation).Methods(stdhttp.MethodPost)
+	users.HandleFunc("/{id}/locations/{loc_id:[0-9]+}", userLocationHandler.UpdateLocation).Methods(stdhttp.MethodPut)
+	users.HandleFunc("/{id}/locations/{loc_id:[0-9]+}", userLocationHandler.DeleteLocation).Methods(stdhttp.MethodDelete)
+	users.HandleFunc("/{id}/locations/{loc_id:[0-9]+}/default", userLocationHandler.SetDefaultLocation).Methods(stdhttp.MethodPut)
+	users.HandleFunc("/{id}/digest", digestHandler.GetDigest).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/digest", digestHandler.SubscribeDigest).Methods(stdhttp.MethodPut)
+	users.HandleFunc("/{id}/digest", digestHandler.UnsubscribeDigest).Methods(stdhttp.MethodDelete)
+	users.HandleFunc("/{id}/webhooks", webhookHandler.ListWebhooks).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/webhooks", webhookHandler.CreateWebhook).Methods(stdhttp.MethodPost)
+	users.HandleFunc("/{id}/webhooks/{webhook_id:[0-9]+}", webhookHandler.GetWebhook).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/webhooks/{webhook_id:[0-9]+}", webhookHandler.UpdateWebhook).Methods(stdhttp.MethodPut)
+	users.HandleFunc("/{id}/webhooks/{webhook_id:[0-9]+}", webhookHandler.DeleteWebhook).Methods(stdhttp.MethodDelete)
+	users.HandleFunc("/{id}/webhooks/{webhook_id:[0-9]+}/rotate-secret", webhookHandler.RotateWebhookSecret).Methods(stdhttp.MethodPost)
+	users.HandleFunc("/{id}/webhooks/{webhook_id:[0-9]+}/deliveries", webhookHandler.ListWebhookDeliveries).Methods(stdhttp.MethodGet)
+	users.HandleFunc("/{id}/webhooks/{webhook_id:[0-9]+}/deliveries/{delivery_id:[0-9]+}/redeliver", webhookHandler.RedeliverWebhook).Methods(stdhttp.MethodPost)
 
 	// Clothing items routes
 	clothingItems := protected.PathPrefix("/clothing-items").Subrouter()
 	clothingItems.HandleFunc("", clothingItemHandler.GetAllClothingItems).Methods(stdhttp.MethodGet)
 	clothingItems.HandleFunc("", clothingItemHandler.CreateClothingItem).Methods(stdhttp.MethodPost)
+	clothingItems.HandleFunc("/detect-colour", clothingItemHandler.DetectColour).Methods(stdhttp.MethodPost)
 	clothingItems.HandleFunc("/{id:[0-9]+}", clothingItemHandler.GetClothingItem).Methods(stdhttp.MethodGet)
 	clothingItems.HandleFunc("/{id:[0-9]+}", clothingItemHandler.UpdateClothingItem).Methods(stdhttp.MethodPut)
 	clothingItems.HandleFunc("/{id:[0-9]+}", clothingItemHandler.DeleteClothingItem).Methods(stdhttp.MethodDelete)
@@ -270,6 +535,13 @@ func setupRouter(
 	wardrobe.HandleFunc("/users/{user_id:[0-9]+}", clothingItemHandler.GetWardrobeItems).Methods(stdhttp.MethodGet)
 	wardrobe.HandleFunc("/users/{user_id:[0-9]+}/items/{item_id:[0-9]+}", clothingItemHandler.AddItemToWardrobe).Methods(stdhttp.MethodPost)
 	wardrobe.HandleFunc("/users/{user_id:[0-9]+}/items/{item_id:[0-9]+}", clothingItemHandler.RemoveItemFromWardrobe).Methods(stdhttp.MethodDelete)
+	wardrobe.HandleFunc("/users/{user_id:[0-9]+}/items/{item_id:[0-9]+}/photo", photoHandler.UploadPhoto).Methods(stdhttp.MethodPost)
+	wardrobe.HandleFunc("/users/{user_id:[0-9]+}/items/{item_id:[0-9]+}/photo", photoHandler.DeletePhoto).Methods(stdhttp.MethodDelete)
+
+	// Admin routes: /api/v1/admin/... (статический токен в X-Admin-Token)
+	admin := api.PathPrefix("/admin").Subrouter()
+	admin.Use(middleware.AdminTokenMiddleware(cfg.Security.AdminToken))
+	specAdminHandler.RegisterRoutes(admin)
 
 	// Prometheus metrics
 	router.Handle("/metrics", promhttp.Handler()).Methods(stdhttp.MethodGet)
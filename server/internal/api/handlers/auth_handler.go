@@ -6,10 +6,12 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"net/mail"
 	"outfitstyle/server/internal/core/application/repositories"
 	"outfitstyle/server/internal/infrastructure/external"
+	"strconv"
 	"strings"
 	"time"
 	"unicode"
@@ -21,7 +23,10 @@ import (
 	resp "outfitstyle/server/internal/pkg/http"
 )
 
-const accessTokenTTLSeconds = 3600
+const (
+	accessTokenTTLSeconds    = 3600
+	twoFactorTokenTTLSeconds = 300
+)
 
 var (
 	errInvalidRequestBody         = errors.New("invalid request body")
@@ -31,6 +36,10 @@ var (
 	errAuthHeaderRequired         = errors.New("authorization header required")
 	errInvalidAuthHeaderFormat    = errors.New("invalid authorization header format")
 	errInvalidRefreshToken        = errors.New("invalid refresh token")
+	errTwoFactorFieldsRequired    = errors.New("twoFactorToken and code are required")
+	errInvalidTwoFactorCode       = errors.New("invalid or expired two-factor code")
+	errLoginLocked                = errors.New("too many failed login attempts, try again later")
+	errFailedToRevokeToken        = errors.New("failed to revoke token")
 	errInvalidEmailFormat         = errors.New("invalid email format")
 	errFailedToProcessReset       = errors.New("failed to process password reset")
 	errTokenRequired              = errors.New("token is required")
@@ -61,6 +70,11 @@ type verifyCodeRequest struct {
 	Code string `json:"code"`
 }
 
+type verifyTwoFactorRequest struct {
+	TwoFactorToken string `json:"twoFactorToken"`
+	Code           string `json:"code"`
+}
+
 type emailRequest struct {
 	Email string `json:"email"`
 }
@@ -134,12 +148,19 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	_, err := h.authService.LoginUser(ctx, input.Email, input.Password)
+	result, err := h.authService.LoginUser(ctx, input.Email, input.Password, sessionClient(r))
 	if err != nil {
 		log.Printf("Login error: %v", err)
+		if writeLoginLocked(w, err) {
+			return
+		}
 		resp.Error(w, http.StatusUnauthorized, errInvalidCredentials)
 		return
 	}
+	if result.TwoFactorToken != "" {
+		writeTwoFactorRequired(w, result.TwoFactorToken)
+		return
+	}
 
 	response := map[string]interface{}{
 		"message": "Verification code sent to your email. Please check your inbox.",
@@ -162,13 +183,61 @@ func (h *AuthHandler) VerifyCode(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	user, accessToken, err := h.authService.VerifyCode(ctx, input.Code)
+	user, tokens, err := h.authService.VerifyCode(ctx, input.Code, sessionClient(r))
 	if err != nil {
 		log.Printf("VerifyCode error: %v", err)
 		resp.Error(w, http.StatusUnauthorized, errInvalidOrExpiredCode)
 		return
 	}
 
+	writeLoggedIn(w, user, tokens)
+}
+
+// VerifyTwoFactor завершает вход пользователя с 2FA: токен из /auth/login и код из приложения
+// или код восстановления. После неверного кода нужно снова войти по паролю.
+func (h *AuthHandler) VerifyTwoFactor(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+	var input verifyTwoFactorRequest
+	if !decodeJSON(w, r, &input) {
+		return
+	}
+	input.TwoFactorToken = strings.TrimSpace(input.TwoFactorToken)
+	input.Code = strings.TrimSpace(input.Code)
+	if input.TwoFactorToken == "" || input.Code == "" {
+		resp.Error(w, http.StatusBadRequest, errTwoFactorFieldsRequired)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
+	defer cancel()
+
+	user, tokens, err := h.authService.VerifyTwoFactor(ctx, input.TwoFactorToken, input.Code, sessionClient(r))
+	if err != nil {
+		log.Printf("VerifyTwoFactor error: %v", err)
+		if writeLoginLocked(w, err) {
+			return
+		}
+		resp.Error(w, http.StatusUnauthorized, errInvalidTwoFactorCode)
+		return
+	}
+
+	writeLoggedIn(w, user, tokens)
+}
+
+// writeLoginLocked отвечает 429 с Retry-After, если вход заблокирован после неудачных попыток.
+func writeLoginLocked(w http.ResponseWriter, err error) bool {
+	var locked *services.LoginLockedError
+	if !errors.As(err, &locked) {
+		return false
+	}
+	retryAfter := int(math.Ceil(time.Until(locked.Until).Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(max(retryAfter, 1)))
+	resp.Error(w, http.StatusTooManyRequests, errLoginLocked)
+	return true
+}
+
+// writeLoggedIn отвечает на успешный вход: пользователь и пара токенов.
+func writeLoggedIn(w http.ResponseWriter, user *domain.User, tokens *services.AuthTokens) {
 	response := map[string]interface{}{
 		"user": map[string]interface{}{
 			"id":         user.ID,
@@ -176,12 +245,22 @@ func (h *AuthHandler) VerifyCode(w http.ResponseWriter, r *http.Request) {
 			"username":   user.Username,
 			"isVerified": user.IsVerified,
 		},
-		"accessToken": accessToken,
-		"expiresIn":   accessTokenTTLSeconds,
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    accessTokenTTLSeconds,
 	}
 	resp.Success(w, response)
 }
 
+// writeTwoFactorRequired отвечает на вход по паролю пользователя с 2FA: токены выдаст /auth/2fa/verify.
+func writeTwoFactorRequired(w http.ResponseWriter, twoFactorToken string) {
+	resp.Success(w, map[string]interface{}{
+		"twoFactorRequired": true,
+		"twoFactorToken":    twoFactorToken,
+		"expiresIn":         twoFactorTokenTTLSeconds,
+	})
+}
+
 func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	authHeader := strings.TrimSpace(r.Header.Get("Authorization"))
 	if authHeader == "" {
@@ -198,16 +277,20 @@ func (h *AuthHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(r.Context(), 10*time.Second)
 	defer cancel()
 
-	accessToken, err := h.authService.RefreshToken(ctx, refreshToken)
+	// Refresh-токен одноразовый: в ответе новый, старый больше не примут
+	tokens, err := h.authService.RefreshToken(ctx, refreshToken, sessionClient(r))
 	if err != nil {
-		log.Printf("RefreshToken error: %v", err)
+		if !errors.Is(err, repositories.ErrRefreshTokenInvalid) && !errors.Is(err, repositories.ErrRefreshTokenReused) {
+			log.Printf("RefreshToken error: %v", err)
+		}
 		resp.Error(w, http.StatusUnauthorized, errInvalidRefreshToken)
 		return
 	}
 
 	response := map[string]interface{}{
-		"accessToken": accessToken,
-		"expiresIn":   accessTokenTTLSeconds,
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
+		"expiresIn":    accessTokenTTLSeconds,
 	}
 	resp.Success(w, response)
 }
@@ -224,7 +307,11 @@ func (h *AuthHandler) Logout(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	refreshToken := strings.TrimSpace(authHeader[len(prefix):])
-	h.authService.RevokeToken(refreshToken)
+	if err := h.authService.RevokeToken(r.Context(), refreshToken); err != nil {
+		log.Printf("Logout error: %v", err)
+		resp.Error(w, http.StatusInternalServerError, errFailedToRevokeToken)
+		return
+	}
 
 	response := map[string]interface{}{
 		"message": "Successfully logged out",
@@ -293,6 +380,7 @@ func (h *AuthHandler) RegisterRoutes(r *mux.Router) {
 	auth.HandleFunc("/register", h.Register).Methods(http.MethodPost)
 	auth.HandleFunc("/login", h.Login).Methods(http.MethodPost)
 	auth.HandleFunc("/verify", h.VerifyCode).Methods(http.MethodPost)
+	auth.HandleFunc("/2fa/verify", h.VerifyTwoFactor).Methods(http.MethodPost)
 	auth.HandleFunc("/forgot-password", h.ForgotPassword).Methods(http.MethodPost)
 	auth.HandleFunc("/reset-password", h.ResetPassword).Methods(http.MethodPost)
 	auth.HandleFunc("/refresh", h.RefreshToken).Methods(http.MethodPost)
@@ -413,8 +501,20 @@ func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// 3. Генерируем access/refresh токены
-	accessToken, refreshToken, err := h.authService.GenerateTokens(user.ID)
+	// 3. С включённой 2FA токены выдаст /auth/2fa/verify
+	twoFactorToken, err := h.authService.TwoFactorChallenge(r.Context(), user.ID)
+	if err != nil {
+		log.Printf("GoogleLogin two-factor error: %v", err)
+		resp.Error(w, http.StatusInternalServerError, errors.New("token generation failed"))
+		return
+	}
+	if twoFactorToken != "" {
+		writeTwoFactorRequired(w, twoFactorToken)
+		return
+	}
+
+	// 4. Генерируем access/refresh токены
+	tokens, err := h.authService.GenerateTokens(r.Context(), user.ID, sessionClient(r))
 	if err != nil {
 		log.Printf("GoogleLogin token error: %v", err)
 		resp.Error(w, http.StatusInternalServerError, errors.New("token generation failed"))
@@ -428,8 +528,8 @@ func (h *AuthHandler) GoogleLogin(w http.ResponseWriter, r *http.Request) {
 			"username":   user.Username,
 			"isVerified": true, // OAuth = verified
 		},
-		"accessToken":  accessToken,
-		"refreshToken": refreshToken,
+		"accessToken":  tokens.AccessToken,
+		"refreshToken": tokens.RefreshToken,
 		"expiresIn":    accessTokenTTLSeconds,
 	}
 
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// ChallengeHandler handles check-in streaks and monthly challenges.
+type ChallengeHandler struct {
+	challengeService *services.ChallengeService
+	logger           *zap.Logger
+}
+
+// NewChallengeHandler creates a new streak and challenge handler.
+func NewChallengeHandler(
+	challengeService *services.ChallengeService,
+	logger *zap.Logger,
+) *ChallengeHandler {
+	return &ChallengeHandler{
+		challengeService: challengeService,
+		logger:           logger,
+	}
+}
+
+// GetStreak godoc
+// @Summary      Серия ежедневных заходов
+// @Description  Дни подряд (UTC) с просмотренной или оценённой рекомендацией. Пропущенный день покрывается
+// @Description  заморозкой, если она есть; заморозка начисляется за каждые 7 дней серии (не больше 2).
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  domain.Streak
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/streak [get]
+func (h *ChallengeHandler) GetStreak(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	streak, err := h.challengeService.GetStreak(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to get streak", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to get streak"))
+		return
+	}
+
+	resp.Success(w, streak)
+}
+
+// ListChallenges godoc
+// @Summary      Испытания месяца
+// @Description  Активные испытания текущего месяца (UTC) с прогрессом пользователя.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   domain.ChallengeProgress
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/challenges [get]
+func (h *ChallengeHandler) ListChallenges(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	challenges, err := h.challengeService.ListChallenges(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list challenges", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to list challenges"))
+		return
+	}
+
+	resp.Success(w, challenges)
+}
+
+// authorize сверяет {id} из пути с пользователем из токена: прогресс видит только владелец.
+func (h *ChallengeHandler) authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	requestedUserID, err := parseUserID(mux.Vars(r))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, false
+	}
+
+	if requestedUserID != authUserID {
+		h.logger.Warn("User tried to access another user's challenges",
+			zap.Int("requested_user_id", requestedUserID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only access own challenges"))
+		return 0, false
+	}
+	return requestedUserID, true
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"mime"
 	"net/http"
 	"strconv"
 	"time"
@@ -12,25 +14,43 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"outfitstyle/server/internal/core/application/catalog"
 	"outfitstyle/server/internal/core/application/services"
 	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/middleware"
 	"outfitstyle/server/internal/pkg/http"
 )
 
 // ClothingItemHandler handles clothing item-related HTTP requests
 type ClothingItemHandler struct {
 	clothingItemService *services.ClothingItemService
+	photoService        *services.PhotoService
+	events              services.EventPublisher
+	achievements        *services.AchievementService
 	logger              *zap.Logger
 }
 
 // NewClothingItemHandler creates a new clothing item handler
-func NewClothingItemHandler(clothingItemService *services.ClothingItemService, logger *zap.Logger) *ClothingItemHandler {
+func NewClothingItemHandler(clothingItemService *services.ClothingItemService, photoService *services.PhotoService, logger *zap.Logger) *ClothingItemHandler {
 	return &ClothingItemHandler{
 		clothingItemService: clothingItemService,
+		photoService:        photoService,
 		logger:              logger,
 	}
 }
 
+// WithEvents подключает публикацию событий wardrobe.item_added и wardrobe.item_removed.
+func (h *ClothingItemHandler) WithEvents(events services.EventPublisher) *ClothingItemHandler {
+	h.events = events
+	return h
+}
+
+// WithAchievements включает проверку достижений при пополнении гардероба.
+func (h *ClothingItemHandler) WithAchievements(achievements *services.AchievementService) *ClothingItemHandler {
+	h.achievements = achievements
+	return h
+}
+
 // GetWardrobeItems retrieves user's wardrobe items
 func (h *ClothingItemHandler) GetWardrobeItems(w http.ResponseWriter, r *http.Request) {
 	userID, err := strconv.Atoi(mux.Vars(r)["user_id"])
@@ -52,6 +72,9 @@ func (h *ClothingItemHandler) GetWardrobeItems(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// Собственные фото пользователя — подписанные ссылки с ограниченным сроком
+	h.photoService.AttachURLs(ctx, userID, items)
+
 	response := map[string]interface{}{
 		"items": items,
 		"count": len(items),
@@ -110,9 +133,17 @@ func (h *ClothingItemHandler) AddItemToWardrobe(w http.ResponseWriter, r *http.R
 		return
 	}
 
-	http.Success(w, map[string]string{
+	if h.events != nil {
+		h.events.Publish(ctx, userID, domain.EventWardrobeItemAdded, map[string]interface{}{"item_id": itemID})
+	}
+
+	response := map[string]interface{}{
 		"message": "Item added to wardrobe successfully",
-	})
+	}
+	if h.achievements != nil {
+		response["achievements_unlocked"] = h.achievements.OnWardrobeItemAdded(ctx, userID, itemID)
+	}
+	http.Success(w, response)
 }
 
 // RemoveItemFromWardrobe removes an item from user's wardrobe
@@ -142,17 +173,56 @@ func (h *ClothingItemHandler) RemoveItemFromWardrobe(w http.ResponseWriter, r *h
 		return
 	}
 
+	if h.events != nil {
+		h.events.Publish(ctx, userID, domain.EventWardrobeItemRemoved, map[string]interface{}{"item_id": itemID})
+	}
+
 	http.Success(w, map[string]string{
 		"message": "Item removed from wardrobe successfully",
 	})
 }
 
-// CreateClothingItem creates a new clothing item
+// CreateClothingItem creates a new clothing item.
+// Принимает JSON или multipart/form-data с полями item (JSON вещи) и необязательным photo:
+// по фото определяется base_colour, если он не задан.
 func (h *ClothingItemHandler) CreateClothingItem(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	var item domain.ClothingItem
-	if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
+	var (
+		item       domain.ClothingItem
+		suggestion *catalog.ColourSuggestion
+	)
+
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+		if err := parsePhotoForm(w, r, h.photoService.MaxBytes()); err != nil {
+			writePhotoFormError(w, err)
+			return
+		}
+		defer r.MultipartForm.RemoveAll()
+
+		if err := json.Unmarshal([]byte(r.FormValue("item")), &item); err != nil {
+			http.Error(w, "invalid item JSON", http.StatusBadRequest)
+			return
+		}
+
+		photo, err := formPhoto(r, h.photoService.MaxBytes())
+		switch {
+		case err == nil:
+			s, err := h.detectColour(photo)
+			if err != nil {
+				writePhotoFormError(w, err)
+				return
+			}
+			suggestion = &s
+			// Явно указанный цвет не перетираем
+			if item.BaseColour == "" && s.Confident() {
+				item.BaseColour = s.BaseColour
+			}
+		case !errors.Is(err, errNoPhoto):
+			writePhotoFormError(w, err)
+			return
+		}
+	} else if err := json.NewDecoder(r.Body).Decode(&item); err != nil {
 		http.Error(w, "invalid JSON", http.StatusBadRequest)
 		return
 	}
@@ -170,11 +240,54 @@ func (h *ClothingItemHandler) CreateClothingItem(w http.ResponseWriter, r *http.
 		return
 	}
 
-	http.Success(w, map[string]interface{}{
+	response := map[string]interface{}{
 		"item": createdItem,
+	}
+	if suggestion != nil {
+		response["colour_suggestion"] = suggestion
+	}
+
+	http.Success(w, response)
+}
+
+// DetectColour определяет base_colour по фото вещи, чтобы клиент мог заполнить форму заранее.
+func (h *ClothingItemHandler) DetectColour(w http.ResponseWriter, r *http.Request) {
+	if err := parsePhotoForm(w, r, h.photoService.MaxBytes()); err != nil {
+		writePhotoFormError(w, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	photo, err := formPhoto(r, h.photoService.MaxBytes())
+	if err != nil {
+		writePhotoFormError(w, err)
+		return
+	}
+
+	suggestion, err := h.detectColour(photo)
+	if err != nil {
+		writePhotoFormError(w, err)
+		return
+	}
+
+	http.Success(w, map[string]interface{}{
+		"colour_suggestion": suggestion,
+		"confident":         suggestion.Confident(),
 	})
 }
 
+func (h *ClothingItemHandler) detectColour(photo []byte) (catalog.ColourSuggestion, error) {
+	if _, err := h.photoService.Check(photo); err != nil {
+		return catalog.ColourSuggestion{}, err
+	}
+	suggestion, err := catalog.DetectBaseColour(photo)
+	if err != nil {
+		// Сигнатура JPEG/PNG есть, но картинка не декодируется
+		return catalog.ColourSuggestion{}, services.ErrUnsupportedPhotoType
+	}
+	return suggestion, nil
+}
+
 // GetClothingItem retrieves a single clothing item by ID
 func (h *ClothingItemHandler) GetClothingItem(w http.ResponseWriter, r *http.Request) {
 	itemID, err := strconv.Atoi(mux.Vars(r)["id"])
@@ -200,6 +313,13 @@ func (h *ClothingItemHandler) GetClothingItem(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Фото привязаны к гардеробу, поэтому показываем их только владельцу
+	if userID, ok := middleware.GetUserIDFromContext(r.Context()); ok {
+		withPhoto := []domain.ClothingItem{item}
+		h.photoService.AttachURLs(ctx, userID, withPhoto)
+		item = withPhoto[0]
+	}
+
 	http.Success(w, map[string]interface{}{
 		"item": item,
 	})
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// DigestHandler handles the morning outfit digest subscription.
+type DigestHandler struct {
+	digestService *services.DigestService
+	logger        *zap.Logger
+}
+
+// NewDigestHandler creates a new digest subscription handler.
+func NewDigestHandler(
+	digestService *services.DigestService,
+	logger *zap.Logger,
+) *DigestHandler {
+	return &DigestHandler{
+		digestService: digestService,
+		logger:        logger,
+	}
+}
+
+// GetDigest godoc
+// @Summary      Подписка на утренний дайджест
+// @Description  Время отправки, часовой пояс и момент следующего письма.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  domain.DigestSubscription
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/digest [get]
+func (h *DigestHandler) GetDigest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	sub, err := h.digestService.GetSubscription(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "failed to get digest subscription")
+		return
+	}
+
+	resp.Success(w, sub)
+}
+
+// SubscribeDigest godoc
+// @Summary      Подписаться на утренний дайджест
+// @Description  Каждый день в send_time (ЧЧ:ММ) по местному времени приходит письмо с погодой, образом на день,
+// @Description  предупреждениями и запланированными образами — для места по умолчанию или города из профиля.
+// @Description  timezone (IANA) можно не передавать, если он известен по месту или городу. Повторный вызов меняет время.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                               true  "User ID"
+// @Param        body  body      services.DigestSubscriptionInput  true  "Время отправки"
+// @Success      200   {object}  domain.DigestSubscription
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      404   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/digest [put]
+func (h *DigestHandler) SubscribeDigest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	defer r.Body.Close()
+
+	var in services.DigestSubscriptionInput
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	sub, err := h.digestService.Subscribe(r.Context(), userID, in)
+	if err != nil {
+		h.writeError(w, err, "failed to subscribe to digest")
+		return
+	}
+
+	resp.Success(w, sub)
+}
+
+// UnsubscribeDigest godoc
+// @Summary      Отписаться от утреннего дайджеста
+// @Description  Выключает дайджест; время и часовой пояс сохраняются для повторной подписки.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  domain.DigestSubscription
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/digest [delete]
+func (h *DigestHandler) UnsubscribeDigest(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	sub, err := h.digestService.Unsubscribe(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "failed to unsubscribe from digest")
+		return
+	}
+
+	resp.Success(w, sub)
+}
+
+// authorize сверяет {id} из пути с пользователем из токена: подпиской управляет только владелец.
+func (h *DigestHandler) authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	requestedUserID, err := parseUserID(mux.Vars(r))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, false
+	}
+
+	if requestedUserID != authUserID {
+		h.logger.Warn("User tried to access another user's digest",
+			zap.Int("requested_user_id", requestedUserID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only access own digest"))
+		return 0, false
+	}
+	return requestedUserID, true
+}
+
+// writeError переводит ошибки сервиса дайджеста в HTTP-статусы.
+func (h *DigestHandler) writeError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidDigest):
+		resp.Error(w, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrDigestNotFound), errors.Is(err, services.ErrUserNotFound):
+		resp.Error(w, http.StatusNotFound, err)
+	default:
+		h.logger.Error("Digest subscription error", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New(msg))
+	}
+}
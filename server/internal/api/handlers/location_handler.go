@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/pkg/gazetteer"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// LocationHandler отдаёт подсказки городов из встроенного справочника.
+type LocationHandler struct {
+	places *gazetteer.Gazetteer
+	logger *zap.Logger
+}
+
+// NewLocationHandler creates a new location search handler.
+func NewLocationHandler(places *gazetteer.Gazetteer, logger *zap.Logger) *LocationHandler {
+	return &LocationHandler{
+		places: places,
+		logger: logger,
+	}
+}
+
+// SearchLocations godoc
+// @Summary      Поиск города
+// @Description  Автодополнение и поиск с опечатками по справочнику городов (названия на русском и английском).
+// @Description  id найденного города передаётся в /recommendations как location_id.
+// @Tags         locations
+// @Produce      json
+// @Param        q      query  string true  "Начало названия или название с опечаткой" example(моск)
+// @Param        limit  query  int    false "Сколько подсказок вернуть (1..50, по умолчанию 10)"
+// @Success      200  {array}   gazetteer.Match
+// @Failure      400  {object}  map[string]string
+// @Router       /locations/search [get]
+func (h *LocationHandler) SearchLocations(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query().Get("q")
+	if gazetteer.Normalize(q) == "" {
+		resp.Error(w, http.StatusBadRequest, errors.New("q parameter is required"))
+		return
+	}
+
+	limit := gazetteer.DefaultLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 || l > gazetteer.MaxLimit {
+			resp.Error(w, http.StatusBadRequest, errors.New("limit must be between 1 and 50"))
+			return
+		}
+		limit = l
+	}
+
+	matches := h.places.Search(q, limit)
+	if matches == nil {
+		matches = []gazetteer.Match{}
+	}
+	resp.Success(w, matches)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// photoFormField — имя поля multipart-формы с файлом.
+const photoFormField = "photo"
+
+// PhotoHandler handles wardrobe item photo uploads.
+type PhotoHandler struct {
+	photoService *services.PhotoService
+	logger       *zap.Logger
+}
+
+// NewPhotoHandler creates a new photo handler.
+func NewPhotoHandler(photoService *services.PhotoService, logger *zap.Logger) *PhotoHandler {
+	return &PhotoHandler{
+		photoService: photoService,
+		logger:       logger,
+	}
+}
+
+type photoResponse struct {
+	ItemID       int64  `json:"item_id"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	PhotoURL     string `json:"photo_url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+}
+
+// UploadPhoto godoc
+// @Summary      Загрузить фото вещи из гардероба
+// @Description  Принимает JPEG или PNG (multipart, поле photo), проверяет тип по содержимому и размер, строит превью. Заменяет прежнее фото. Ссылки в ответе подписаны и истекают.
+// @Tags         wardrobe
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        user_id  path      int   true  "User ID"
+// @Param        item_id  path      int   true  "Clothing item ID"
+// @Param        photo    formData  file  true  "Фото (JPEG/PNG)"
+// @Success      201  {object}  photoResponse
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      413  {object}  map[string]string
+// @Failure      415  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /wardrobe/users/{user_id}/items/{item_id}/photo [post]
+func (h *PhotoHandler) UploadPhoto(w http.ResponseWriter, r *http.Request) {
+	userID, itemID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	if err := parsePhotoForm(w, r, h.photoService.MaxBytes()); err != nil {
+		writePhotoFormError(w, err)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	data, err := formPhoto(r, h.photoService.MaxBytes())
+	if err != nil {
+		writePhotoFormError(w, err)
+		return
+	}
+
+	photo, err := h.photoService.Upload(r.Context(), userID, itemID, data)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrPhotoTooLarge), errors.Is(err, services.ErrUnsupportedPhotoType):
+			writePhotoFormError(w, err)
+		case errors.Is(err, services.ErrItemNotInWardrobe):
+			resp.Error(w, http.StatusNotFound, err)
+		default:
+			h.logger.Error("Failed to upload photo", zap.Error(err), zap.Int("user_id", userID), zap.Int64("item_id", itemID))
+			resp.Error(w, http.StatusInternalServerError, errors.New("failed to upload photo"))
+		}
+		return
+	}
+
+	photoURL, thumbURL, err := h.photoService.URLs(r.Context(), photo)
+	if err != nil {
+		h.logger.Error("Failed to sign photo URL", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to upload photo"))
+		return
+	}
+
+	resp.JSONResponse(w, http.StatusCreated, photoResponse{
+		ItemID:       photo.ItemID,
+		ContentType:  photo.ContentType,
+		SizeBytes:    photo.SizeBytes,
+		Width:        photo.Width,
+		Height:       photo.Height,
+		PhotoURL:     photoURL,
+		ThumbnailURL: thumbURL,
+	})
+}
+
+// DeletePhoto godoc
+// @Summary      Удалить фото вещи из гардероба
+// @Tags         wardrobe
+// @Produce      json
+// @Param        user_id  path      int  true  "User ID"
+// @Param        item_id  path      int  true  "Clothing item ID"
+// @Success      200  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /wardrobe/users/{user_id}/items/{item_id}/photo [delete]
+func (h *PhotoHandler) DeletePhoto(w http.ResponseWriter, r *http.Request) {
+	userID, itemID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.photoService.Delete(r.Context(), userID, itemID); err != nil {
+		h.logger.Error("Failed to delete photo", zap.Error(err), zap.Int("user_id", userID), zap.Int64("item_id", itemID))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to delete photo"))
+		return
+	}
+
+	resp.Success(w, map[string]string{
+		"message": "Photo deleted successfully",
+	})
+}
+
+var (
+	// errInvalidPhotoForm — тело не multipart или файл не читается.
+	errInvalidPhotoForm = errors.New("invalid multipart form")
+	// errNoPhoto — в форме нет поля photo.
+	errNoPhoto = errors.New("photo file is required")
+)
+
+// parsePhotoForm разбирает multipart-тело, ограничивая его размером фото с запасом на остальные поля.
+func parsePhotoForm(w http.ResponseWriter, r *http.Request, maxBytes int64) error {
+	r.Body = http.MaxBytesReader(w, r.Body, maxBytes+1<<20)
+	if err := r.ParseMultipartForm(maxBytes); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return services.ErrPhotoTooLarge
+		}
+		return errInvalidPhotoForm
+	}
+	return nil
+}
+
+// formPhoto читает файл из поля photo; если поля нет — errNoPhoto.
+func formPhoto(r *http.Request, maxBytes int64) ([]byte, error) {
+	file, _, err := r.FormFile(photoFormField)
+	if err != nil {
+		if errors.Is(err, http.ErrMissingFile) {
+			return nil, errNoPhoto
+		}
+		return nil, errInvalidPhotoForm
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		return nil, errInvalidPhotoForm
+	}
+	return data, nil
+}
+
+func writePhotoFormError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, services.ErrPhotoTooLarge):
+		resp.Error(w, http.StatusRequestEntityTooLarge, err)
+	case errors.Is(err, services.ErrUnsupportedPhotoType):
+		resp.Error(w, http.StatusUnsupportedMediaType, err)
+	default:
+		resp.Error(w, http.StatusBadRequest, err)
+	}
+}
+
+// authorize разбирает путь и проверяет, что пользователь работает со своим гардеробом.
+func (h *PhotoHandler) authorize(w http.ResponseWriter, r *http.Request) (int, int64, bool) {
+	vars := mux.Vars(r)
+	userID, err := strconv.Atoi(vars["user_id"])
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, 0, false
+	}
+	itemID, err := strconv.ParseInt(vars["item_id"], 10, 64)
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid item ID"))
+		return 0, 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, 0, false
+	}
+	if authUserID != userID {
+		h.logger.Warn("User tried to change another user's wardrobe photo",
+			zap.Int("requested_user_id", userID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only manage own wardrobe"))
+		return 0, 0, false
+	}
+	return userID, itemID, true
+}
@@ -3,10 +3,13 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -14,10 +17,12 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"go.uber.org/zap"
 
+	"outfitstyle/server/internal/core/application/alerts"
 	"outfitstyle/server/internal/core/application/services"
 	"outfitstyle/server/internal/core/domain"
 	"outfitstyle/server/internal/infrastructure/middleware"
 	"outfitstyle/server/internal/infrastructure/external"
+	"outfitstyle/server/internal/pkg/gazetteer"
 	resp "outfitstyle/server/internal/pkg/http"
 )
 
@@ -37,30 +42,55 @@ var (
 // RecommendationHandler handles recommendation-related HTTP requests.
 type RecommendationHandler struct {
 	recommendationService *services.RecommendationService
-	weatherService        *external.WeatherService
+	weatherService        external.WeatherProvider
+	locationService       *services.UserLocationService
+	alertService          *services.WeatherAlertService
+	places                *gazetteer.Gazetteer
+	achievements          *services.AchievementService
 	logger                *zap.Logger
 }
 
 // NewRecommendationHandler creates a new recommendation handler.
 func NewRecommendationHandler(
 	recommendationService *services.RecommendationService,
-	weatherService *external.WeatherService,
+	weatherService external.WeatherProvider,
+	locationService *services.UserLocationService,
+	alertService *services.WeatherAlertService,
+	places *gazetteer.Gazetteer,
 	logger *zap.Logger,
 ) *RecommendationHandler {
 	return &RecommendationHandler{
 		recommendationService: recommendationService,
 		weatherService:        weatherService,
+		locationService:       locationService,
+		alertService:          alertService,
+		places:                places,
 		logger:                logger,
 	}
 }
 
+// WithAchievements включает проверку достижений при выдаче и оценке рекомендаций.
+func (h *RecommendationHandler) WithAchievements(achievements *services.AchievementService) *RecommendationHandler {
+	h.achievements = achievements
+	return h
+}
+
 // GetRecommendations godoc
 // @Summary      Получить рекомендацию по погоде
-// @Description  Возвращает комплект одежды для заданного города и пользователя
+// @Description  Возвращает комплект одежды для заданного места и пользователя. Место — location_id из /locations/search, координаты lat/lon или город; в ответе location — найденное название.
+// @Description  Если место не указано, берётся сохранённое место пользователя по умолчанию (или saved_location_id).
+// @Description  Если провайдер не знает город, 404 содержит suggestions — похожие города из справочника.
+// @Description  alerts — предупреждения об опасной погоде (мороз, жара, гроза, снегопад, ветер); их защитные вещи уже заменили в items вещи той же категории.
+// @Description  id — ID сохранённой рекомендации; рекомендация и событие recommendation.created сохраняются до ответа.
+// @Description  achievements_unlocked — достижения, впервые открытые этой рекомендацией.
 // @Tags         recommendations
 // @Accept       json
 // @Produce      json
-// @Param        city     query  string true  "Город"                    example(Moscow)
+// @Param        location_id query string false "id города из /locations/search"         example(ru-moscow)
+// @Param        city     query  string false "Город (если не заданы location_id и lat/lon)" example(Moscow)
+// @Param        lat      query  number false "Широта, -90..90 (вместе с lon)"              example(55.7558)
+// @Param        lon      query  number false "Долгота, -180..180 (вместе с lat)"           example(37.6173)
+// @Param        saved_location_id query int false "id сохранённого места из /users/{id}/locations"
 // @Param        source   query  string false "Источник вещей: wardrobe (гардероб), catalog (каталог), mixed (оба). По умолчанию mixed."
 // @Success      200  {object}  map[string]interface{}
 // @Failure      400  {object}  map[string]string
@@ -86,9 +116,13 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 
 	// ---------------- ПАРАМЕТРЫ ЗАПРОСА ----------------
 
-	city := r.URL.Query().Get("city")
-	if city == "" {
-		resp.Error(w, http.StatusBadRequest, fmt.Errorf("city parameter is required"))
+	loc, status, err := h.requestLocation(r.Context(), ctxUserID, r.URL.Query())
+	if err != nil {
+		if status == http.StatusInternalServerError {
+			h.logger.Error("Failed to get saved location", zap.Error(err), zap.Int("user_id", ctxUserID))
+			err = fmt.Errorf("failed to get saved location")
+		}
+		resp.Error(w, status, err)
 		return
 	}
 
@@ -104,15 +138,23 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 	defer cancel()
 
 	h.logger.Info("Get recommendations request",
-		zap.String("city", city),
+		loc.logField(),
 		zap.Int("user_id", userID),
 		zap.String("source", source),
 	)
 
 	// ---------------- ПОГОДА ----------------
 
-	weather, err := h.weatherService.GetWeather(ctxWithTimeout, city)
+	weather, err := loc.fetch(ctxWithTimeout, h.weatherService)
 	if err != nil {
+		if errors.Is(err, external.ErrCityNotFound) {
+			resp.JSONResponse(w, http.StatusNotFound, map[string]interface{}{
+				"error":       external.ErrCityNotFound.Error(),
+				"suggestions": loc.suggestions(h.places),
+			})
+			recommendationsTotal.WithLabelValues(strconv.Itoa(userID), "error_city").Inc()
+			return
+		}
 		h.logger.Error("Weather error", zap.Error(err))
 		resp.Error(w, http.StatusServiceUnavailable, fmt.Errorf("failed to get weather data"))
 		recommendationsTotal.WithLabelValues(strconv.Itoa(userID), "error_weather").Inc()
@@ -166,12 +208,18 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 		zap.Bool("ml_powered", recommendation.MLPowered),
 	)
 
-	// Проверка ачивок (асинхронно)
-	go h.checkAchievements(userID, weather)
+	// Предупреждения об опасной погоде — по тому же прогнозу, что и рекомендация
+	weatherAlerts := []alerts.Alert{}
+	if h.alertService != nil {
+		if active := h.alertService.Evaluate(weather.WeatherData); len(active) > 0 {
+			weatherAlerts = active
+		}
+	}
 
 	// ---------------- ОТВЕТ ----------------
 
 	response := map[string]interface{}{
+		"id":              recommendation.ID,
 		"location":        recommendation.Location,
 		"temperature":     recommendation.Temperature,
 		"feels_like":      recommendation.FeelsLike,
@@ -184,17 +232,59 @@ func (h *RecommendationHandler) GetRecommendations(w http.ResponseWriter, r *htt
 		"will_snow":       recommendation.WillSnow,
 		"hourly_forecast": weather.WeatherData.HourlyForecast,
 		"message":         h.getWeatherMessage(recommendation.Temperature),
+		"alerts":          weatherAlerts,
 		"items":           recommendation.Items,
 		"ml_powered":      recommendation.MLPowered,
 		"outfit_score":    outfitScore,
 		"algorithm":       recommendation.Algorithm,
 		"timestamp":       recommendation.Timestamp,
 	}
+	if h.achievements != nil {
+		response["achievements_unlocked"] = h.achievements.OnRecommendation(ctx, recommendation)
+	}
 
 	resp.Success(w, response)
 	recommendationsTotal.WithLabelValues(strconv.Itoa(userID), "success").Inc()
 }
 
+// requestLocation выбирает место для рекомендации: saved_location_id, затем место из query,
+// а если его нет — место пользователя по умолчанию. Вместе с ошибкой возвращает HTTP-статус.
+func (h *RecommendationHandler) requestLocation(ctx context.Context, userID int, q url.Values) (weatherLocation, int, error) {
+	if h.locationService != nil {
+		if idStr := strings.TrimSpace(q.Get("saved_location_id")); idStr != "" {
+			id, err := strconv.Atoi(idStr)
+			if err != nil || id <= 0 {
+				return weatherLocation{}, http.StatusBadRequest, fmt.Errorf("invalid saved_location_id")
+			}
+			saved, err := h.locationService.Get(ctx, userID, id)
+			if err != nil {
+				if errors.Is(err, services.ErrLocationNotFound) {
+					return weatherLocation{}, http.StatusNotFound, err
+				}
+				return weatherLocation{}, http.StatusInternalServerError, err
+			}
+			return savedLocation(saved, h.places), 0, nil
+		}
+	}
+
+	loc, err := parseWeatherLocation(q, h.places)
+	if !errors.Is(err, errNoLocation) || h.locationService == nil {
+		if err != nil {
+			return weatherLocation{}, http.StatusBadRequest, err
+		}
+		return loc, 0, nil
+	}
+
+	saved, err := h.locationService.GetDefault(ctx, userID)
+	if err != nil {
+		return weatherLocation{}, http.StatusInternalServerError, err
+	}
+	if saved == nil {
+		return weatherLocation{}, http.StatusBadRequest, fmt.Errorf("pass location_id, city or lat/lon, or save a default location")
+	}
+	return savedLocation(saved, h.places), 0, nil
+}
+
 // GetRecommendationHistory handles GET /api/v1/recommendations/history
 func (h *RecommendationHandler) GetRecommendationHistory(w http.ResponseWriter, r *http.Request) {
 	// Extract user ID from context (authenticated by middleware)
@@ -265,7 +355,7 @@ func (h *RecommendationHandler) GetRecommendationByID(w http.ResponseWriter, r *
 
 // RateRecommendation godoc
 // @Summary      Оценить рекомендацию
-// @Description  Позволяет пользователю оценить рекомендацию
+// @Description  Позволяет пользователю оценить рекомендацию. achievements_unlocked — впервые открытые достижения.
 // @Tags         recommendations
 // @Accept       json
 // @Produce      json
@@ -328,7 +418,11 @@ func (h *RecommendationHandler) RateRecommendation(w http.ResponseWriter, r *htt
 		return
 	}
 
-	resp.Success(w, map[string]string{"message": "Rating saved successfully"})
+	response := map[string]interface{}{"message": "Rating saved successfully"}
+	if h.achievements != nil {
+		response["achievements_unlocked"] = h.achievements.OnRating(ctx, userID, id, req.Rating)
+	}
+	resp.Success(w, response)
 }
 
 // AddFavorite godoc
@@ -497,17 +591,6 @@ func decodeJSONReq(w http.ResponseWriter, r *http.Request, dst interface{}) bool
 	return true
 }
 
-// checkAchievements checks and unlocks achievements for the user (placeholder).
-func (h *RecommendationHandler) checkAchievements(userID int, weather *domain.ExtendedWeatherData) {
-	// Пример простой логики ачивок:
-	if weather.WeatherData.Temperature < -10 {
-		h.logger.Info("Achievement unlocked: Cold Warrior",
-			zap.Int("user_id", userID),
-			zap.Float64("temp", weather.WeatherData.Temperature),
-		)
-	}
-}
-
 // getWeatherMessage generates a friendly message based on temperature.
 func (h *RecommendationHandler) getWeatherMessage(temp float64) string {
 	switch {
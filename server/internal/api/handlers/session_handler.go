@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// SessionHandler handles the user's login sessions.
+type SessionHandler struct {
+	sessionService *services.SessionService
+	logger         *zap.Logger
+}
+
+// NewSessionHandler creates a new session handler.
+func NewSessionHandler(sessionService *services.SessionService, logger *zap.Logger) *SessionHandler {
+	return &SessionHandler{
+		sessionService: sessionService,
+		logger:         logger,
+	}
+}
+
+// ListSessions godoc
+// @Summary      Активные сессии
+// @Description  Действующие входы пользователя: устройство (User-Agent) и IP последнего refresh,
+// @Description  время последнего использования. Последние использованные — первыми.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   domain.Session
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/sessions [get]
+func (h *SessionHandler) ListSessions(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.ListSessions(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list sessions", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to list sessions"))
+		return
+	}
+
+	resp.Success(w, sessions)
+}
+
+// RevokeSession godoc
+// @Summary      Завершить сессию
+// @Description  Refresh-токен сессии перестаёт действовать; выданный access-токен живёт до своего срока.
+// @Tags         users
+// @Produce      json
+// @Param        id          path      int  true  "User ID"
+// @Param        session_id  path      int  true  "ID сессии"
+// @Success      200         {object}  map[string]string
+// @Failure      400         {object}  map[string]string
+// @Failure      401         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/sessions/{session_id} [delete]
+func (h *SessionHandler) RevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	sessionID, err := strconv.ParseInt(mux.Vars(r)["session_id"], 10, 64)
+	if err != nil || sessionID <= 0 {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid session ID"))
+		return
+	}
+
+	if err := h.sessionService.RevokeSession(r.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, services.ErrSessionNotFound) {
+			resp.Error(w, http.StatusNotFound, err)
+			return
+		}
+		h.logger.Error("Failed to revoke session", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to revoke session"))
+		return
+	}
+
+	resp.Success(w, map[string]string{"message": "Session revoked successfully"})
+}
+
+// authorize сверяет {id} из пути с пользователем из токена: сессиями управляет только владелец.
+func (h *SessionHandler) authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	requestedUserID, err := parseUserID(mux.Vars(r))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, false
+	}
+
+	if requestedUserID != authUserID {
+		h.logger.Warn("User tried to access another user's sessions",
+			zap.Int("requested_user_id", requestedUserID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only access own sessions"))
+		return 0, false
+	}
+	return requestedUserID, true
+}
+
+// sessionClient описывает клиента запроса для списка сессий.
+func sessionClient(r *http.Request) domain.SessionClient {
+	return domain.SessionClient{
+		Device: r.UserAgent(),
+		IP:     resp.ClientIP(r),
+	}
+}
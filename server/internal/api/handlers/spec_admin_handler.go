@@ -0,0 +1,256 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/catalog"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// SpecAdminHandler handles admin CRUD for subcategory_specs.
+type SpecAdminHandler struct {
+	specService *catalog.SpecService
+	logger      *zap.Logger
+}
+
+// NewSpecAdminHandler creates a new subcategory spec admin handler.
+func NewSpecAdminHandler(specService *catalog.SpecService, logger *zap.Logger) *SpecAdminHandler {
+	return &SpecAdminHandler{
+		specService: specService,
+		logger:      logger,
+	}
+}
+
+// RegisterRoutes регистрирует маршруты /admin/subcategory-specs на уже защищённом роутере.
+func (h *SpecAdminHandler) RegisterRoutes(admin *mux.Router) {
+	specs := admin.PathPrefix("/subcategory-specs").Subrouter()
+	specs.HandleFunc("", h.ListSpecs).Methods(http.MethodGet)
+	specs.HandleFunc("", h.CreateSpec).Methods(http.MethodPost)
+	specs.HandleFunc("/{category}/{subcategory}", h.GetSpec).Methods(http.MethodGet)
+	specs.HandleFunc("/{category}/{subcategory}", h.UpdateSpec).Methods(http.MethodPut)
+	specs.HandleFunc("/{category}/{subcategory}", h.DeleteSpec).Methods(http.MethodDelete)
+}
+
+// ListSpecs godoc
+// @Summary      Список норм подкатегорий
+// @Description  Возвращает весь словарь subcategory_specs, по которому работает планировщик.
+// @Tags         admin
+// @Produce      json
+// @Success      200  {array}   domain.SubcategorySpec
+// @Failure      401  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     AdminToken
+// @Router       /admin/subcategory-specs [get]
+func (h *SpecAdminHandler) ListSpecs(w http.ResponseWriter, r *http.Request) {
+	specs, err := h.specService.List(r.Context())
+	if err != nil {
+		h.logger.Error("Failed to list subcategory specs", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to list subcategory specs"))
+		return
+	}
+	resp.Success(w, specs)
+}
+
+// GetSpec godoc
+// @Summary      Получить норму подкатегории
+// @Tags         admin
+// @Produce      json
+// @Param        category     path      string  true  "Category"
+// @Param        subcategory  path      string  true  "Subcategory"
+// @Success      200  {object}  domain.SubcategorySpec
+// @Failure      404  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     AdminToken
+// @Router       /admin/subcategory-specs/{category}/{subcategory} [get]
+func (h *SpecAdminHandler) GetSpec(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	spec, err := h.specService.Get(r.Context(), vars["category"], vars["subcategory"])
+	if err != nil {
+		h.writeError(w, err, nil)
+		return
+	}
+	resp.Success(w, spec)
+}
+
+// CreateSpec godoc
+// @Summary      Создать норму подкатегории
+// @Description  Проверяет норму и добавляет её в словарь. С dry_run=true только возвращает предпросмотр: сколько планов изменится.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        spec     body      domain.SubcategorySpec  true   "Норма"
+// @Param        dry_run  query     bool                    false  "Только предпросмотр"
+// @Success      200  {object}  catalog.SpecImpact
+// @Success      201  {object}  catalog.SpecImpact
+// @Failure      400  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Security     AdminToken
+// @Router       /admin/subcategory-specs [post]
+func (h *SpecAdminHandler) CreateSpec(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r)
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var spec domain.SubcategorySpec
+	if err := decodeStrict(r, &spec); err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid JSON"))
+		return
+	}
+
+	impact, err := h.specService.Create(r.Context(), spec, dryRun)
+	if err != nil {
+		h.writeError(w, err, &impact)
+		return
+	}
+
+	if impact.Applied {
+		h.logger.Info("Subcategory spec created",
+			zap.String("category", spec.Category),
+			zap.String("subcategory", spec.Subcategory),
+			zap.Int("plans_changed", impact.Plans))
+		resp.JSONResponse(w, http.StatusCreated, impact)
+		return
+	}
+	resp.Success(w, impact)
+}
+
+// UpdateSpec godoc
+// @Summary      Обновить норму подкатегории
+// @Description  Меняет warmth_min, температурный диапазон и погодные флаги. С dry_run=true только возвращает предпросмотр: сколько вещей и планов затронет изменение.
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param        category     path      string                  true   "Category"
+// @Param        subcategory  path      string                  true   "Subcategory"
+// @Param        spec         body      domain.SubcategorySpec  true   "Норма"
+// @Param        dry_run      query     bool                    false  "Только предпросмотр"
+// @Success      200  {object}  catalog.SpecImpact
+// @Failure      400  {object}  map[string]string
+// @Failure      404  {object}  map[string]string
+// @Failure      422  {object}  map[string]interface{}
+// @Security     AdminToken
+// @Router       /admin/subcategory-specs/{category}/{subcategory} [put]
+func (h *SpecAdminHandler) UpdateSpec(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r)
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var spec domain.SubcategorySpec
+	if err := decodeStrict(r, &spec); err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid JSON"))
+		return
+	}
+
+	// Ключ берём из пути: переименовать подкатегорию через тело нельзя
+	vars := mux.Vars(r)
+	spec.Category = vars["category"]
+	spec.Subcategory = vars["subcategory"]
+
+	impact, err := h.specService.Update(r.Context(), spec, dryRun)
+	if err != nil {
+		h.writeError(w, err, &impact)
+		return
+	}
+
+	if impact.Applied {
+		h.logger.Info("Subcategory spec updated",
+			zap.String("category", spec.Category),
+			zap.String("subcategory", spec.Subcategory),
+			zap.Int64("items", impact.Items),
+			zap.Int("plans_changed", impact.Plans))
+	}
+	resp.Success(w, impact)
+}
+
+// DeleteSpec godoc
+// @Summary      Удалить норму подкатегории
+// @Description  Удаляет норму, если на неё не ссылается ни одна вещь каталога (ON DELETE RESTRICT). С dry_run=true только возвращает предпросмотр.
+// @Tags         admin
+// @Produce      json
+// @Param        category     path      string  true   "Category"
+// @Param        subcategory  path      string  true   "Subcategory"
+// @Param        dry_run      query     bool    false  "Только предпросмотр"
+// @Success      200  {object}  catalog.SpecImpact
+// @Failure      404  {object}  map[string]string
+// @Failure      409  {object}  map[string]interface{}
+// @Security     AdminToken
+// @Router       /admin/subcategory-specs/{category}/{subcategory} [delete]
+func (h *SpecAdminHandler) DeleteSpec(w http.ResponseWriter, r *http.Request) {
+	dryRun, err := parseDryRun(r)
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, err)
+		return
+	}
+
+	vars := mux.Vars(r)
+	impact, err := h.specService.Delete(r.Context(), vars["category"], vars["subcategory"], dryRun)
+	if err != nil {
+		h.writeError(w, err, &impact)
+		return
+	}
+
+	if impact.Applied {
+		h.logger.Info("Subcategory spec deleted",
+			zap.String("category", vars["category"]),
+			zap.String("subcategory", vars["subcategory"]),
+			zap.Int("plans_changed", impact.Plans))
+	}
+	resp.Success(w, impact)
+}
+
+// writeError переводит ошибки сервиса в HTTP-статусы. Для конфликтов отдаёт и предпросмотр,
+// чтобы было видно, сколько вещей мешает удалению.
+func (h *SpecAdminHandler) writeError(w http.ResponseWriter, err error, impact *catalog.SpecImpact) {
+	var verr *catalog.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		resp.JSONResponse(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":    "invalid subcategory spec",
+			"problems": verr.Problems,
+		})
+	case errors.Is(err, repo.ErrSpecNotFound):
+		resp.Error(w, http.StatusNotFound, err)
+	case errors.Is(err, repo.ErrSpecExists):
+		resp.Error(w, http.StatusConflict, err)
+	case errors.Is(err, repo.ErrSpecInUse):
+		resp.JSONResponse(w, http.StatusConflict, map[string]interface{}{
+			"error":  err.Error(),
+			"impact": impact,
+		})
+	default:
+		h.logger.Error("Subcategory spec operation failed", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New("subcategory spec operation failed"))
+	}
+}
+
+func parseDryRun(r *http.Request) (bool, error) {
+	v := r.URL.Query().Get("dry_run")
+	if v == "" {
+		return false, nil
+	}
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, errors.New("dry_run must be a boolean")
+	}
+	return dryRun, nil
+}
+
+func decodeStrict(r *http.Request, v interface{}) error {
+	defer r.Body.Close()
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	return dec.Decode(v)
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// TwoFactorHandler handles enrolment and disabling of TOTP two-factor authentication.
+type TwoFactorHandler struct {
+	twoFactorService *services.TwoFactorService
+	logger           *zap.Logger
+}
+
+// NewTwoFactorHandler creates a new two-factor authentication handler.
+func NewTwoFactorHandler(twoFactorService *services.TwoFactorService, logger *zap.Logger) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorService: twoFactorService,
+		logger:           logger,
+	}
+}
+
+type twoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type twoFactorDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+// GetTwoFactor godoc
+// @Summary      Состояние 2FA
+// @Description  Включена ли двухфакторная аутентификация, начато ли подключение и сколько осталось кодов восстановления.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  domain.TwoFactorStatus
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/2fa [get]
+func (h *TwoFactorHandler) GetTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	status, err := h.twoFactorService.Status(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "failed to get two-factor status")
+		return
+	}
+
+	resp.Success(w, status)
+}
+
+// EnrollTwoFactor godoc
+// @Summary      Начать подключение 2FA
+// @Description  Создаёт секрет TOTP (RFC 6238) и otpauth://-ссылку для QR-кода. 2FA включится
+// @Description  после подтверждения кодом из приложения; повторный вызов заменяет неподтверждённый секрет.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {object}  domain.TwoFactorEnrollment
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      409  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/2fa/enroll [post]
+func (h *TwoFactorHandler) EnrollTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.twoFactorService.Enroll(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "failed to enroll two-factor")
+		return
+	}
+
+	resp.Success(w, enrollment)
+}
+
+// ConfirmTwoFactor godoc
+// @Summary      Подтвердить подключение 2FA
+// @Description  Включает 2FA по коду из приложения и возвращает 10 одноразовых кодов восстановления.
+// @Description  Коды показываются один раз: сервер хранит только их хэши.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                   true  "User ID"
+// @Param        body  body      twoFactorCodeRequest  true  "Код из приложения"
+// @Success      200   {object}  map[string][]string
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/2fa/confirm [post]
+func (h *TwoFactorHandler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	var in twoFactorCodeRequest
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	codes, err := h.twoFactorService.Confirm(r.Context(), userID, in.Code)
+	if err != nil {
+		h.writeError(w, err, "failed to confirm two-factor")
+		return
+	}
+
+	resp.Success(w, map[string][]string{"recovery_codes": codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary      Выключить 2FA
+// @Description  Требует пароль (если он задан) и код из приложения или код восстановления.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                      true  "User ID"
+// @Param        body  body      twoFactorDisableRequest  true  "Пароль и код"
+// @Success      200   {object}  map[string]string
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/2fa/disable [post]
+func (h *TwoFactorHandler) DisableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	var in twoFactorDisableRequest
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	if err := h.twoFactorService.Disable(r.Context(), userID, in.Password, in.Code); err != nil {
+		h.writeError(w, err, "failed to disable two-factor")
+		return
+	}
+
+	resp.Success(w, map[string]string{"message": "Two-factor authentication disabled"})
+}
+
+// authorize сверяет {id} из пути с пользователем из токена: 2FA настраивает только владелец.
+func (h *TwoFactorHandler) authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	requestedUserID, err := parseUserID(mux.Vars(r))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, false
+	}
+
+	if requestedUserID != authUserID {
+		h.logger.Warn("User tried to access another user's two-factor settings",
+			zap.Int("requested_user_id", requestedUserID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only access own two-factor settings"))
+		return 0, false
+	}
+	return requestedUserID, true
+}
+
+// writeError переводит ошибки 2FA в HTTP-статусы.
+func (h *TwoFactorHandler) writeError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidTwoFactorCode):
+		resp.Error(w, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrReauthenticationFailed):
+		resp.Error(w, http.StatusForbidden, err)
+	case errors.Is(err, services.ErrTwoFactorEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnabled),
+		errors.Is(err, services.ErrTwoFactorNotEnrolled):
+		resp.Error(w, http.StatusConflict, err)
+	case errors.Is(err, services.ErrUserNotFound):
+		resp.Error(w, http.StatusNotFound, err)
+	default:
+		h.logger.Error("Two-factor error", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New(msg))
+	}
+}
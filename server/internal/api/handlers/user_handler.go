@@ -9,6 +9,7 @@ import (
 	"github.com/gorilla/mux"
 	"go.uber.org/zap"
 
+	"outfitstyle/server/internal/core/application/planner"
 	"outfitstyle/server/internal/core/application/services"
 	"outfitstyle/server/internal/core/domain"
 	"outfitstyle/server/internal/infrastructure/middleware"
@@ -17,8 +18,9 @@ import (
 
 // UserHandler handles user-related HTTP requests.
 type UserHandler struct {
-	userService *services.UserService
-	logger      *zap.Logger
+	userService  *services.UserService
+	statsService *services.StatsService
+	logger       *zap.Logger
 }
 
 // NewUserHandler creates a new user handler.
@@ -32,6 +34,12 @@ func NewUserHandler(
 	}
 }
 
+// WithStats отдаёт в GET /users/{id}/stats статистику, которую ведёт StatsService.
+func (h *UserHandler) WithStats(statsService *services.StatsService) *UserHandler {
+	h.statsService = statsService
+	return h
+}
+
 // parseUserID is a small helper to parse user id from path vars.
 func parseUserID(vars map[string]string) (int, error) {
 	userIDStr, ok := vars["id"]
@@ -238,17 +246,26 @@ func (h *UserHandler) GetUserRatings(w http.ResponseWriter, r *http.Request) {
 	resp.Success(w, response)
 }
 
+// createOutfitPlanRequest — план образа и место, для погоды которого подбираются подкатегории.
+type createOutfitPlanRequest struct {
+	domain.OutfitPlan
+	Location string `json:"location,omitempty"`
+}
+
 // CreateOutfitPlan godoc
 // @Summary      Создать план образа
 // @Description  Создаёт новый план образа для пользователя. Только для авторизованного пользователя.
+// @Description  Если указан location, в ответе weather_plan — подкатегории на дату плана: до 5 дней вперёд
+// @Description  по прогнозу (basis=forecast), дальше или без прогноза — по климатической норме (basis=climate).
 // @Tags         users
 // @Accept       json
 // @Produce      json
-// @Param        id    path      int             true  "User ID"
-// @Param        plan  body      domain.OutfitPlan  true  "Данные плана образа"
-// @Success      200   {object}  map[string]string
+// @Param        id    path      int                      true  "User ID"
+// @Param        plan  body      createOutfitPlanRequest  true  "Данные плана образа"
+// @Success      200   {object}  map[string]interface{}
 // @Failure      400   {object}  map[string]string
 // @Failure      401   {object}  map[string]string
+// @Failure      422   {object}  map[string]string
 // @Failure      500   {object}  map[string]string
 // @Security     BearerAuth
 // @Router       /users/{id}/outfit-plans [post]
@@ -278,22 +295,38 @@ func (h *UserHandler) CreateOutfitPlan(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var plan domain.OutfitPlan
-	if err := json.NewDecoder(r.Body).Decode(&plan); err != nil {
+	var req createOutfitPlanRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		resp.Error(w, http.StatusBadRequest, errors.New("invalid request body"))
 		return
 	}
 
+	plan := req.OutfitPlan
 	plan.UserID = domain.ID(requestedUserID)
 
 	ctx := r.Context()
-	if err := h.userService.CreateOutfitPlan(ctx, &plan); err != nil {
+	weatherPlan, err := h.userService.CreateOutfitPlan(ctx, &plan, req.Location)
+	switch {
+	case errors.Is(err, planner.ErrDateInPast):
+		resp.Error(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, planner.ErrNoWeather):
+		resp.Error(w, http.StatusUnprocessableEntity, err)
+		return
+	case err != nil:
 		h.logger.Error("Failed to create outfit plan", zap.Error(err))
 		resp.Error(w, http.StatusInternalServerError, errors.New("failed to create outfit plan"))
 		return
 	}
 
-	resp.Success(w, map[string]string{"message": "Outfit plan created successfully"})
+	response := map[string]interface{}{
+		"message": "Outfit plan created successfully",
+		"plan":    plan,
+	}
+	if weatherPlan != nil {
+		response["weather_plan"] = weatherPlan
+	}
+	resp.Success(w, response)
 }
 
 // GetUserOutfitPlans godoc
@@ -439,6 +472,21 @@ func (h *UserHandler) GetUserStats(w http.ResponseWriter, r *http.Request) {
 	}
 
 	ctx := r.Context()
+	if h.statsService != nil {
+		stats, err := h.statsService.GetUserStats(ctx, requestedUserID)
+		if err != nil {
+			h.logger.Error("Failed to get user stats", zap.Error(err))
+			resp.Error(w, http.StatusInternalServerError, errors.New("failed to get user stats"))
+			return
+		}
+		if stats == nil {
+			resp.Error(w, http.StatusNotFound, errors.New("user stats not found"))
+			return
+		}
+		resp.Success(w, stats)
+		return
+	}
+
 	stats, err := h.userService.GetUserStats(ctx, requestedUserID)
 	if err != nil {
 		h.logger.Error("Failed to get user stats", zap.Error(err))
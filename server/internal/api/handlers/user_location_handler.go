@@ -0,0 +1,263 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// UserLocationHandler handles saved user locations (home, work, custom).
+type UserLocationHandler struct {
+	locationService *services.UserLocationService
+	logger          *zap.Logger
+}
+
+// NewUserLocationHandler creates a new saved locations handler.
+func NewUserLocationHandler(
+	locationService *services.UserLocationService,
+	logger *zap.Logger,
+) *UserLocationHandler {
+	return &UserLocationHandler{
+		locationService: locationService,
+		logger:          logger,
+	}
+}
+
+// ListLocations godoc
+// @Summary      Сохранённые места пользователя
+// @Description  Возвращает сохранённые места (дом, работа, свои); место по умолчанию — первым.
+// @Tags         users
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   domain.UserLocation
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/locations [get]
+func (h *UserLocationHandler) ListLocations(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	locations, err := h.locationService.List(r.Context(), userID)
+	if err != nil {
+		h.logger.Error("Failed to list user locations", zap.Error(err), zap.Int("user_id", userID))
+		resp.Error(w, http.StatusInternalServerError, errors.New("failed to list locations"))
+		return
+	}
+
+	resp.Success(w, locations)
+}
+
+// CreateLocation godoc
+// @Summary      Сохранить место
+// @Description  Сохраняет место по location_id из /locations/search или по lat/lon с часовым поясом (IANA).
+// @Description  Дом и работа — не больше одного каждого вида. Первое место становится местом по умолчанию.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                          true  "User ID"
+// @Param        body  body      services.UserLocationInput   true  "Место"
+// @Success      201   {object}  domain.UserLocation
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/locations [post]
+func (h *UserLocationHandler) CreateLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	defer r.Body.Close()
+
+	var in services.UserLocationInput
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	loc, err := h.locationService.Create(r.Context(), userID, in)
+	if err != nil {
+		h.writeError(w, err, "failed to create location")
+		return
+	}
+
+	resp.JSONResponse(w, http.StatusCreated, loc)
+}
+
+// UpdateLocation godoc
+// @Summary      Изменить место
+// @Description  Заменяет сохранённое место. Снять отметку «по умолчанию» можно, только выбрав другое место.
+// @Tags         users
+// @Accept       json
+// @Produce      json
+// @Param        id      path      int                          true  "User ID"
+// @Param        loc_id  path      int                          true  "ID сохранённого места"
+// @Param        body    body      services.UserLocationInput   true  "Место"
+// @Success      200     {object}  domain.UserLocation
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      409     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/locations/{loc_id} [put]
+func (h *UserLocationHandler) UpdateLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	defer r.Body.Close()
+
+	locID, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	var in services.UserLocationInput
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	loc, err := h.locationService.Update(r.Context(), userID, locID, in)
+	if err != nil {
+		h.writeError(w, err, "failed to update location")
+		return
+	}
+
+	resp.Success(w, loc)
+}
+
+// DeleteLocation godoc
+// @Summary      Удалить место
+// @Description  Удаляет сохранённое место. Если оно было местом по умолчанию, им становится самое старое из оставшихся.
+// @Tags         users
+// @Produce      json
+// @Param        id      path      int  true  "User ID"
+// @Param        loc_id  path      int  true  "ID сохранённого места"
+// @Success      200     {object}  map[string]string
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/locations/{loc_id} [delete]
+func (h *UserLocationHandler) DeleteLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	locID, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.locationService.Delete(r.Context(), userID, locID); err != nil {
+		h.writeError(w, err, "failed to delete location")
+		return
+	}
+
+	resp.Success(w, map[string]string{"message": "Location deleted successfully"})
+}
+
+// SetDefaultLocation godoc
+// @Summary      Сделать место местом по умолчанию
+// @Description  Место по умолчанию используется рекомендациями без city/location_id/lat,lon, а также рассылками и планами.
+// @Tags         users
+// @Produce      json
+// @Param        id      path      int  true  "User ID"
+// @Param        loc_id  path      int  true  "ID сохранённого места"
+// @Success      200     {object}  domain.UserLocation
+// @Failure      400     {object}  map[string]string
+// @Failure      401     {object}  map[string]string
+// @Failure      403     {object}  map[string]string
+// @Failure      404     {object}  map[string]string
+// @Failure      500     {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/locations/{loc_id}/default [put]
+func (h *UserLocationHandler) SetDefaultLocation(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	locID, ok := parseLocationID(w, r)
+	if !ok {
+		return
+	}
+
+	loc, err := h.locationService.SetDefault(r.Context(), userID, locID)
+	if err != nil {
+		h.writeError(w, err, "failed to set default location")
+		return
+	}
+
+	resp.Success(w, loc)
+}
+
+// authorize сверяет {id} из пути с пользователем из токена: места видны только владельцу.
+func (h *UserLocationHandler) authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	requestedUserID, err := parseUserID(mux.Vars(r))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, false
+	}
+
+	if requestedUserID != authUserID {
+		h.logger.Warn("User tried to access another user's locations",
+			zap.Int("requested_user_id", requestedUserID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only access own locations"))
+		return 0, false
+	}
+	return requestedUserID, true
+}
+
+func parseLocationID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["loc_id"])
+	if err != nil || id <= 0 {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid location ID"))
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError переводит ошибки сервиса мест в HTTP-статусы.
+func (h *UserLocationHandler) writeError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidLocation):
+		resp.Error(w, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrLocationNotFound):
+		resp.Error(w, http.StatusNotFound, err)
+	case errors.Is(err, repositories.ErrTooManyLocations),
+		errors.Is(err, repositories.ErrLocationKindTaken),
+		errors.Is(err, repositories.ErrDefaultLocationTaken):
+		resp.Error(w, http.StatusConflict, err)
+	default:
+		h.logger.Error("User location error", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New(msg))
+	}
+}
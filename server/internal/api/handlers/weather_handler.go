@@ -1,33 +1,41 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"outfitstyle/server/internal/core/domain"
 	"outfitstyle/server/internal/infrastructure/external"
+	"outfitstyle/server/internal/pkg/gazetteer"
 )
 
 type WeatherHandler struct {
-	weather *external.WeatherService
+	weather external.WeatherProvider
+	places  *gazetteer.Gazetteer
 	logger  *zap.Logger
 }
 
-func NewWeatherHandler(weather *external.WeatherService, logger *zap.Logger) *WeatherHandler {
-	return &WeatherHandler{weather: weather, logger: logger}
+func NewWeatherHandler(weather external.WeatherProvider, places *gazetteer.Gazetteer, logger *zap.Logger) *WeatherHandler {
+	return &WeatherHandler{weather: weather, places: places, logger: logger}
 }
 
 func (h *WeatherHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	city := r.URL.Query().Get("city")
-	if city == "" {
-		http.Error(w, "city is required", http.StatusBadRequest)
+	loc, err := parseWeatherLocation(r.URL.Query(), h.places)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	data, err := h.weather.GetWeather(ctx, city)
+	data, err := loc.fetch(ctx, h.weather)
 	if err != nil {
 		if errors.Is(err, external.ErrCityNotFound) {
 			http.Error(w, "city not found", http.StatusBadRequest)
@@ -43,3 +51,121 @@ func (h *WeatherHandler) GetWeather(w http.ResponseWriter, r *http.Request) {
 		h.logger.Error("failed to encode weather response", zap.Error(err))
 	}
 }
+
+// errNoLocation — в запросе не указано место; рекомендации в этом случае берут место пользователя по умолчанию.
+var errNoLocation = errors.New("location_id, city or lat/lon parameters are required")
+
+// weatherLocation — место из query: город из справочника, координаты, название как есть
+// или сохранённое место пользователя.
+type weatherLocation struct {
+	city   string
+	coords *domain.Coordinates
+	place  *gazetteer.Place
+	saved  *domain.UserLocation
+}
+
+// parseWeatherLocation читает location_id, lat+lon или city (в порядке приоритета).
+// Город, найденный в справочнике по точному названию, запрашивается по его координатам;
+// остальные названия уходят провайдеру как есть.
+func parseWeatherLocation(q url.Values, places *gazetteer.Gazetteer) (weatherLocation, error) {
+	if id := strings.TrimSpace(q.Get("location_id")); id != "" {
+		if places == nil {
+			return weatherLocation{}, fmt.Errorf("location_id is not supported")
+		}
+		place, ok := places.Get(id)
+		if !ok {
+			return weatherLocation{}, fmt.Errorf("unknown location_id %q", id)
+		}
+		return placeLocation(place), nil
+	}
+
+	latStr, lonStr := strings.TrimSpace(q.Get("lat")), strings.TrimSpace(q.Get("lon"))
+	if latStr == "" && lonStr == "" {
+		city := strings.TrimSpace(q.Get("city"))
+		if city == "" {
+			return weatherLocation{}, errNoLocation
+		}
+		if places != nil {
+			if place, ok := places.Resolve(city); ok {
+				return placeLocation(place), nil
+			}
+		}
+		return weatherLocation{city: city}, nil
+	}
+	if latStr == "" || lonStr == "" {
+		return weatherLocation{}, fmt.Errorf("lat and lon must be provided together")
+	}
+
+	lat, err := strconv.ParseFloat(latStr, 64)
+	if err != nil {
+		return weatherLocation{}, fmt.Errorf("invalid lat: %q", latStr)
+	}
+	lon, err := strconv.ParseFloat(lonStr, 64)
+	if err != nil {
+		return weatherLocation{}, fmt.Errorf("invalid lon: %q", lonStr)
+	}
+	coords := domain.Coordinates{Lat: lat, Lon: lon}
+	if err := coords.Validate(); err != nil {
+		return weatherLocation{}, err
+	}
+	return weatherLocation{coords: &coords}, nil
+}
+
+func placeLocation(place gazetteer.Place) weatherLocation {
+	return weatherLocation{
+		city:   place.Name,
+		coords: &domain.Coordinates{Lat: place.Lat, Lon: place.Lon},
+		place:  &place,
+	}
+}
+
+// savedLocation — сохранённое место: город из справочника запрашивается как placeLocation,
+// точка на карте — по координатам, название даёт провайдер.
+func savedLocation(loc *domain.UserLocation, places *gazetteer.Gazetteer) weatherLocation {
+	if places != nil && loc.LocationID != "" {
+		if place, ok := places.Get(loc.LocationID); ok {
+			l := placeLocation(place)
+			l.saved = loc
+			return l
+		}
+	}
+	coords := loc.Coordinates
+	return weatherLocation{coords: &coords, saved: loc}
+}
+
+func (l weatherLocation) fetch(ctx context.Context, p external.WeatherProvider) (*domain.ExtendedWeatherData, error) {
+	if l.coords == nil {
+		return p.GetWeather(ctx, l.city)
+	}
+
+	weather, err := p.GetWeatherByCoords(ctx, *l.coords)
+	if err != nil || l.place == nil {
+		return weather, err
+	}
+	// Провайдер называет ближайшую станцию или район — показываем город, который выбрал пользователь
+	named := *weather
+	named.Location = l.place.DisplayName()
+	return &named, nil
+}
+
+// suggestions — подсказки для названия, которого не знает провайдер.
+func (l weatherLocation) suggestions(places *gazetteer.Gazetteer) []gazetteer.Match {
+	if places == nil || l.city == "" || l.place != nil {
+		return nil
+	}
+	return places.Search(l.city, 5)
+}
+
+// logField — место запроса для логов.
+func (l weatherLocation) logField() zap.Field {
+	if l.saved != nil {
+		return zap.Int("saved_location_id", l.saved.ID)
+	}
+	if l.place != nil {
+		return zap.String("location_id", l.place.ID)
+	}
+	if l.coords != nil {
+		return zap.Stringer("coords", *l.coords)
+	}
+	return zap.String("city", l.city)
+}
@@ -0,0 +1,395 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/services"
+	"outfitstyle/server/internal/infrastructure/middleware"
+	resp "outfitstyle/server/internal/pkg/http"
+)
+
+// Размер страницы журнала доставок.
+const (
+	defaultDeliveriesLimit = 50
+	maxDeliveriesLimit     = 200
+)
+
+// WebhookHandler handles users' webhook subscriptions and their delivery log.
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+	logger         *zap.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler.
+func NewWebhookHandler(
+	webhookService *services.WebhookService,
+	logger *zap.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+		logger:         logger,
+	}
+}
+
+// ListWebhooks godoc
+// @Summary      Вебхуки пользователя
+// @Description  Возвращает вебхуки без секретов.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id   path      int  true  "User ID"
+// @Success      200  {array}   domain.Webhook
+// @Failure      400  {object}  map[string]string
+// @Failure      401  {object}  map[string]string
+// @Failure      403  {object}  map[string]string
+// @Failure      500  {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks [get]
+func (h *WebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+
+	hooks, err := h.webhookService.List(r.Context(), userID)
+	if err != nil {
+		h.writeError(w, err, "failed to list webhooks")
+		return
+	}
+
+	resp.Success(w, hooks)
+}
+
+// CreateWebhook godoc
+// @Summary      Создать вебхук
+// @Description  Подписывает URL на события: recommendation.created, rating.submitted, wardrobe.item_added,
+// @Description  wardrobe.item_removed, achievement.unlocked. В ответе — секрет для проверки подписи
+// @Description  X-OutfitStyle-Signature; больше он не показывается. Не больше 10 вебхуков.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id    path      int                    true  "User ID"
+// @Param        body  body      services.WebhookInput  true  "Вебхук"
+// @Success      201   {object}  domain.Webhook
+// @Failure      400   {object}  map[string]string
+// @Failure      401   {object}  map[string]string
+// @Failure      403   {object}  map[string]string
+// @Failure      409   {object}  map[string]string
+// @Failure      500   {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks [post]
+func (h *WebhookHandler) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	defer r.Body.Close()
+
+	var in services.WebhookInput
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	hook, err := h.webhookService.Create(r.Context(), userID, in)
+	if err != nil {
+		h.writeError(w, err, "failed to create webhook")
+		return
+	}
+
+	resp.JSONResponse(w, http.StatusCreated, hook)
+}
+
+// GetWebhook godoc
+// @Summary      Вебхук
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int  true  "User ID"
+// @Param        webhook_id  path      int  true  "ID вебхука"
+// @Success      200         {object}  domain.Webhook
+// @Failure      400         {object}  map[string]string
+// @Failure      401         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks/{webhook_id} [get]
+func (h *WebhookHandler) GetWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	hookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookService.Get(r.Context(), userID, hookID)
+	if err != nil {
+		h.writeError(w, err, "failed to get webhook")
+		return
+	}
+
+	resp.Success(w, hook)
+}
+
+// UpdateWebhook godoc
+// @Summary      Изменить вебхук
+// @Description  Заменяет URL, описание, события и active (тело как в POST); секрет не меняется.
+// @Description  Доставки выключенного вебхука ждут в очереди, пока его не включат.
+// @Tags         webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id          path      int                    true  "User ID"
+// @Param        webhook_id  path      int                    true  "ID вебхука"
+// @Param        body        body      services.WebhookInput  true  "Вебхук"
+// @Success      200         {object}  domain.Webhook
+// @Failure      400         {object}  map[string]string
+// @Failure      401         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks/{webhook_id} [put]
+func (h *WebhookHandler) UpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	defer r.Body.Close()
+
+	hookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	var in services.WebhookInput
+	if !decodeJSONReq(w, r, &in) {
+		return
+	}
+
+	hook, err := h.webhookService.Update(r.Context(), userID, hookID, in)
+	if err != nil {
+		h.writeError(w, err, "failed to update webhook")
+		return
+	}
+
+	resp.Success(w, hook)
+}
+
+// DeleteWebhook godoc
+// @Summary      Удалить вебхук
+// @Description  Удаляет вебхук вместе с журналом доставок.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int  true  "User ID"
+// @Param        webhook_id  path      int  true  "ID вебхука"
+// @Success      200         {object}  map[string]string
+// @Failure      400         {object}  map[string]string
+// @Failure      401         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks/{webhook_id} [delete]
+func (h *WebhookHandler) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	hookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(r.Context(), userID, hookID); err != nil {
+		h.writeError(w, err, "failed to delete webhook")
+		return
+	}
+
+	resp.Success(w, map[string]string{"message": "Webhook deleted successfully"})
+}
+
+// RotateWebhookSecret godoc
+// @Summary      Сменить секрет вебхука
+// @Description  Возвращает новый секрет; доставки из очереди будут подписаны им.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int  true  "User ID"
+// @Param        webhook_id  path      int  true  "ID вебхука"
+// @Success      200         {object}  domain.Webhook
+// @Failure      400         {object}  map[string]string
+// @Failure      401         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks/{webhook_id}/rotate-secret [post]
+func (h *WebhookHandler) RotateWebhookSecret(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	hookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookService.RotateSecret(r.Context(), userID, hookID)
+	if err != nil {
+		h.writeError(w, err, "failed to rotate webhook secret")
+		return
+	}
+
+	resp.Success(w, hook)
+}
+
+// ListWebhookDeliveries godoc
+// @Summary      Журнал доставок вебхука
+// @Description  Доставки, новые первыми: статус (pending, delivered, dead), число попыток, следующая попытка,
+// @Description  код и ошибка последнего ответа, тело запроса.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id          path      int     true   "User ID"
+// @Param        webhook_id  path      int     true   "ID вебхука"
+// @Param        status      query     string  false  "pending, delivered или dead"
+// @Param        limit       query     int     false  "1–200, по умолчанию 50"
+// @Param        offset      query     int     false  "Смещение"
+// @Success      200         {array}   domain.WebhookDelivery
+// @Failure      400         {object}  map[string]string
+// @Failure      401         {object}  map[string]string
+// @Failure      403         {object}  map[string]string
+// @Failure      404         {object}  map[string]string
+// @Failure      500         {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks/{webhook_id}/deliveries [get]
+func (h *WebhookHandler) ListWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	hookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+
+	q := r.URL.Query()
+	limit := defaultDeliveriesLimit
+	if raw := q.Get("limit"); raw != "" {
+		l, err := strconv.Atoi(raw)
+		if err != nil || l < 1 || l > maxDeliveriesLimit {
+			resp.Error(w, http.StatusBadRequest, errors.New("limit must be between 1 and 200"))
+			return
+		}
+		limit = l
+	}
+	offset := 0
+	if raw := q.Get("offset"); raw != "" {
+		o, err := strconv.Atoi(raw)
+		if err != nil || o < 0 {
+			resp.Error(w, http.StatusBadRequest, errors.New("offset must be a non-negative integer"))
+			return
+		}
+		offset = o
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(r.Context(), userID, hookID, q.Get("status"), limit, offset)
+	if err != nil {
+		h.writeError(w, err, "failed to list webhook deliveries")
+		return
+	}
+
+	resp.Success(w, deliveries)
+}
+
+// RedeliverWebhook godoc
+// @Summary      Повторить доставку
+// @Description  Ставит доставку (обычно dead) в очередь заново с полным числом попыток.
+// @Tags         webhooks
+// @Produce      json
+// @Param        id           path      int  true  "User ID"
+// @Param        webhook_id   path      int  true  "ID вебхука"
+// @Param        delivery_id  path      int  true  "ID доставки"
+// @Success      200          {object}  domain.WebhookDelivery
+// @Failure      400          {object}  map[string]string
+// @Failure      401          {object}  map[string]string
+// @Failure      403          {object}  map[string]string
+// @Failure      404          {object}  map[string]string
+// @Failure      500          {object}  map[string]string
+// @Security     BearerAuth
+// @Router       /users/{id}/webhooks/{webhook_id}/deliveries/{delivery_id}/redeliver [post]
+func (h *WebhookHandler) RedeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.authorize(w, r)
+	if !ok {
+		return
+	}
+	hookID, ok := parseWebhookID(w, r)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(mux.Vars(r)["delivery_id"], 10, 64)
+	if err != nil || deliveryID <= 0 {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid delivery ID"))
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(r.Context(), userID, hookID, deliveryID)
+	if err != nil {
+		h.writeError(w, err, "failed to redeliver webhook")
+		return
+	}
+
+	resp.Success(w, delivery)
+}
+
+// authorize сверяет {id} из пути с пользователем из токена: вебхуки видны только владельцу.
+func (h *WebhookHandler) authorize(w http.ResponseWriter, r *http.Request) (int, bool) {
+	requestedUserID, err := parseUserID(mux.Vars(r))
+	if err != nil {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid user ID"))
+		return 0, false
+	}
+
+	authUserID, ok := middleware.GetUserIDFromContext(r.Context())
+	if !ok {
+		h.logger.Error("User ID not found in context")
+		resp.Error(w, http.StatusUnauthorized, errors.New("authentication required"))
+		return 0, false
+	}
+
+	if requestedUserID != authUserID {
+		h.logger.Warn("User tried to access another user's webhooks",
+			zap.Int("requested_user_id", requestedUserID),
+			zap.Int("authenticated_user_id", authUserID))
+		resp.Error(w, http.StatusForbidden, errors.New("access denied: can only access own webhooks"))
+		return 0, false
+	}
+	return requestedUserID, true
+}
+
+func parseWebhookID(w http.ResponseWriter, r *http.Request) (int, bool) {
+	id, err := strconv.Atoi(mux.Vars(r)["webhook_id"])
+	if err != nil || id <= 0 {
+		resp.Error(w, http.StatusBadRequest, errors.New("invalid webhook ID"))
+		return 0, false
+	}
+	return id, true
+}
+
+// writeError переводит ошибки сервиса вебхуков в HTTP-статусы.
+func (h *WebhookHandler) writeError(w http.ResponseWriter, err error, msg string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhook):
+		resp.Error(w, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrWebhookNotFound):
+		resp.Error(w, http.StatusNotFound, err)
+	case errors.Is(err, services.ErrTooManyWebhooks):
+		resp.Error(w, http.StatusConflict, err)
+	default:
+		h.logger.Error("Webhook error", zap.Error(err))
+		resp.Error(w, http.StatusInternalServerError, errors.New(msg))
+	}
+}
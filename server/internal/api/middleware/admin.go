@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"outfitstyle/server/internal/api"
+)
+
+// AdminTokenHeader — заголовок со статическим токеном администратора.
+const AdminTokenHeader = "X-Admin-Token"
+
+// AdminTokenMiddleware пропускает запрос только с верным X-Admin-Token.
+// Если токен не задан в конфиге, admin API недоступен целиком.
+func AdminTokenMiddleware(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				api.JSONError(w, http.StatusForbidden, "Admin API is disabled")
+				return
+			}
+
+			provided := r.Header.Get(AdminTokenHeader)
+			if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				api.JSONError(w, http.StatusUnauthorized, "Invalid admin token")
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
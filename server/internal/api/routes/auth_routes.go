@@ -13,6 +13,7 @@ func RegisterAuthRoutes(router *mux.Router, authHandler *handlers.AuthHandler) {
 	auth.HandleFunc("/register", authHandler.Register).Methods("POST")
 	auth.HandleFunc("/login", authHandler.Login).Methods("POST")
 	auth.HandleFunc("/verify", authHandler.VerifyCode).Methods("POST")
+	auth.HandleFunc("/2fa/verify", authHandler.VerifyTwoFactor).Methods("POST")
 	auth.HandleFunc("/refresh", authHandler.RefreshToken).Methods("POST")
 	auth.HandleFunc("/logout", authHandler.Logout).Methods("POST")
 	auth.HandleFunc("/forgot-password", authHandler.ForgotPassword).Methods("POST")
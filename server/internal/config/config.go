@@ -4,6 +4,7 @@ package config
 import (
 	"fmt"
 	"log"
+	"net"
 	"os"
 	"strconv"
 	"strings"
@@ -22,6 +23,11 @@ type AppConfig struct {
 	Security   SecurityConfig
 	Logging    LoggingConfig
 	Cache      CacheConfig
+	Storage    StorageConfig
+	Alerts     AlertsConfig
+	Digest     DigestConfig
+	Webhooks   WebhooksConfig
+	Jobs       JobsConfig
 }
 
 type ServerConfig struct {
@@ -50,7 +56,18 @@ type WeatherAPIConfig struct {
 	Key     string `env:"WEATHER_API_KEY"`
 	BaseURL string `env:"WEATHER_API_URL" default:"https://api.openweathermap.org/data/2.5"`
 	Timeout int    `env:"WEATHER_API_TIMEOUT" default:"10"` // seconds
+	// Providers — порядок failover: первый ответивший провайдер побеждает
+	Providers             []string `env:"WEATHER_PROVIDERS" default:"openweathermap,open-meteo"`
+	OpenMeteoURL          string   `env:"OPEN_METEO_URL" default:"https://api.open-meteo.com/v1"`
+	OpenMeteoGeocodingURL string   `env:"OPEN_METEO_GEOCODING_URL" default:"https://geocoding-api.open-meteo.com/v1"`
 }
+
+// Имена провайдеров погоды для WEATHER_PROVIDERS
+const (
+	WeatherProviderOpenWeatherMap = "openweathermap"
+	WeatherProviderOpenMeteo      = "open-meteo"
+)
+
 type MLServiceConfig struct {
 	BaseURL string `env:"ML_SERVICE_URL" default:"http://localhost:5000"`
 	Timeout int    `env:"ML_SERVICE_TIMEOUT" default:"30"` // seconds
@@ -70,9 +87,15 @@ type SecurityConfig struct {
 	RefreshTokenExpiryDays int    `env:"REFRESH_TOKEN_EXPIRY_DAYS" default:"7"`
 	VerificationCodeExpiry int    `env:"VERIFICATION_CODE_EXPIRY" default:"10"` // minutes
 	MaxLoginAttempts       int    `env:"MAX_LOGIN_ATTEMPTS" default:"5"`
+	MaxLoginAttemptsPerIP  int    `env:"MAX_LOGIN_ATTEMPTS_PER_IP" default:"20"`
 	BlockDuration          int    `env:"BLOCK_DURATION" default:"30"` // minutes
 	CORSAllowedOrigins     string `env:"CORS_ALLOWED_ORIGINS" default:"*"`
 	RateLimit              int    `env:"RATE_LIMIT" default:"100"` // requests per minute
+	AdminToken             string `env:"ADMIN_API_TOKEN"`          // пусто — admin API выключен
+	// TrustedProxies — CIDR (или адреса) прокси, чьему X-Forwarded-For можно верить; пусто — никому
+	TrustedProxies []string `env:"TRUSTED_PROXIES"`
+	// AuthCleanupInterval — как часто удалять истёкшие коды, токены и отзывы
+	AuthCleanupInterval time.Duration `env:"AUTH_CLEANUP_INTERVAL" default:"15m"`
 }
 
 type LoggingConfig struct {
@@ -82,10 +105,71 @@ type LoggingConfig struct {
 
 type CacheConfig struct {
 	Enabled    bool   `env:"CACHE_ENABLED" default:"true"`
+	Backend    string `env:"CACHE_BACKEND" default:"memory"` // memory или redis
 	RedisURL   string `env:"REDIS_URL" default:"redis://localhost:6379"`
 	Expiration int    `env:"CACHE_EXPIRATION" default:"300"` // seconds
 }
 
+// StorageConfig — хранилище фото гардероба и ограничения загрузки.
+type StorageConfig struct {
+	Backend            string        `env:"STORAGE_BACKEND" default:"local"` // local или s3
+	LocalDir           string        `env:"STORAGE_LOCAL_DIR" default:"./data/media"`
+	PublicBaseURL      string        `env:"PUBLIC_BASE_URL" default:"http://localhost:8080"`
+	SigningKey         string        `env:"STORAGE_SIGNING_KEY"` // пусто — используется JWT_SECRET
+	URLTTL             time.Duration `env:"PHOTO_URL_TTL" default:"1h"`
+	PhotoMaxMB         int           `env:"PHOTO_MAX_MB" default:"10"`
+	PhotoThumbnailSize int           `env:"PHOTO_THUMBNAIL_SIZE" default:"320"`
+	S3Endpoint         string        `env:"S3_ENDPOINT"`
+	S3Region           string        `env:"S3_REGION" default:"us-east-1"`
+	S3Bucket           string        `env:"S3_BUCKET"`
+	S3AccessKey        string        `env:"S3_ACCESS_KEY"`
+	S3SecretKey        string        `env:"S3_SECRET_KEY"`
+	S3PathStyle        bool          `env:"S3_PATH_STYLE" default:"false"`
+}
+
+// AlertsConfig — пороги предупреждений об опасной погоде и рассылка уведомлений о них.
+type AlertsConfig struct {
+	ColdC         int           `env:"ALERT_COLD_C" default:"-25"` // ощущаемая температура не выше
+	ColdDangerC   int           `env:"ALERT_COLD_DANGER_C" default:"-35"`
+	HeatC         int           `env:"ALERT_HEAT_C" default:"30"` // максимальная температура не ниже
+	HeatDangerC   int           `env:"ALERT_HEAT_DANGER_C" default:"35"`
+	WindMS        int           `env:"ALERT_WIND_MS" default:"15"` // ветер, м/с
+	WindDangerMS  int           `env:"ALERT_WIND_DANGER_MS" default:"25"`
+	NotifyEnabled bool          `env:"ALERTS_NOTIFY_ENABLED" default:"false"`
+	CheckInterval time.Duration `env:"ALERTS_CHECK_INTERVAL" default:"1h"`
+}
+
+// DigestConfig — утренний дайджест: время отправки выбирает пользователь, сервер раз
+// в PollInterval рассылает наступившие письма.
+type DigestConfig struct {
+	Enabled      bool          `env:"DIGEST_ENABLED" default:"false"`
+	PollInterval time.Duration `env:"DIGEST_POLL_INTERVAL" default:"1m"`
+}
+
+// WebhooksConfig — доставка вебхуков: повторы с экспоненциальной паузой, после
+// MaxAttempts неудач доставка становится dead.
+type WebhooksConfig struct {
+	Enabled      bool          `env:"WEBHOOKS_ENABLED" default:"true"`
+	PollInterval time.Duration `env:"WEBHOOKS_POLL_INTERVAL" default:"5s"`
+	MaxAttempts  int           `env:"WEBHOOKS_MAX_ATTEMPTS" default:"10"`
+	Timeout      time.Duration `env:"WEBHOOKS_TIMEOUT" default:"10s"`
+	BaseBackoff  time.Duration `env:"WEBHOOKS_BASE_BACKOFF" default:"1m"`
+	MaxBackoff   time.Duration `env:"WEBHOOKS_MAX_BACKOFF" default:"6h"`
+	AllowPrivate bool          `env:"WEBHOOKS_ALLOW_PRIVATE" default:"false"` // http:// и внутренние адреса, только для разработки
+}
+
+// JobsConfig — очередь фоновых задач в таблице jobs. Задача дольше Lease считается
+// брошенной и выполняется заново; при остановке сервер ждёт задачи до DrainTimeout.
+type JobsConfig struct {
+	Workers      int           `env:"JOBS_WORKERS" default:"4"`
+	PollInterval time.Duration `env:"JOBS_POLL_INTERVAL" default:"1s"`
+	Lease        time.Duration `env:"JOBS_LEASE" default:"5m"`
+	DrainTimeout time.Duration `env:"JOBS_DRAIN_TIMEOUT" default:"20s"`
+	BaseBackoff  time.Duration `env:"JOBS_BASE_BACKOFF" default:"10s"`
+	MaxBackoff   time.Duration `env:"JOBS_MAX_BACKOFF" default:"1h"`
+	Retention    time.Duration `env:"JOBS_RETENTION" default:"168h"` // сколько хранить выполненные задачи
+}
+
 func Load() (*AppConfig, error) {
 	// .env грузим ТОЛЬКО при локальном запуске, не в Docker
 	if os.Getenv("RUN_IN_DOCKER") == "" {
@@ -103,6 +187,11 @@ func Load() (*AppConfig, error) {
 		Security:   loadSecurityConfig(),
 		Logging:    loadLoggingConfig(),
 		Cache:      loadCacheConfig(),
+		Storage:    loadStorageConfig(),
+		Alerts:     loadAlertsConfig(),
+		Digest:     loadDigestConfig(),
+		Webhooks:   loadWebhooksConfig(),
+		Jobs:       loadJobsConfig(),
 	}
 
 	if err := validateConfig(cfg); err != nil {
@@ -130,6 +219,12 @@ func loadServerConfig() ServerConfig {
 	}
 }
 
+// LoadDatabaseConfig читает только настройки БД — для утилит из cmd/, которым не нужна
+// остальная конфигурация сервера.
+func LoadDatabaseConfig() DatabaseConfig {
+	return loadDatabaseConfig()
+}
+
 func loadDatabaseConfig() DatabaseConfig {
 	return DatabaseConfig{
 		Host:         getEnv("DB_HOST", "localhost"),
@@ -144,10 +239,21 @@ func loadDatabaseConfig() DatabaseConfig {
 }
 
 func loadWeatherAPIConfig() WeatherAPIConfig {
+	var providers []string
+	for _, p := range strings.Split(getEnv("WEATHER_PROVIDERS", "openweathermap,open-meteo"), ",") {
+		if p = strings.TrimSpace(strings.ToLower(p)); p != "" {
+			providers = append(providers, p)
+		}
+	}
+
 	return WeatherAPIConfig{
-		Key:     getEnv("WEATHER_API_KEY", ""),
-		BaseURL: getEnv("WEATHER_API_URL", "https://api.openweathermap.org/data/2.5"),
-		Timeout: getEnvInt("WEATHER_API_TIMEOUT", 10, 1, 300),
+		Key: getEnv("WEATHER_API_KEY", ""),
+		// WEATHER_API_BASE_URL — старое имя переменной, которое читал cmd/server
+		BaseURL:               getEnv("WEATHER_API_URL", getEnv("WEATHER_API_BASE_URL", "https://api.openweathermap.org/data/2.5")),
+		Timeout:               getEnvInt("WEATHER_API_TIMEOUT", 10, 1, 300),
+		Providers:             providers,
+		OpenMeteoURL:          getEnv("OPEN_METEO_URL", "https://api.open-meteo.com/v1"),
+		OpenMeteoGeocodingURL: getEnv("OPEN_METEO_GEOCODING_URL", "https://geocoding-api.open-meteo.com/v1"),
 	}
 }
 
@@ -175,9 +281,13 @@ func loadSecurityConfig() SecurityConfig {
 		RefreshTokenExpiryDays: getEnvInt("REFRESH_TOKEN_EXPIRY_DAYS", 7, 1, 365),
 		VerificationCodeExpiry: getEnvInt("VERIFICATION_CODE_EXPIRY", 10, 1, 1440),
 		MaxLoginAttempts:       getEnvInt("MAX_LOGIN_ATTEMPTS", 5, 1, 100),
+		MaxLoginAttemptsPerIP:  getEnvInt("MAX_LOGIN_ATTEMPTS_PER_IP", 20, 1, 1000),
 		BlockDuration:          getEnvInt("BLOCK_DURATION", 30, 1, 1440),
 		CORSAllowedOrigins:     getEnv("CORS_ALLOWED_ORIGINS", "*"),
 		RateLimit:              getEnvInt("RATE_LIMIT", 100, 1, 10000),
+		AdminToken:             getEnv("ADMIN_API_TOKEN", ""),
+		AuthCleanupInterval:    getEnvDuration("AUTH_CLEANUP_INTERVAL", 15*time.Minute),
+		TrustedProxies:         getEnvList("TRUSTED_PROXIES"),
 	}
 }
 
@@ -191,14 +301,89 @@ func loadLoggingConfig() LoggingConfig {
 func loadCacheConfig() CacheConfig {
 	return CacheConfig{
 		Enabled:    getEnvBool("CACHE_ENABLED", true),
+		Backend:    getEnv("CACHE_BACKEND", "memory"),
 		RedisURL:   getEnv("REDIS_URL", "redis://localhost:6379"),
 		Expiration: getEnvInt("CACHE_EXPIRATION", 300, 1, 3600),
 	}
 }
 
+func loadStorageConfig() StorageConfig {
+	return StorageConfig{
+		Backend:            getEnv("STORAGE_BACKEND", "local"),
+		LocalDir:           getEnv("STORAGE_LOCAL_DIR", "./data/media"),
+		PublicBaseURL:      strings.TrimRight(getEnv("PUBLIC_BASE_URL", "http://localhost:8080"), "/"),
+		SigningKey:         getEnv("STORAGE_SIGNING_KEY", getEnv("JWT_SECRET", "")),
+		URLTTL:             getEnvDuration("PHOTO_URL_TTL", time.Hour),
+		PhotoMaxMB:         getEnvInt("PHOTO_MAX_MB", 10, 1, 50),
+		PhotoThumbnailSize: getEnvInt("PHOTO_THUMBNAIL_SIZE", 320, 64, 1024),
+		S3Endpoint:         getEnv("S3_ENDPOINT", ""),
+		S3Region:           getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:           getEnv("S3_BUCKET", ""),
+		S3AccessKey:        getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:        getEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:        getEnvBool("S3_PATH_STYLE", false),
+	}
+}
+
+func loadAlertsConfig() AlertsConfig {
+	return AlertsConfig{
+		ColdC:         getEnvInt("ALERT_COLD_C", -25, -60, 0),
+		ColdDangerC:   getEnvInt("ALERT_COLD_DANGER_C", -35, -70, 0),
+		HeatC:         getEnvInt("ALERT_HEAT_C", 30, 20, 50),
+		HeatDangerC:   getEnvInt("ALERT_HEAT_DANGER_C", 35, 20, 55),
+		WindMS:        getEnvInt("ALERT_WIND_MS", 15, 5, 50),
+		WindDangerMS:  getEnvInt("ALERT_WIND_DANGER_MS", 25, 5, 60),
+		NotifyEnabled: getEnvBool("ALERTS_NOTIFY_ENABLED", false),
+		CheckInterval: getEnvDuration("ALERTS_CHECK_INTERVAL", time.Hour),
+	}
+}
+
+func loadDigestConfig() DigestConfig {
+	return DigestConfig{
+		Enabled:      getEnvBool("DIGEST_ENABLED", false),
+		PollInterval: getEnvDuration("DIGEST_POLL_INTERVAL", time.Minute),
+	}
+}
+
+func loadWebhooksConfig() WebhooksConfig {
+	return WebhooksConfig{
+		Enabled:      getEnvBool("WEBHOOKS_ENABLED", true),
+		PollInterval: getEnvDuration("WEBHOOKS_POLL_INTERVAL", 5*time.Second),
+		MaxAttempts:  getEnvInt("WEBHOOKS_MAX_ATTEMPTS", 10, 1, 30),
+		Timeout:      getEnvDuration("WEBHOOKS_TIMEOUT", 10*time.Second),
+		BaseBackoff:  getEnvDuration("WEBHOOKS_BASE_BACKOFF", time.Minute),
+		MaxBackoff:   getEnvDuration("WEBHOOKS_MAX_BACKOFF", 6*time.Hour),
+		AllowPrivate: getEnvBool("WEBHOOKS_ALLOW_PRIVATE", false),
+	}
+}
+
+func loadJobsConfig() JobsConfig {
+	return JobsConfig{
+		Workers:      getEnvInt("JOBS_WORKERS", 4, 1, 64),
+		PollInterval: getEnvDuration("JOBS_POLL_INTERVAL", time.Second),
+		Lease:        getEnvDuration("JOBS_LEASE", 5*time.Minute),
+		DrainTimeout: getEnvDuration("JOBS_DRAIN_TIMEOUT", 20*time.Second),
+		BaseBackoff:  getEnvDuration("JOBS_BASE_BACKOFF", 10*time.Second),
+		MaxBackoff:   getEnvDuration("JOBS_MAX_BACKOFF", time.Hour),
+		Retention:    getEnvDuration("JOBS_RETENTION", 7*24*time.Hour),
+	}
+}
+
 func validateConfig(cfg *AppConfig) error {
-	if cfg.WeatherAPI.Key == "" {
-		return errors.New("WEATHER_API_KEY is required")
+	if len(cfg.WeatherAPI.Providers) == 0 {
+		return errors.New("WEATHER_PROVIDERS must list at least one provider")
+	}
+	for _, p := range cfg.WeatherAPI.Providers {
+		switch p {
+		case WeatherProviderOpenWeatherMap:
+			if cfg.WeatherAPI.Key == "" {
+				return errors.New("WEATHER_API_KEY is required for the openweathermap provider")
+			}
+		case WeatherProviderOpenMeteo:
+		default:
+			return fmt.Errorf("invalid weather provider: %s (must be one of: %s, %s)",
+				p, WeatherProviderOpenWeatherMap, WeatherProviderOpenMeteo)
+		}
 	}
 
 	if cfg.Server.Environment != "development" {
@@ -217,6 +402,66 @@ func validateConfig(cfg *AppConfig) error {
 			cfg.Database.SSLMode, strings.Join(validSSLmodes, ", "))
 	}
 
+	if cfg.Cache.Backend != "memory" && cfg.Cache.Backend != "redis" {
+		return fmt.Errorf("invalid CACHE_BACKEND: %s (must be memory or redis)", cfg.Cache.Backend)
+	}
+
+	// Validate storage backend
+	switch cfg.Storage.Backend {
+	case "local":
+		if cfg.Server.Environment != "development" && cfg.Storage.SigningKey == "" {
+			return errors.New("STORAGE_SIGNING_KEY (or JWT_SECRET) is required for local storage in production")
+		}
+	case "s3":
+		if cfg.Storage.S3Bucket == "" || cfg.Storage.S3Endpoint == "" {
+			return errors.New("S3_ENDPOINT and S3_BUCKET are required for s3 storage")
+		}
+	default:
+		return fmt.Errorf("invalid STORAGE_BACKEND: %s (must be local or s3)", cfg.Storage.Backend)
+	}
+
+	// Danger-пороги должны быть строже обычных
+	if cfg.Alerts.ColdDangerC > cfg.Alerts.ColdC || cfg.Alerts.HeatDangerC < cfg.Alerts.HeatC || cfg.Alerts.WindDangerMS < cfg.Alerts.WindMS {
+		return errors.New("ALERT_*_DANGER thresholds must be stricter than the warning thresholds")
+	}
+	if cfg.Alerts.NotifyEnabled && cfg.Alerts.CheckInterval < time.Minute {
+		return errors.New("ALERTS_CHECK_INTERVAL must be at least 1m")
+	}
+	// Письмо уходит с точностью до интервала опроса
+	if cfg.Digest.Enabled && (cfg.Digest.PollInterval < 10*time.Second || cfg.Digest.PollInterval > 15*time.Minute) {
+		return errors.New("DIGEST_POLL_INTERVAL must be between 10s and 15m")
+	}
+	if cfg.Webhooks.PollInterval < time.Second || cfg.Webhooks.Timeout < time.Second || cfg.Webhooks.Timeout > time.Minute {
+		return errors.New("WEBHOOKS_POLL_INTERVAL must be at least 1s and WEBHOOKS_TIMEOUT between 1s and 1m")
+	}
+	if cfg.Webhooks.BaseBackoff < time.Second || cfg.Webhooks.MaxBackoff < cfg.Webhooks.BaseBackoff {
+		return errors.New("WEBHOOKS_BASE_BACKOFF must be at least 1s and not exceed WEBHOOKS_MAX_BACKOFF")
+	}
+	if cfg.Webhooks.AllowPrivate && cfg.Server.Environment != "development" {
+		return errors.New("WEBHOOKS_ALLOW_PRIVATE is only allowed in development")
+	}
+	if cfg.Security.AuthCleanupInterval < time.Minute {
+		return errors.New("AUTH_CLEANUP_INTERVAL must be at least 1m")
+	}
+	for _, proxy := range cfg.Security.TrustedProxies {
+		if parseProxyNet(proxy) == nil {
+			return fmt.Errorf("invalid TRUSTED_PROXIES entry: %s (must be an IP or CIDR)", proxy)
+		}
+	}
+	if cfg.Jobs.PollInterval < 100*time.Millisecond || cfg.Jobs.PollInterval > time.Minute {
+		return errors.New("JOBS_POLL_INTERVAL must be between 100ms and 1m")
+	}
+	// Аренда должна покрывать таймаут любой задачи, иначе задачу заберут, пока она выполняется
+	if cfg.Jobs.Lease < time.Minute || cfg.Jobs.DrainTimeout < 0 {
+		return errors.New("JOBS_LEASE must be at least 1m and JOBS_DRAIN_TIMEOUT non-negative")
+	}
+	if cfg.Jobs.BaseBackoff < time.Second || cfg.Jobs.MaxBackoff < cfg.Jobs.BaseBackoff {
+		return errors.New("JOBS_BASE_BACKOFF must be at least 1s and not exceed JOBS_MAX_BACKOFF")
+	}
+	if cfg.Jobs.Retention < time.Hour {
+		return errors.New("JOBS_RETENTION must be at least 1h")
+	}
+
 	// Validate connection limits
 	if cfg.Database.MaxOpenConns < cfg.Database.MaxIdleConns {
 		return errors.New("DB_MAX_OPEN_CONNS cannot be less than DB_MAX_IDLE_CONNS")
@@ -231,6 +476,32 @@ func (c *DatabaseConfig) DatabaseURL() string {
 		c.Host, c.Port, c.User, c.Password, c.Name, c.SSLMode)
 }
 
+// GetTrustedProxies returns the trusted proxy networks
+func (c *SecurityConfig) GetTrustedProxies() []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(c.TrustedProxies))
+	for _, proxy := range c.TrustedProxies {
+		if network := parseProxyNet(proxy); network != nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// parseProxyNet разбирает CIDR; одиночный адрес считается сетью из одного адреса.
+func parseProxyNet(s string) *net.IPNet {
+	if _, network, err := net.ParseCIDR(s); err == nil {
+		return network
+	}
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return nil
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
 // GetAllowedOrigins returns list of allowed CORS origins
 func (c *SecurityConfig) GetAllowedOrigins() []string {
 	if c.CORSAllowedOrigins == "*" {
@@ -282,6 +553,17 @@ func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
 	return defaultValue
 }
 
+// getEnvList читает список через запятую, пропуская пустые элементы.
+func getEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(os.Getenv(key), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
 func contains(s []string, e string) bool {
 	for _, a := range s {
 		if a == e {
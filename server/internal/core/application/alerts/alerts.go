@@ -0,0 +1,201 @@
+// Package alerts — правила предупреждений об опасной погоде: сильный мороз, жара, гроза,
+// сильный снегопад и сильный ветер. Пакет не зависит от остального приложения, поэтому
+// его используют и планировщик, и рекомендации, и рассылка уведомлений.
+package alerts
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Kind — вид предупреждения.
+type Kind string
+
+const (
+	ExtremeCold Kind = "extreme_cold"
+	Heat        Kind = "heat"
+	Storm       Kind = "storm"
+	HeavySnow   Kind = "heavy_snow"
+	StrongWind  Kind = "strong_wind"
+)
+
+// Severity — уровень опасности.
+type Severity string
+
+const (
+	Warning Severity = "warning"
+	Danger  Severity = "danger"
+)
+
+// Item — подкатегория словаря subcategory_specs.
+type Item struct {
+	Category    string `json:"category"`
+	Subcategory string `json:"subcategory"`
+}
+
+// Alert — сработавшее предупреждение. Protective планировщик добавляет в план
+// независимо от температурных норм, Avoid — убирает.
+type Alert struct {
+	Kind       Kind     `json:"kind"`
+	Severity   Severity `json:"severity"`
+	Title      string   `json:"title"`
+	Message    string   `json:"message"`
+	Protective []Item   `json:"protective,omitempty"`
+	Avoid      []Item   `json:"avoid,omitempty"`
+}
+
+// Conditions — прогноз, по которому проверяются правила. Weather — описание провайдера
+// (OpenWeatherMap и Open-Meteo отдают его по-русски) или условие планировщика.
+type Conditions struct {
+	Temperature float64
+	FeelsLike   float64
+	MinTemp     float64
+	MaxTemp     float64
+	WindSpeed   float64 // м/с
+	Weather     string
+}
+
+// Rules — пороги срабатывания. Danger-пороги строже Warning-порогов.
+type Rules struct {
+	ColdC        float64 // ощущаемая или минимальная температура не выше
+	ColdDangerC  float64
+	HeatC        float64 // максимальная температура не ниже
+	HeatDangerC  float64
+	WindMS       float64 // скорость ветра не ниже
+	WindDangerMS float64
+}
+
+// DefaultRules — пороги по умолчанию, близкие к критериям опасных явлений Росгидромета.
+func DefaultRules() Rules {
+	return Rules{
+		ColdC:        -25,
+		ColdDangerC:  -35,
+		HeatC:        30,
+		HeatDangerC:  35,
+		WindMS:       15,
+		WindDangerMS: 25,
+	}
+}
+
+var (
+	coldProtective = []Item{
+		{"outerwear", "puffer"}, {"outerwear", "parka"},
+		{"upper", "thermal_top"}, {"lower", "thermal_pants"},
+		{"footwear", "winter_boots"},
+		{"accessory", "hat"}, {"accessory", "scarf"}, {"accessory", "gloves"},
+	}
+	snowProtective = []Item{
+		{"outerwear", "parka"}, {"footwear", "winter_boots"},
+		{"accessory", "hat"}, {"accessory", "gloves"},
+	}
+	stormProtective = []Item{{"outerwear", "raincoat"}, {"footwear", "boots"}}
+	windProtective  = []Item{{"outerwear", "softshell"}}
+	// Зонт в грозу и на сильном ветру скорее опасен, чем полезен
+	noUmbrella = []Item{{"accessory", "umbrella"}}
+)
+
+var (
+	stormMarkers     = []string{"гроз", "thunderstorm"}
+	heavySnowMarkers = []string{"сильный снег", "heavy snow", "heavy shower snow"}
+)
+
+// Evaluate возвращает сработавшие предупреждения в порядке: мороз, жара, гроза, снегопад, ветер.
+func (r Rules) Evaluate(c Conditions) []Alert {
+	var out []Alert
+	weather := strings.ToLower(c.Weather)
+
+	if cold := math.Min(c.FeelsLike, c.MinTemp); cold <= r.ColdC {
+		out = append(out, Alert{
+			Kind:       ExtremeCold,
+			Severity:   severity(cold <= r.ColdDangerC),
+			Title:      "Сильный мороз",
+			Message:    "Ощущается как " + formatTemp(cold) + ". Закройте лицо и руки, выбирайте многослойную одежду и термобельё.",
+			Protective: coldProtective,
+		})
+	}
+
+	if hot := math.Max(c.Temperature, c.MaxTemp); hot >= r.HeatC {
+		out = append(out, Alert{
+			Kind:     Heat,
+			Severity: severity(hot >= r.HeatDangerC),
+			Title:    "Жара",
+			Message:  "До " + formatTemp(hot) + ". Лёгкая светлая одежда из натуральных тканей, пейте больше воды.",
+		})
+	}
+
+	if containsAny(weather, stormMarkers) {
+		out = append(out, Alert{
+			Kind:       Storm,
+			Severity:   severity(strings.Contains(weather, "сильн") || strings.Contains(weather, "heavy")),
+			Title:      "Гроза",
+			Message:    "Ожидается гроза. Возьмите дождевик вместо зонта и непромокаемую обувь.",
+			Protective: stormProtective,
+			Avoid:      noUmbrella,
+		})
+	}
+
+	if containsAny(weather, heavySnowMarkers) {
+		out = append(out, Alert{
+			Kind:       HeavySnow,
+			Severity:   Warning,
+			Title:      "Сильный снегопад",
+			Message:    "Ожидается сильный снег. Нужны высокая зимняя обувь, шапка и перчатки.",
+			Protective: snowProtective,
+		})
+	}
+
+	if c.WindSpeed >= r.WindMS {
+		out = append(out, Alert{
+			Kind:       StrongWind,
+			Severity:   severity(c.WindSpeed >= r.WindDangerMS),
+			Title:      "Сильный ветер",
+			Message:    "Ветер до " + formatFloat(c.WindSpeed) + " м/с. Нужна ветрозащитная куртка, зонт лучше оставить дома.",
+			Protective: windProtective,
+			Avoid:      noUmbrella,
+		})
+	}
+
+	return out
+}
+
+// MaxSeverity — наибольший уровень среди предупреждений; пустая строка, если их нет.
+func MaxSeverity(active []Alert) Severity {
+	var s Severity
+	for _, a := range active {
+		if a.Severity == Danger {
+			return Danger
+		}
+		s = Warning
+	}
+	return s
+}
+
+func severity(danger bool) Severity {
+	if danger {
+		return Danger
+	}
+	return Warning
+}
+
+func containsAny(s string, markers []string) bool {
+	for _, m := range markers {
+		if strings.Contains(s, m) {
+			return true
+		}
+	}
+	return false
+}
+
+func formatTemp(t float64) string {
+	s := formatFloat(t)
+	if t > 0 {
+		s = "+" + s
+	}
+	return s + "°C"
+}
+
+// formatFloat округляет до целых: десятые доли градуса в предупреждении только мешают.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(math.Round(f), 'f', 0, 64)
+}
@@ -0,0 +1,360 @@
+package catalog
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// Format — формат файла каталога.
+type Format string
+
+const (
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// MaterialsSeparator разделяет материалы внутри одной CSV-ячейки.
+const MaterialsSeparator = "|"
+
+// Columns — раскладка полей каталога. Совпадает с json-тегами domain.ClothingItem,
+// поэтому NDJSON и CSV взаимозаменяемы.
+var Columns = []string{
+	"id", "name", "category", "subcategory", "gender", "style", "usage", "season", "base_colour",
+	"formality_level", "warmth_level", "min_temp", "max_temp", "materials", "fit", "pattern",
+	"icon_emoji", "source", "is_owned",
+}
+
+// ParseFormat разбирает имя формата; пустая строка — определить по расширению файла.
+func ParseFormat(name, path string) (Format, error) {
+	if name == "" {
+		switch strings.ToLower(filepath.Ext(path)) {
+		case ".csv":
+			return FormatCSV, nil
+		case ".ndjson", ".jsonl", ".json":
+			return FormatNDJSON, nil
+		default:
+			return "", fmt.Errorf("cannot detect format of %q, pass it explicitly", path)
+		}
+	}
+
+	switch Format(strings.ToLower(name)) {
+	case FormatNDJSON:
+		return FormatNDJSON, nil
+	case FormatCSV:
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (must be ndjson or csv)", name)
+	}
+}
+
+// Record — одна строка входного файла.
+// Err заполнен, если строку не удалось разобрать; Item в этом случае не используется.
+type Record struct {
+	Line int
+	Raw  string
+	Item domain.ClothingItem
+	Err  error
+}
+
+// Reader потоково читает вещи каталога. Next возвращает io.EOF, когда строки закончились.
+type Reader interface {
+	Next() (Record, error)
+}
+
+// NewReader создаёт потоковый reader для указанного формата.
+func NewReader(r io.Reader, format Format) (Reader, error) {
+	switch format {
+	case FormatNDJSON:
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+		return &ndjsonReader{scanner: sc}, nil
+	case FormatCSV:
+		return newCSVReader(r)
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (r *ndjsonReader) Next() (Record, error) {
+	for r.scanner.Scan() {
+		r.line++
+		raw := strings.TrimSpace(r.scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		rec := Record{Line: r.line, Raw: raw}
+		dec := json.NewDecoder(strings.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&rec.Item); err != nil {
+			rec.Err = fmt.Errorf("invalid json: %w", err)
+		}
+		return rec, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return Record{}, fmt.Errorf("read ndjson: %w", err)
+	}
+	return Record{}, io.EOF
+}
+
+type csvReader struct {
+	reader *csv.Reader
+	index  map[string]int
+	line   int
+}
+
+func newCSVReader(r io.Reader) (*csvReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read csv header: %w", err)
+	}
+
+	known := make(map[string]bool, len(Columns))
+	for _, c := range Columns {
+		known[c] = true
+	}
+
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if !known[name] {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		index[name] = i
+	}
+
+	return &csvReader{reader: cr, index: index, line: 1}, nil
+}
+
+func (r *csvReader) Next() (Record, error) {
+	fields, err := r.reader.Read()
+	if err == io.EOF {
+		return Record{}, io.EOF
+	}
+	r.line++
+	rec := Record{Line: r.line, Raw: strings.Join(fields, ",")}
+	if err != nil {
+		rec.Err = fmt.Errorf("invalid csv: %w", err)
+		return rec, nil
+	}
+
+	rec.Item, rec.Err = r.decode(fields)
+	return rec, nil
+}
+
+func (r *csvReader) decode(fields []string) (domain.ClothingItem, error) {
+	var it domain.ClothingItem
+	get := func(col string) string {
+		i, ok := r.index[col]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return strings.TrimSpace(fields[i])
+	}
+
+	var err error
+	if it.ID, err = parseInt64(get("id")); err != nil {
+		return it, fmt.Errorf("id: %w", err)
+	}
+	if it.Formality, err = parseInt16(get("formality_level")); err != nil {
+		return it, fmt.Errorf("formality_level: %w", err)
+	}
+	if it.Warmth, err = parseInt16(get("warmth_level")); err != nil {
+		return it, fmt.Errorf("warmth_level: %w", err)
+	}
+	if it.MinTemp, err = parseInt16(get("min_temp")); err != nil {
+		return it, fmt.Errorf("min_temp: %w", err)
+	}
+	if it.MaxTemp, err = parseInt16(get("max_temp")); err != nil {
+		return it, fmt.Errorf("max_temp: %w", err)
+	}
+	if v := get("is_owned"); v != "" {
+		if it.IsOwned, err = strconv.ParseBool(v); err != nil {
+			return it, fmt.Errorf("is_owned: %w", err)
+		}
+	}
+
+	it.Name = get("name")
+	it.Category = get("category")
+	it.Subcategory = get("subcategory")
+	it.Gender = get("gender")
+	it.Style = get("style")
+	it.Usage = get("usage")
+	it.Season = get("season")
+	it.BaseColour = get("base_colour")
+	it.Fit = get("fit")
+	it.Pattern = get("pattern")
+	it.IconEmoji = get("icon_emoji")
+	it.Source = get("source")
+
+	if v := get("materials"); v != "" {
+		for _, m := range strings.Split(v, MaterialsSeparator) {
+			if m = strings.TrimSpace(m); m != "" {
+				it.Materials = append(it.Materials, m)
+			}
+		}
+	}
+
+	return it, nil
+}
+
+// Writer потоково пишет вещи каталога в той же раскладке, которую принимает Reader.
+type Writer interface {
+	Write(item domain.ClothingItem) error
+	Flush() error
+}
+
+// NewWriter создаёт writer для указанного формата. Для CSV сразу пишется заголовок.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case FormatNDJSON:
+		bw := bufio.NewWriter(w)
+		return &ndjsonWriter{buf: bw, enc: json.NewEncoder(bw)}, nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(Columns); err != nil {
+			return nil, fmt.Errorf("write csv header: %w", err)
+		}
+		return &csvWriter{writer: cw}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+// itemRow — NDJSON-представление вещи: ровно Columns, без created_at и переводов.
+type itemRow struct {
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Subcategory string   `json:"subcategory"`
+	Gender      string   `json:"gender"`
+	Style       string   `json:"style"`
+	Usage       string   `json:"usage"`
+	Season      string   `json:"season"`
+	BaseColour  string   `json:"base_colour"`
+	Formality   int16    `json:"formality_level"`
+	Warmth      int16    `json:"warmth_level"`
+	MinTemp     int16    `json:"min_temp"`
+	MaxTemp     int16    `json:"max_temp"`
+	Materials   []string `json:"materials"`
+	Fit         string   `json:"fit"`
+	Pattern     string   `json:"pattern"`
+	IconEmoji   string   `json:"icon_emoji"`
+	Source      string   `json:"source"`
+	IsOwned     bool     `json:"is_owned"`
+}
+
+type ndjsonWriter struct {
+	buf *bufio.Writer
+	enc *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(it domain.ClothingItem) error {
+	materials := it.Materials
+	if materials == nil {
+		materials = []string{}
+	}
+	return w.enc.Encode(itemRow{
+		ID: it.ID, Name: it.Name, Category: it.Category, Subcategory: it.Subcategory, Gender: it.Gender,
+		Style: it.Style, Usage: it.Usage, Season: it.Season, BaseColour: it.BaseColour,
+		Formality: it.Formality, Warmth: it.Warmth, MinTemp: it.MinTemp, MaxTemp: it.MaxTemp,
+		Materials: materials, Fit: it.Fit, Pattern: it.Pattern,
+		IconEmoji: it.IconEmoji, Source: it.Source, IsOwned: it.IsOwned,
+	})
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return w.buf.Flush()
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) Write(it domain.ClothingItem) error {
+	return w.writer.Write([]string{
+		strconv.FormatInt(it.ID, 10), it.Name, it.Category, it.Subcategory, it.Gender,
+		it.Style, it.Usage, it.Season, it.BaseColour,
+		strconv.Itoa(int(it.Formality)), strconv.Itoa(int(it.Warmth)),
+		strconv.Itoa(int(it.MinTemp)), strconv.Itoa(int(it.MaxTemp)),
+		strings.Join(it.Materials, MaterialsSeparator), it.Fit, it.Pattern,
+		it.IconEmoji, it.Source, strconv.FormatBool(it.IsOwned),
+	})
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// ReadSpecs читает словарь subcategory_specs из NDJSON (по одной норме на строку).
+// Спецификации всегда передаются в NDJSON, независимо от формата файла с вещами.
+func ReadSpecs(r io.Reader) ([]domain.SubcategorySpec, error) {
+	sc := bufio.NewScanner(r)
+	var specs []domain.SubcategorySpec
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := strings.TrimSpace(sc.Text())
+		if raw == "" {
+			continue
+		}
+
+		var spec domain.SubcategorySpec
+		dec := json.NewDecoder(strings.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&spec); err != nil {
+			return nil, fmt.Errorf("line %d: invalid json: %w", line, err)
+		}
+		if errs := ValidateSpec(spec); len(errs) > 0 {
+			return nil, fmt.Errorf("line %d: %s", line, strings.Join(errs, "; "))
+		}
+		specs = append(specs, spec)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read specs: %w", err)
+	}
+	return specs, nil
+}
+
+// WriteSpecs пишет словарь subcategory_specs в NDJSON.
+func WriteSpecs(w io.Writer, specs []domain.SubcategorySpec) error {
+	enc := json.NewEncoder(w)
+	for _, spec := range specs {
+		if err := enc.Encode(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseInt64(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+func parseInt16(s string) (int16, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseInt(s, 10, 16)
+	return int16(v), err
+}
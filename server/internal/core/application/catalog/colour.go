@@ -0,0 +1,112 @@
+package catalog
+
+import (
+	"math"
+	"sort"
+
+	"outfitstyle/server/internal/pkg/imaging"
+)
+
+const (
+	// DominantColourCount — сколько кластеров ищем на фото вещи.
+	DominantColourCount = 5
+	// MinColourConfidence — ниже этой уверенности base_colour не подставляется автоматически.
+	MinColourConfidence = 0.35
+	// colourMatchDeltaE — при таком расстоянии до эталона совпадение считается нулевым.
+	colourMatchDeltaE = 40
+)
+
+// BaseColourSwatches — эталонные RGB для каждого значения BaseColours.
+// У части цветов несколько эталонов: светло-серый и графит — это всё ещё gray, олива — green.
+var BaseColourSwatches = map[string][]imaging.Colour{
+	"black":  {{R: 20, G: 20, B: 22}},
+	"white":  {{R: 245, G: 245, B: 245}},
+	"gray":   {{R: 128, G: 128, B: 128}, {R: 185, G: 185, B: 185}, {R: 75, G: 77, B: 80}},
+	"navy":   {{R: 30, G: 40, B: 80}, {R: 20, G: 30, B: 60}},
+	"beige":  {{R: 215, G: 195, B: 160}, {R: 235, G: 225, B: 200}},
+	"brown":  {{R: 110, G: 75, B: 45}, {R: 150, G: 105, B: 65}, {R: 70, G: 50, B: 35}},
+	"green":  {{R: 60, G: 120, B: 60}, {R: 100, G: 105, B: 55}, {R: 40, G: 75, B: 50}},
+	"blue":   {{R: 50, G: 100, B: 190}, {R: 120, G: 160, B: 210}, {R: 70, G: 95, B: 135}},
+	"red":    {{R: 190, G: 30, B: 40}, {R: 120, G: 25, B: 35}},
+	"pink":   {{R: 235, G: 150, B: 180}, {R: 250, G: 200, B: 210}},
+	"yellow": {{R: 240, G: 210, B: 60}, {R: 245, G: 230, B: 140}},
+	"orange": {{R: 235, G: 125, B: 40}, {R: 200, G: 90, B: 40}},
+	"purple": {{R: 110, G: 55, B: 140}, {R: 170, G: 130, B: 190}},
+}
+
+// DominantColour — кластер цвета на фото и ближайшее значение палитры.
+type DominantColour struct {
+	Hex        string  `json:"hex"`
+	Share      float64 `json:"share"`
+	BaseColour string  `json:"base_colour"`
+}
+
+// ColourSuggestion — предложенный base_colour с уверенностью 0..1.
+// Confidence — доля вещи, уверенно попавшая в выбранный цвет палитры.
+type ColourSuggestion struct {
+	BaseColour string           `json:"base_colour"`
+	Confidence float64          `json:"confidence"`
+	Dominant   []DominantColour `json:"dominant_colours"`
+}
+
+// Confident сообщает, можно ли подставить цвет без подтверждения пользователя.
+func (s ColourSuggestion) Confident() bool {
+	return s.BaseColour != "" && s.Confidence >= MinColourConfidence
+}
+
+// DetectBaseColour находит основные цвета на фото вещи и сводит их к палитре base_colour.
+func DetectBaseColour(photo []byte) (ColourSuggestion, error) {
+	colours, err := imaging.DominantColours(photo, DominantColourCount)
+	if err != nil {
+		return ColourSuggestion{}, err
+	}
+	return MatchBaseColour(colours), nil
+}
+
+// MatchBaseColour сопоставляет кластеры с палитрой: каждый кластер голосует за ближайший
+// цвет весом «доля × близость», побеждает цвет с наибольшей суммой.
+func MatchBaseColour(colours []imaging.Colour) ColourSuggestion {
+	var suggestion ColourSuggestion
+	scores := make(map[string]float64)
+
+	for _, c := range colours {
+		name, dist := nearestBaseColour(c)
+		closeness := math.Max(0, 1-dist/colourMatchDeltaE)
+		scores[name] += c.Share * closeness
+		suggestion.Dominant = append(suggestion.Dominant, DominantColour{
+			Hex:        c.Hex(),
+			Share:      round2(c.Share),
+			BaseColour: name,
+		})
+	}
+
+	// Порядок BaseColours делает выбор при равенстве детерминированным
+	for _, name := range BaseColours {
+		if scores[name] > suggestion.Confidence {
+			suggestion.BaseColour = name
+			suggestion.Confidence = scores[name]
+		}
+	}
+	suggestion.Confidence = round2(suggestion.Confidence)
+
+	sort.SliceStable(suggestion.Dominant, func(i, j int) bool {
+		return suggestion.Dominant[i].Share > suggestion.Dominant[j].Share
+	})
+	return suggestion
+}
+
+func nearestBaseColour(c imaging.Colour) (string, float64) {
+	best, bestDist := "", math.MaxFloat64
+	for _, name := range BaseColours {
+		for _, swatch := range BaseColourSwatches[name] {
+			if d := imaging.DeltaE(c, swatch); d < bestDist {
+				best, bestDist = name, d
+			}
+		}
+	}
+	return best, bestDist
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,192 @@
+package catalog
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// Severity — насколько серьёзна находка отчёта о качестве.
+type Severity string
+
+const (
+	// SeverityError — вещь сломана или никогда не попадёт в подбор; кандидат в карантин.
+	SeverityError Severity = "error"
+	// SeverityWarning — данные подозрительны, но вещь работает.
+	SeverityWarning Severity = "warning"
+)
+
+// Коды правил отчёта о качестве.
+const (
+	RuleSchema          = "schema"
+	RuleWarmthBelowSpec = "warmth_below_spec"
+	RuleTempOutsideSpec = "temp_outside_spec"
+	RuleTempExceedsSpec = "temp_exceeds_spec"
+	RuleSeasonVsTemp    = "season_vs_temp"
+	RuleNearDuplicate   = "near_duplicate"
+)
+
+// Issue — одна находка по конкретной вещи.
+type Issue struct {
+	ItemID   int64    `json:"item_id"`
+	Source   string   `json:"source"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	Message  string   `json:"message"`
+}
+
+// SeasonTempRanges — правдоподобный диапазон температур для сезона, °C.
+// Вещь, чей min_temp..max_temp не пересекается с диапазоном своего сезона, противоречит сама себе.
+var SeasonTempRanges = map[string][2]int16{
+	"winter": {-50, 10},
+	"spring": {-5, 25},
+	"autumn": {-10, 22},
+	"summer": {12, 50},
+}
+
+// SpecTempTolerance — на сколько градусов диапазон вещи может выходить за рекомендацию нормы без предупреждения.
+const SpecTempTolerance = 5
+
+// DefaultNameSimilarity — порог сходства названий (Жаккар по словам) для почти-дубликатов.
+const DefaultNameSimilarity = 0.8
+
+// sourcePriority — какой источник считать оригиналом при дубликатах: вещь с меньшим приоритетом помечается.
+var sourcePriority = map[string]int{"user": 3, "manual": 2, "partner": 1, "synthetic": 0}
+
+// CheckItem применяет к вещи схему и правила согласованности со словарём норм.
+func (v *Validator) CheckItem(item domain.ClothingItem) []Issue {
+	var issues []Issue
+	add := func(rule string, sev Severity, format string, args ...interface{}) {
+		issues = append(issues, Issue{ItemID: item.ID, Source: item.Source, Rule: rule, Severity: sev, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, e := range v.Validate(item) {
+		add(RuleSchema, SeverityError, "%s", e)
+	}
+
+	if spec, ok := v.Spec(item.Category, item.Subcategory); ok {
+		// FindCandidatesByPlan требует warmth_level >= warmth_min — такая вещь не будет предложена никогда
+		if item.Warmth < spec.WarmthMin {
+			add(RuleWarmthBelowSpec, SeverityError, "warmth_level %d is below %s/%s warmth_min %d",
+				item.Warmth, item.Category, item.Subcategory, spec.WarmthMin)
+		}
+
+		switch {
+		case item.MaxTemp < spec.TempMinReco || item.MinTemp > spec.TempMaxReco:
+			add(RuleTempOutsideSpec, SeverityError, "temp range %d..%d does not overlap %s/%s recommendation %d..%d",
+				item.MinTemp, item.MaxTemp, item.Category, item.Subcategory, spec.TempMinReco, spec.TempMaxReco)
+		case item.MinTemp < spec.TempMinReco-SpecTempTolerance || item.MaxTemp > spec.TempMaxReco+SpecTempTolerance:
+			add(RuleTempExceedsSpec, SeverityWarning, "temp range %d..%d exceeds %s/%s recommendation %d..%d by more than %d°C",
+				item.MinTemp, item.MaxTemp, item.Category, item.Subcategory, spec.TempMinReco, spec.TempMaxReco, SpecTempTolerance)
+		}
+	}
+
+	if r, ok := SeasonTempRanges[item.Season]; ok && (item.MaxTemp < r[0] || item.MinTemp > r[1]) {
+		add(RuleSeasonVsTemp, SeverityWarning, "season %q contradicts temp range %d..%d (expected overlap with %d..%d)",
+			item.Season, item.MinTemp, item.MaxTemp, r[0], r[1])
+	}
+
+	return issues
+}
+
+// FindNearDuplicates ищет почти одинаковые вещи: та же подкатегория, цвет и узор,
+// совпадающие стиль и посадка, близкие уровни и похожие названия.
+// Помечается вещь из менее приоритетного источника (при равенстве — с большим id).
+func FindNearDuplicates(items []domain.ClothingItem, threshold float64) []Issue {
+	type entry struct {
+		item   domain.ClothingItem
+		tokens map[string]bool
+	}
+
+	buckets := make(map[string][]entry)
+	for _, it := range items {
+		k := strings.Join([]string{it.Category, it.Subcategory, it.BaseColour, it.Pattern}, "|")
+		buckets[k] = append(buckets[k], entry{item: it, tokens: nameTokens(it.Name)})
+	}
+
+	keys := make([]string, 0, len(buckets))
+	for k := range buckets {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var issues []Issue
+	flagged := make(map[int64]bool)
+	for _, k := range keys {
+		bucket := buckets[k]
+		for i := 0; i < len(bucket); i++ {
+			for j := i + 1; j < len(bucket); j++ {
+				a, b := bucket[i].item, bucket[j].item
+				if !similarAttributes(a, b) {
+					continue
+				}
+				sim := jaccard(bucket[i].tokens, bucket[j].tokens)
+				if sim < threshold {
+					continue
+				}
+
+				orig, dup := a, b
+				if sourcePriority[b.Source] > sourcePriority[a.Source] ||
+					(sourcePriority[b.Source] == sourcePriority[a.Source] && b.ID < a.ID) {
+					orig, dup = b, a
+				}
+				if flagged[dup.ID] {
+					continue
+				}
+				flagged[dup.ID] = true
+
+				issues = append(issues, Issue{
+					ItemID:   dup.ID,
+					Source:   dup.Source,
+					Rule:     RuleNearDuplicate,
+					Severity: SeverityWarning,
+					Message: fmt.Sprintf("near-duplicate of item %d (%s, %q vs %q, similarity %.2f)",
+						orig.ID, orig.Source, dup.Name, orig.Name, sim),
+				})
+			}
+		}
+	}
+	return issues
+}
+
+func similarAttributes(a, b domain.ClothingItem) bool {
+	return a.Style == b.Style &&
+		a.Fit == b.Fit &&
+		absInt16(a.Warmth-b.Warmth) <= 1 &&
+		absInt16(a.Formality-b.Formality) <= 1
+}
+
+// nameTokens нормализует название: нижний регистр, без пунктуации, множество слов.
+func nameTokens(name string) map[string]bool {
+	fields := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		tokens[f] = true
+	}
+	return tokens
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+	inter := 0
+	for t := range a {
+		if b[t] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
+
+func absInt16(v int16) int16 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,149 @@
+package catalog
+
+import (
+	"fmt"
+	"strings"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// Допустимые значения полей clothing_items — зеркало CHECK-ограничений из 0001_init_schema.sql.
+// При изменении схемы обновлять вместе с миграцией.
+var (
+	Genders     = []string{"unisex"}
+	Styles      = []string{"casual", "sport", "street", "classic", "business", "smart_casual", "outdoor"}
+	Usages      = []string{"daily", "work", "formal", "sport", "outdoor", "travel", "party"}
+	Seasons     = []string{"winter", "spring", "summer", "autumn", "all"}
+	BaseColours = []string{"black", "white", "gray", "navy", "beige", "brown", "green", "blue", "red", "pink", "yellow", "orange", "purple"}
+	Fits        = []string{"slim", "regular", "relaxed", "oversized"}
+	Patterns    = []string{"solid", "striped", "checked", "printed", "camo"}
+	Sources     = []string{"synthetic", "user", "partner", "manual"}
+	Categories  = []string{"outerwear", "upper", "lower", "footwear", "accessory"}
+)
+
+const (
+	MinFormality = 1
+	MaxFormality = 5
+	MinWarmth    = 1
+	MaxWarmth    = 10
+)
+
+// Validator проверяет вещи каталога по тем же правилам, что и схема БД:
+// CHECK-перечисления, диапазоны уровней и FK на subcategory_specs.
+type Validator struct {
+	specs map[string]domain.SubcategorySpec
+}
+
+// NewValidator создаёт валидатор по текущему словарю subcategory_specs.
+func NewValidator(specs []domain.SubcategorySpec) *Validator {
+	m := make(map[string]domain.SubcategorySpec, len(specs))
+	for _, spec := range specs {
+		m[specKey(spec.Category, spec.Subcategory)] = spec
+	}
+	return &Validator{specs: m}
+}
+
+// Spec возвращает норму для пары category/subcategory, если она есть в словаре.
+func (v *Validator) Spec(category, subcategory string) (domain.SubcategorySpec, bool) {
+	spec, ok := v.specs[specKey(category, subcategory)]
+	return spec, ok
+}
+
+// Validate возвращает список нарушений; пустой список означает, что вещь можно писать в БД.
+func (v *Validator) Validate(item domain.ClothingItem) []string {
+	var errs []string
+
+	if item.ID <= 0 {
+		errs = append(errs, fmt.Sprintf("id must be positive, got %d", item.ID))
+	}
+	if strings.TrimSpace(item.Name) == "" {
+		errs = append(errs, "name is required")
+	}
+	if strings.TrimSpace(item.IconEmoji) == "" {
+		errs = append(errs, "icon_emoji is required")
+	}
+
+	if _, ok := v.Spec(item.Category, item.Subcategory); !ok {
+		errs = append(errs, fmt.Sprintf("unknown category/subcategory: %s/%s", item.Category, item.Subcategory))
+	}
+
+	errs = appendEnumError(errs, "gender", item.Gender, Genders)
+	errs = appendEnumError(errs, "style", item.Style, Styles)
+	errs = appendEnumError(errs, "usage", item.Usage, Usages)
+	errs = appendEnumError(errs, "season", item.Season, Seasons)
+	errs = appendEnumError(errs, "base_colour", item.BaseColour, BaseColours)
+	errs = appendEnumError(errs, "fit", item.Fit, Fits)
+	errs = appendEnumError(errs, "pattern", item.Pattern, Patterns)
+	errs = appendEnumError(errs, "source", item.Source, Sources)
+
+	if item.Formality < MinFormality || item.Formality > MaxFormality {
+		errs = append(errs, fmt.Sprintf("formality_level must be between %d and %d, got %d", MinFormality, MaxFormality, item.Formality))
+	}
+	if item.Warmth < MinWarmth || item.Warmth > MaxWarmth {
+		errs = append(errs, fmt.Sprintf("warmth_level must be between %d and %d, got %d", MinWarmth, MaxWarmth, item.Warmth))
+	}
+	if item.MinTemp > item.MaxTemp {
+		errs = append(errs, fmt.Sprintf("min_temp (%d) cannot be greater than max_temp (%d)", item.MinTemp, item.MaxTemp))
+	}
+
+	return errs
+}
+
+// ValidateSpec проверяет норму подкатегории по CHECK-ограничениям subcategory_specs.
+func ValidateSpec(spec domain.SubcategorySpec) []string {
+	var errs []string
+
+	errs = appendEnumError(errs, "category", spec.Category, Categories)
+	if strings.TrimSpace(spec.Subcategory) == "" {
+		errs = append(errs, "subcategory is required")
+	}
+	if spec.WarmthMin < MinWarmth || spec.WarmthMin > MaxWarmth {
+		errs = append(errs, fmt.Sprintf("warmth_min must be between %d and %d, got %d", MinWarmth, MaxWarmth, spec.WarmthMin))
+	}
+	if spec.TempMinReco > spec.TempMaxReco {
+		errs = append(errs, fmt.Sprintf("temp_min_reco (%d) cannot be greater than temp_max_reco (%d)", spec.TempMinReco, spec.TempMaxReco))
+	}
+
+	return errs
+}
+
+// AddSpecs дополняет словарь валидатора (например, нормами из импортируемого файла).
+func (v *Validator) AddSpecs(specs []domain.SubcategorySpec) {
+	for _, spec := range specs {
+		v.specs[specKey(spec.Category, spec.Subcategory)] = spec
+	}
+}
+
+// ApplyDefaults заполняет поля, у которых в схеме есть DEFAULT.
+func ApplyDefaults(item *domain.ClothingItem) {
+	if item.Gender == "" {
+		item.Gender = "unisex"
+	}
+	if item.Source == "" {
+		item.Source = "synthetic"
+	}
+	if item.Materials == nil {
+		item.Materials = []string{}
+	}
+}
+
+// IsAllowed сообщает, входит ли значение в перечисление.
+func IsAllowed(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if a == value {
+			return true
+		}
+	}
+	return false
+}
+
+func appendEnumError(errs []string, field, value string, allowed []string) []string {
+	if IsAllowed(value, allowed) {
+		return errs
+	}
+	return append(errs, fmt.Sprintf("%s %q is not one of: %s", field, value, strings.Join(allowed, ", ")))
+}
+
+func specKey(category, subcategory string) string {
+	return category + "/" + subcategory
+}
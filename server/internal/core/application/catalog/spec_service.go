@@ -0,0 +1,207 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"outfitstyle/server/internal/core/application/planner"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
+)
+
+// Сетка, по которой считаем затронутые планы: каждый градус в диапазоне × каждое погодное условие.
+const (
+	PlanSweepMinTemp = -40
+	PlanSweepMaxTemp = 40
+)
+
+// ValidationError — норма не прошла проверку; Problems отдаётся клиенту как есть.
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid subcategory spec: " + strings.Join(e.Problems, "; ")
+}
+
+// SpecImpact — предпросмотр последствий изменения нормы.
+type SpecImpact struct {
+	Before *domain.SubcategorySpec `json:"before,omitempty"`
+	After  *domain.SubcategorySpec `json:"after,omitempty"`
+
+	// Items — вещи каталога, ссылающиеся на подкатегорию.
+	Items int64 `json:"items"`
+	// ItemsExcluded — вещи, которые перестанут проходить по warmth_min (отрицательное — вернутся в выдачу).
+	ItemsExcluded int64 `json:"items_excluded"`
+
+	// Plans — сколько точек сетки температура × погода получат другой план.
+	Plans      int `json:"plans"`
+	PlansTotal int `json:"plans_total"`
+
+	Applied bool `json:"applied"`
+}
+
+// SpecService — администрирование словаря subcategory_specs с проверкой и предпросмотром.
+type SpecService struct {
+	specRepo repo.SubcategorySpecRepository
+	itemRepo repo.ClothingItemRepository
+}
+
+// NewSpecService creates a new subcategory spec service
+func NewSpecService(specRepo repo.SubcategorySpecRepository, itemRepo repo.ClothingItemRepository) *SpecService {
+	return &SpecService{specRepo: specRepo, itemRepo: itemRepo}
+}
+
+func (s *SpecService) List(ctx context.Context) ([]domain.SubcategorySpec, error) {
+	return s.specRepo.ListAll(ctx)
+}
+
+func (s *SpecService) Get(ctx context.Context, category, subcategory string) (domain.SubcategorySpec, error) {
+	return s.specRepo.Get(ctx, category, subcategory)
+}
+
+// Create добавляет норму. При dryRun только считает последствия.
+func (s *SpecService) Create(ctx context.Context, spec domain.SubcategorySpec, dryRun bool) (SpecImpact, error) {
+	if problems := ValidateSpec(spec); len(problems) > 0 {
+		return SpecImpact{}, &ValidationError{Problems: problems}
+	}
+
+	specs, err := s.specRepo.ListAll(ctx)
+	if err != nil {
+		return SpecImpact{}, fmt.Errorf("list specs: %w", err)
+	}
+	if _, found := findSpec(specs, spec.Category, spec.Subcategory); found {
+		return SpecImpact{}, repo.ErrSpecExists
+	}
+
+	impact := SpecImpact{After: &spec}
+	impact.Plans, impact.PlansTotal = diffPlans(specs, append(cloneSpecs(specs), spec))
+
+	if dryRun {
+		return impact, nil
+	}
+	if err := s.specRepo.Create(ctx, spec); err != nil {
+		return impact, err
+	}
+	impact.Applied = true
+	return impact, nil
+}
+
+// Update меняет нормы существующей подкатегории. Переименование не поддерживается:
+// пара category/subcategory — ключ, на который ссылаются вещи.
+func (s *SpecService) Update(ctx context.Context, spec domain.SubcategorySpec, dryRun bool) (SpecImpact, error) {
+	if problems := ValidateSpec(spec); len(problems) > 0 {
+		return SpecImpact{}, &ValidationError{Problems: problems}
+	}
+
+	specs, err := s.specRepo.ListAll(ctx)
+	if err != nil {
+		return SpecImpact{}, fmt.Errorf("list specs: %w", err)
+	}
+	i, found := findSpec(specs, spec.Category, spec.Subcategory)
+	if !found {
+		return SpecImpact{}, repo.ErrSpecNotFound
+	}
+	before := specs[i]
+
+	impact := SpecImpact{Before: &before, After: &spec}
+
+	total, eligibleBefore, err := s.itemRepo.CountBySubcategory(ctx, spec.Category, spec.Subcategory, before.WarmthMin)
+	if err != nil {
+		return SpecImpact{}, fmt.Errorf("count items: %w", err)
+	}
+	_, eligibleAfter, err := s.itemRepo.CountBySubcategory(ctx, spec.Category, spec.Subcategory, spec.WarmthMin)
+	if err != nil {
+		return SpecImpact{}, fmt.Errorf("count items: %w", err)
+	}
+	impact.Items = total
+	impact.ItemsExcluded = eligibleBefore - eligibleAfter
+
+	after := cloneSpecs(specs)
+	after[i] = spec
+	impact.Plans, impact.PlansTotal = diffPlans(specs, after)
+
+	if dryRun {
+		return impact, nil
+	}
+	if err := s.specRepo.Update(ctx, spec); err != nil {
+		return impact, err
+	}
+	impact.Applied = true
+	return impact, nil
+}
+
+// Delete удаляет норму, если на неё не ссылается ни одна вещь (как ON DELETE RESTRICT в схеме).
+func (s *SpecService) Delete(ctx context.Context, category, subcategory string, dryRun bool) (SpecImpact, error) {
+	specs, err := s.specRepo.ListAll(ctx)
+	if err != nil {
+		return SpecImpact{}, fmt.Errorf("list specs: %w", err)
+	}
+	i, found := findSpec(specs, category, subcategory)
+	if !found {
+		return SpecImpact{}, repo.ErrSpecNotFound
+	}
+	before := specs[i]
+
+	impact := SpecImpact{Before: &before}
+
+	total, eligible, err := s.itemRepo.CountBySubcategory(ctx, category, subcategory, before.WarmthMin)
+	if err != nil {
+		return SpecImpact{}, fmt.Errorf("count items: %w", err)
+	}
+	impact.Items = total
+	impact.ItemsExcluded = eligible
+
+	after := append(cloneSpecs(specs[:i]), specs[i+1:]...)
+	impact.Plans, impact.PlansTotal = diffPlans(specs, after)
+
+	// Отказываем и в dry-run: предпросмотр должен показывать, что удаление не пройдёт
+	if total > 0 {
+		return impact, repo.ErrSpecInUse
+	}
+	if dryRun {
+		return impact, nil
+	}
+	if err := s.specRepo.Delete(ctx, category, subcategory); err != nil {
+		// Вещь могли добавить между подсчётом и удалением — FK всё равно не пропустит
+		if errors.Is(err, repo.ErrSpecInUse) {
+			return impact, err
+		}
+		return impact, fmt.Errorf("delete spec: %w", err)
+	}
+	impact.Applied = true
+	return impact, nil
+}
+
+// diffPlans прогоняет планировщик по сетке до и после изменения и считает различающиеся планы.
+func diffPlans(before, after []domain.SubcategorySpec) (changed, total int) {
+	for t := PlanSweepMinTemp; t <= PlanSweepMaxTemp; t++ {
+		for _, cond := range planner.Conditions {
+			total++
+			a := planner.BuildPlan(before, float64(t), string(cond))
+			b := planner.BuildPlan(after, float64(t), string(cond))
+			if !reflect.DeepEqual(a, b) {
+				changed++
+			}
+		}
+	}
+	return changed, total
+}
+
+func findSpec(specs []domain.SubcategorySpec, category, subcategory string) (int, bool) {
+	for i, spec := range specs {
+		if spec.Category == category && spec.Subcategory == subcategory {
+			return i, true
+		}
+	}
+	return -1, false
+}
+
+func cloneSpecs(specs []domain.SubcategorySpec) []domain.SubcategorySpec {
+	out := make([]domain.SubcategorySpec, len(specs), len(specs)+1)
+	copy(out, specs)
+	return out
+}
@@ -0,0 +1,112 @@
+package climate
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// bundledNormals — нормы 1991–2020 для городов, куда чаще всего смотрят пользователи.
+// Колонка location может перечислять синонимы через «|»: каждый станет отдельным местом.
+//
+//go:embed normals.csv
+var bundledNormals []byte
+
+var normalsHeader = []string{"location", "month", "temp_mean", "temp_min", "temp_max", "precip_mm", "precip_days"}
+
+// BundledNormals возвращает поставляемый набор норм.
+func BundledNormals() ([]domain.ClimateNormal, error) {
+	return ParseNormals(bytes.NewReader(bundledNormals))
+}
+
+// ParseNormals читает набор норм в формате normals.csv. Пустой precip_mm — «нет данных».
+func ParseNormals(r io.Reader) ([]domain.ClimateNormal, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(normalsHeader)
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	for i, col := range normalsHeader {
+		if strings.TrimSpace(header[i]) != col {
+			return nil, fmt.Errorf("unexpected column %d: %q (want %q)", i+1, header[i], col)
+		}
+	}
+
+	var normals []domain.ClimateNormal
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		n, err := parseNormal(rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		for _, name := range strings.Split(rec[0], "|") {
+			if name = NormalizeLocation(name); name == "" {
+				continue
+			}
+			n.Location = name
+			normals = append(normals, n)
+		}
+	}
+	return normals, nil
+}
+
+func parseNormal(rec []string) (domain.ClimateNormal, error) {
+	n := domain.ClimateNormal{Source: domain.ClimateSourceDataset}
+
+	month, err := strconv.Atoi(rec[1])
+	if err != nil || month < 1 || month > 12 {
+		return n, fmt.Errorf("invalid month %q", rec[1])
+	}
+	n.Month = month
+
+	fields := []struct {
+		name string
+		raw  string
+		dst  *float64
+	}{
+		{"temp_mean", rec[2], &n.TempMean},
+		{"temp_min", rec[3], &n.TempMin},
+		{"temp_max", rec[4], &n.TempMax},
+		{"precip_days", rec[6], &n.PrecipDays},
+	}
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(f.raw, 64)
+		if err != nil {
+			return n, fmt.Errorf("invalid %s %q", f.name, f.raw)
+		}
+		*f.dst = v
+	}
+	if n.TempMin > n.TempMean || n.TempMean > n.TempMax {
+		return n, fmt.Errorf("temperatures must satisfy temp_min <= temp_mean <= temp_max")
+	}
+	if n.PrecipDays < 0 || n.PrecipDays > float64(DaysInMonth(month)) {
+		return n, fmt.Errorf("precip_days %v out of range for month %d", n.PrecipDays, month)
+	}
+
+	if rec[5] != "" {
+		v, err := strconv.ParseFloat(rec[5], 64)
+		if err != nil || v < 0 {
+			return n, fmt.Errorf("invalid precip_mm %q", rec[5])
+		}
+		n.PrecipMM = &v
+	}
+	return n, nil
+}
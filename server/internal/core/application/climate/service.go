@@ -0,0 +1,93 @@
+package climate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
+)
+
+// MinHistoryDays — столько дней наблюдений за месяц нужно, чтобы доверять своей истории больше, чем набору норм.
+const MinHistoryDays = 10
+
+var ErrNoNormals = errors.New("no climate normals for location")
+
+// Service отдаёт месячную норму места, объединяя поставляемый набор и историю наблюдений.
+type Service struct {
+	repo repo.ClimateNormalRepository
+}
+
+func NewService(r repo.ClimateNormalRepository) *Service {
+	return &Service{repo: r}
+}
+
+// Normal возвращает норму места за месяц или ErrNoNormals.
+func (s *Service) Normal(ctx context.Context, location string, month time.Month) (domain.ClimateNormal, error) {
+	normals, err := s.repo.Get(ctx, NormalizeLocation(location), int(month))
+	if err != nil {
+		return domain.ClimateNormal{}, fmt.Errorf("get climate normals: %w", err)
+	}
+
+	var dataset, history *domain.ClimateNormal
+	for i := range normals {
+		switch normals[i].Source {
+		case domain.ClimateSourceDataset:
+			dataset = &normals[i]
+		case domain.ClimateSourceHistory:
+			history = &normals[i]
+		}
+	}
+
+	n, ok := Merge(dataset, history)
+	if !ok {
+		return domain.ClimateNormal{}, ErrNoNormals
+	}
+	return n, nil
+}
+
+// Merge объединяет нормы одного места и месяца. Температуры берутся из истории, если она
+// достаточно длинная (она отражает последние годы), осадки — из набора: в weather_data
+// нет количества осадков, а дни с осадками по запросам пользователей оцениваются грубо.
+func Merge(dataset, history *domain.ClimateNormal) (domain.ClimateNormal, bool) {
+	trusted := history != nil && history.Observations >= MinHistoryDays
+
+	switch {
+	case dataset == nil && history == nil:
+		return domain.ClimateNormal{}, false
+	case dataset == nil:
+		return *history, true
+	case !trusted:
+		return *dataset, true
+	}
+
+	n := *dataset
+	n.TempMean = history.TempMean
+	n.TempMin = history.TempMin
+	n.TempMax = history.TempMax
+	n.Source = domain.ClimateSourceHistory
+	n.Observations = history.Observations
+	return n, true
+}
+
+// WetDayShare — доля дней месяца с осадками.
+func WetDayShare(n domain.ClimateNormal) float64 {
+	if n.Month < 1 || n.Month > 12 {
+		return 0
+	}
+	return n.PrecipDays / float64(DaysInMonth(n.Month))
+}
+
+// DaysInMonth — число дней месяца в невисокосном году.
+func DaysInMonth(month int) int {
+	return time.Date(2001, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// NormalizeLocation приводит название места к ключу норм так же, как кэш погоды:
+// регистр и лишние пробелы не важны.
+func NormalizeLocation(location string) string {
+	return strings.Join(strings.Fields(strings.ToLower(location)), " ")
+}
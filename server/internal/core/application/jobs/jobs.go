@@ -0,0 +1,115 @@
+// Package jobs — надёжная очередь фоновых задач поверх таблицы jobs.
+//
+// Задача переживает рестарт и падение сервера: она лежит в Postgres, воркеры забирают её
+// через FOR UPDATE SKIP LOCKED с арендой, а задачу упавшего воркера после истечения аренды
+// забирает другой. Поэтому обработчик должен быть идемпотентным — повтор возможен.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// Значения по умолчанию для вида задачи.
+const (
+	DefaultMaxAttempts = 5
+	DefaultTimeout     = time.Minute
+)
+
+// Kind описывает вид задачи с нагрузкой типа T. Нагрузка хранится в JSON,
+// поэтому T должен сериализоваться без потерь.
+type Kind[T any] struct {
+	Name        string
+	MaxAttempts int           // 0 — DefaultMaxAttempts
+	Timeout     time.Duration // 0 — DefaultTimeout; должен быть меньше аренды очереди
+}
+
+func (k Kind[T]) maxAttempts() int {
+	if k.MaxAttempts > 0 {
+		return k.MaxAttempts
+	}
+	return DefaultMaxAttempts
+}
+
+func (k Kind[T]) timeout() time.Duration {
+	if k.Timeout > 0 {
+		return k.Timeout
+	}
+	return DefaultTimeout
+}
+
+// Enqueue ставит задачу в очередь. Если задан UniqueKey и такая задача уже есть,
+// новая не создаётся и ошибки нет.
+func (k Kind[T]) Enqueue(ctx context.Context, q *Queue, payload T, opts ...Option) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode %s payload", k.Name)
+	}
+
+	job := &domain.Job{
+		Kind:        k.Name,
+		Payload:     data,
+		MaxAttempts: k.maxAttempts(),
+		RunAt:       time.Now(),
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	return q.enqueue(ctx, job)
+}
+
+// Handle регистрирует обработчик вида задачи. Регистрировать нужно до Run;
+// повторная регистрация и таймаут не меньше аренды — ошибка конфигурации (паника).
+func Handle[T any](q *Queue, k Kind[T], fn func(ctx context.Context, payload T) error) {
+	q.register(k.Name, k.timeout(), func(ctx context.Context, raw json.RawMessage) error {
+		var payload T
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return Permanent(errors.Wrapf(err, "failed to decode %s payload", k.Name))
+		}
+		return fn(ctx, payload)
+	})
+}
+
+// Option настраивает ставящуюся в очередь задачу.
+type Option func(*domain.Job)
+
+// RunAt откладывает выполнение до момента t.
+func RunAt(t time.Time) Option {
+	return func(j *domain.Job) { j.RunAt = t }
+}
+
+// Delay откладывает выполнение на d.
+func Delay(d time.Duration) Option {
+	return func(j *domain.Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// UniqueKey не даёт поставить вторую задачу того же вида с тем же ключом,
+// пока первая хранится в таблице (выполненные удаляются через Config.Retention).
+func UniqueKey(key string) Option {
+	return func(j *domain.Job) { j.UniqueKey = key }
+}
+
+// permanentError — ошибка, которую бессмысленно повторять.
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку обработчика как неисправимую: задача сразу становится dead.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err: err}
+}
+
+// IsPermanent сообщает, помечена ли ошибка через Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
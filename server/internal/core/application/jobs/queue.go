@@ -0,0 +1,289 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	mathrand "math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// Config задаёт параметры очереди.
+type Config struct {
+	Workers      int           // одновременно выполняемых задач
+	PollInterval time.Duration // как часто искать готовые задачи
+	Lease        time.Duration // аренда забранной задачи; после неё задачу заберёт другой воркер
+	DrainTimeout time.Duration // сколько ждать выполняющиеся задачи при остановке
+	BaseBackoff  time.Duration // пауза перед второй попыткой, дальше удваивается
+	MaxBackoff   time.Duration
+	Retention    time.Duration // сколько хранить выполненные задачи
+}
+
+// handler — зарегистрированный обработчик вида задачи.
+type handler struct {
+	timeout time.Duration
+	run     func(ctx context.Context, payload json.RawMessage) error
+}
+
+// Queue выполняет задачи из таблицы jobs.
+type Queue struct {
+	repo   repositories.JobRepository
+	config Config
+	logger *zap.Logger
+
+	mu       sync.RWMutex
+	handlers map[string]handler
+	wake     chan struct{}
+}
+
+// NewQueue creates a new job queue.
+func NewQueue(repo repositories.JobRepository, config Config, logger *zap.Logger) *Queue {
+	return &Queue{
+		repo:     repo,
+		config:   config,
+		logger:   logger,
+		handlers: make(map[string]handler),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+func (q *Queue) register(kind string, timeout time.Duration, run func(context.Context, json.RawMessage) error) {
+	if timeout >= q.config.Lease {
+		panic(fmt.Sprintf("jobs: timeout of %s (%s) must be shorter than the lease (%s)", kind, timeout, q.config.Lease))
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, ok := q.handlers[kind]; ok {
+		panic(fmt.Sprintf("jobs: handler for %s registered twice", kind))
+	}
+	q.handlers[kind] = handler{timeout: timeout, run: run}
+}
+
+func (q *Queue) enqueue(ctx context.Context, job *domain.Job) error {
+	created, err := q.repo.Enqueue(ctx, job)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enqueue %s", job.Kind)
+	}
+	// Готовую задачу своего вида берём сразу, не дожидаясь опроса
+	if created && !job.RunAt.After(time.Now()) {
+		q.notify()
+	}
+	return nil
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) kinds() []string {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	kinds := make([]string, 0, len(q.handlers))
+	for kind := range q.handlers {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+func (q *Queue) handler(kind string) handler {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	return q.handlers[kind]
+}
+
+// Run выполняет задачи, пока не отменён ctx, затем дожидается выполняющихся задач
+// (не дольше DrainTimeout). Не успевшие задачи прерываются и возвращаются в очередь
+// без списания попытки. Run блокируется до конца остановки.
+func (q *Queue) Run(ctx context.Context) {
+	kinds := q.kinds()
+	if len(kinds) == 0 {
+		return
+	}
+
+	// Контекст задач не связан с ctx: остановка сначала даёт им доработать
+	workCtx, abort := context.WithCancel(context.WithoutCancel(ctx))
+	defer abort()
+
+	slots := make(chan struct{}, q.config.Workers)
+	var wg sync.WaitGroup
+
+	poll := time.NewTicker(q.config.PollInterval)
+	defer poll.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	q.logger.Info("Job queue started", zap.Strings("kinds", kinds), zap.Int("workers", q.config.Workers))
+	for {
+		q.dispatch(ctx, workCtx, kinds, slots, &wg)
+
+		select {
+		case <-ctx.Done():
+			q.drain(&wg, abort)
+			return
+		case <-poll.C:
+		case <-q.wake:
+		case <-cleanup.C:
+			q.cleanup(ctx)
+		}
+	}
+}
+
+// dispatch забирает столько готовых задач, сколько свободно воркеров.
+func (q *Queue) dispatch(ctx, workCtx context.Context, kinds []string, slots chan struct{}, wg *sync.WaitGroup) {
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return
+	}
+
+	now := time.Now()
+	jobs, err := q.repo.Claim(ctx, kinds, now, now.Add(q.config.Lease), free)
+	if err != nil {
+		if ctx.Err() == nil {
+			q.logger.Error("Failed to claim jobs", zap.Error(err))
+		}
+		return
+	}
+
+	for _, job := range jobs {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(job domain.Job) {
+			defer wg.Done()
+			q.execute(workCtx, job)
+			<-slots
+			// Освободился воркер — возможно, задачи ещё остались
+			q.notify()
+		}(job)
+	}
+}
+
+// drain ждёт выполняющиеся задачи, а по истечении DrainTimeout прерывает их.
+func (q *Queue) drain(wg *sync.WaitGroup, abort context.CancelFunc) {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.logger.Info("Job queue stopped")
+		return
+	case <-time.After(q.config.DrainTimeout):
+	}
+
+	q.logger.Warn("Job queue drain timed out, interrupting running jobs")
+	abort()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		// Обработчик не реагирует на отмену — задачу вернёт истечение аренды
+		q.logger.Error("Jobs did not stop after interruption")
+	}
+}
+
+// execute выполняет задачу и записывает результат.
+func (q *Queue) execute(ctx context.Context, job domain.Job) {
+	h := q.handler(job.Kind)
+	start := time.Now()
+
+	jobCtx, cancel := context.WithTimeout(ctx, h.timeout)
+	err := safeRun(jobCtx, h, job.Payload)
+	cancel()
+
+	// Результат записываем и после прерывания: иначе задача ждала бы истечения аренды
+	recordCtx, cancelRecord := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+	defer cancelRecord()
+
+	fields := []zap.Field{
+		zap.Int64("job_id", job.ID),
+		zap.String("kind", job.Kind),
+		zap.Int("attempt", job.Attempts),
+		zap.Duration("duration", time.Since(start)),
+	}
+
+	// Аренда из Claim: по ней репозиторий отличает наш запуск от повторного у другого воркера
+	var lease time.Time
+	if job.LockedUntil != nil {
+		lease = *job.LockedUntil
+	}
+
+	switch {
+	case err == nil:
+		err = q.repo.Complete(recordCtx, job.ID, lease)
+	case ctx.Err() != nil:
+		// Прервано остановкой сервера — попытка не считается
+		q.logger.Warn("Job interrupted by shutdown", fields...)
+		err = q.repo.Release(recordCtx, job.ID, lease)
+	case IsPermanent(err) || job.Attempts >= job.MaxAttempts:
+		q.logger.Error("Job failed permanently", append(fields, zap.Error(err))...)
+		err = q.repo.Bury(recordCtx, job.ID, lease, truncate(err.Error(), 1000))
+	default:
+		next := time.Now().Add(q.backoff(job.Attempts))
+		q.logger.Warn("Job failed, will retry", append(fields, zap.Error(err), zap.Time("next_attempt_at", next))...)
+		err = q.repo.Retry(recordCtx, job.ID, lease, next, truncate(err.Error(), 1000))
+	}
+	if errors.Is(err, repositories.ErrJobLeaseLost) {
+		// Задачу уже выполняет другой воркер — его результат главнее
+		q.logger.Warn("Job lease expired before the result was recorded", fields...)
+		return
+	}
+	if err != nil {
+		q.logger.Error("Failed to record job result", append(fields, zap.Error(err))...)
+	}
+}
+
+// safeRun вызывает обработчик, превращая панику в ошибку.
+func safeRun(ctx context.Context, h handler, payload json.RawMessage) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic: %v", r)
+		}
+	}()
+	return h.run(ctx, payload)
+}
+
+// backoff — экспоненциальная пауза перед следующей попыткой с разбросом ±10%.
+func (q *Queue) backoff(attempts int) time.Duration {
+	d := float64(q.config.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if limit := float64(q.config.MaxBackoff); d > limit {
+		d = limit
+	}
+	d *= 0.9 + 0.2*mathrand.Float64()
+	return time.Duration(d)
+}
+
+// cleanup удаляет выполненные задачи старше Retention.
+func (q *Queue) cleanup(ctx context.Context) {
+	deleted, err := q.repo.DeleteDone(ctx, time.Now().Add(-q.config.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			q.logger.Error("Failed to clean up jobs", zap.Error(err))
+		}
+		return
+	}
+	if deleted > 0 {
+		q.logger.Info("Old jobs cleaned up", zap.Int64("deleted", deleted))
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n]
+}
@@ -0,0 +1,106 @@
+package planner
+
+import (
+	"context"
+	"fmt"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// WithAlertRules задаёт пороги предупреждений об опасной погоде (по умолчанию alerts.DefaultRules).
+func (p *OutfitPlanner) WithAlertRules(rules alerts.Rules) *OutfitPlanner {
+	p.rules = rules
+	return p
+}
+
+// GeneratePlanWithAlerts строит план и применяет к нему уже вычисленные предупреждения.
+// Нужен, когда у вызывающего есть полный прогноз (ветер, минимум и максимум за день),
+// а не только температура и условие, как у GeneratePlan.
+func (p *OutfitPlanner) GeneratePlanWithAlerts(ctx context.Context, temperature float64, weatherCondition string, userPreferences map[string]interface{}, active []alerts.Alert) (*OutfitPlan, error) {
+	specs, err := p.specRepo.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subcategory specs: %w", err)
+	}
+
+	plan := BuildPlan(specs, temperature, weatherCondition)
+	ApplyAlerts(plan, specs, active)
+
+	return &OutfitPlan{
+		Temperature:      temperature,
+		WeatherCondition: weatherCondition,
+		UserPreferences:  userPreferences,
+		Plan:             plan,
+		Alerts:           active,
+	}, nil
+}
+
+// DayConditions — условия для правил предупреждений по погоде дня из планировщика.
+func DayConditions(w DayWeather) alerts.Conditions {
+	return alerts.Conditions{
+		Temperature: w.Temperature,
+		FeelsLike:   w.Temperature,
+		MinTemp:     w.Temperature,
+		MaxTemp:     w.Temperature,
+		WindSpeed:   w.WindSpeed,
+		Weather:     string(w.Condition),
+	}
+}
+
+// ApplyAlerts добавляет в план защитные подкатегории предупреждений — первыми в своей категории
+// и независимо от температурных норм — и убирает нежелательные. Подкатегории, которых нет
+// в словаре, пропускаются: правила не должны ломать план при изменённом словаре.
+func ApplyAlerts(plan map[string][]domain.SubcategorySpec, specs []domain.SubcategorySpec, active []alerts.Alert) {
+	if len(active) == 0 {
+		return
+	}
+
+	index := make(map[alerts.Item]domain.SubcategorySpec, len(specs))
+	for _, spec := range specs {
+		index[alerts.Item{Category: spec.Category, Subcategory: spec.Subcategory}] = spec
+	}
+
+	avoid := make(map[alerts.Item]bool)
+	forced := make(map[string][]domain.SubcategorySpec)
+	seen := make(map[alerts.Item]bool)
+	for _, a := range active {
+		for _, item := range a.Avoid {
+			avoid[item] = true
+		}
+		for _, item := range a.Protective {
+			spec, ok := index[item]
+			if !ok || seen[item] {
+				continue
+			}
+			seen[item] = true
+			forced[item.Category] = append(forced[item.Category], spec)
+		}
+	}
+
+	for category, categorySpecs := range plan {
+		kept := categorySpecs[:0]
+		for _, spec := range categorySpecs {
+			item := alerts.Item{Category: spec.Category, Subcategory: spec.Subcategory}
+			if !avoid[item] && !seen[item] {
+				kept = append(kept, spec)
+			}
+		}
+		plan[category] = kept
+	}
+
+	for category, specs := range forced {
+		var merged []domain.SubcategorySpec
+		for _, spec := range specs {
+			if !avoid[alerts.Item{Category: spec.Category, Subcategory: spec.Subcategory}] {
+				merged = append(merged, spec)
+			}
+		}
+		plan[category] = append(merged, plan[category]...)
+	}
+
+	for category, categorySpecs := range plan {
+		if len(categorySpecs) == 0 {
+			delete(plan, category)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package planner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/application/climate"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// ForecastHorizon — на сколько дней вперёд есть прогноз; дальше план строится по климатической норме.
+const ForecastHorizon = 5
+
+// Basis — на чём основан план.
+type Basis string
+
+const (
+	BasisForecast Basis = "forecast"
+	BasisClimate  Basis = "climate"
+)
+
+var (
+	ErrDateInPast = errors.New("plan date is in the past")
+	// ErrNoWeather — нет ни прогноза, ни нормы для места.
+	ErrNoWeather = errors.New("no forecast or climate normals for location")
+)
+
+// DayWeather — погода на день, по которой подбирается план.
+type DayWeather struct {
+	Temperature float64
+	Condition   WeatherCondition
+	WindSpeed   float64 // м/с, 0 — неизвестен
+}
+
+// ForecastSource даёт прогноз на день в пределах ForecastHorizon.
+type ForecastSource interface {
+	DayForecast(ctx context.Context, location string, date time.Time) (DayWeather, error)
+}
+
+// ClimateSource даёт месячную климатическую норму места (climate.Service).
+type ClimateSource interface {
+	Normal(ctx context.Context, location string, month time.Month) (domain.ClimateNormal, error)
+}
+
+// WithWeather подключает источники погоды для PlanForDate. Любой из них может быть nil.
+func (p *OutfitPlanner) WithWeather(forecast ForecastSource, normals ClimateSource) *OutfitPlanner {
+	p.forecast = forecast
+	p.climate = normals
+	return p
+}
+
+// PlanForDate строит план на дату: в пределах ForecastHorizon — по прогнозу, дальше
+// (или если прогноз не подключён либо недоступен) — по климатической норме месяца. Basis
+// в ответе показывает, какой из вариантов сработал.
+func (p *OutfitPlanner) PlanForDate(ctx context.Context, location string, date time.Time, userPreferences map[string]interface{}) (*OutfitPlan, error) {
+	today := truncateDay(p.now().In(date.Location()))
+	day := truncateDay(date)
+	if day.Before(today) {
+		return nil, ErrDateInPast
+	}
+	daysAhead := calendarDays(today, day)
+
+	var (
+		weather DayWeather
+		basis   Basis
+		normal  *domain.ClimateNormal
+	)
+	if p.forecast != nil && daysAhead <= ForecastHorizon {
+		w, err := p.forecast.DayForecast(ctx, location, day)
+		switch {
+		case err == nil:
+			weather, basis = w, BasisForecast
+		case p.climate == nil:
+			return nil, fmt.Errorf("get forecast: %w", err)
+		}
+		// Иначе прогноз недоступен (провайдеры не ответили, у места уже другие сутки) — берём норму
+	}
+	if basis == "" {
+		if p.climate == nil {
+			return nil, ErrNoWeather
+		}
+		n, err := p.climate.Normal(ctx, location, day.Month())
+		if errors.Is(err, climate.ErrNoNormals) {
+			return nil, ErrNoWeather
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get climate normals: %w", err)
+		}
+		weather, basis, normal = WeatherFromNormal(n), BasisClimate, &n
+	}
+
+	// Норма — не прогноз: предупреждения об опасной погоде строим только по прогнозу
+	var active []alerts.Alert
+	if basis == BasisForecast {
+		active = p.rules.Evaluate(DayConditions(weather))
+	}
+
+	plan, err := p.GeneratePlanWithAlerts(ctx, weather.Temperature, string(weather.Condition), userPreferences, active)
+	if err != nil {
+		return nil, err
+	}
+	plan.Location = location
+	plan.Date = day.Format("2006-01-02")
+	plan.Basis = basis
+	plan.Climate = normal
+	return plan, nil
+}
+
+// WeatherFromNormal сводит норму к погоде дня: средняя температура; осадки, если они бывают
+// хотя бы в трети дней месяца (снег — при средней не выше нуля), ясно — если почти никогда.
+// Порог занижен намеренно: в поездку лучше взять непромокаемое, чем промокнуть.
+func WeatherFromNormal(n domain.ClimateNormal) DayWeather {
+	w := DayWeather{Temperature: n.TempMean, Condition: Clouds}
+	switch share := climate.WetDayShare(n); {
+	case share >= 0.3:
+		w.Condition = Rain
+		if n.TempMean <= 0 {
+			w.Condition = Snow
+		}
+	case share < 0.1:
+		w.Condition = Clear
+	}
+	return w
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// calendarDays — число календарных дней от from до to. Даты переносятся в UTC: в местном
+// времени сутки на переходе на летнее время короче 24 часов.
+func calendarDays(from, to time.Time) int {
+	fy, fm, fd := from.Date()
+	ty, tm, td := to.Date()
+	a := time.Date(fy, fm, fd, 0, 0, 0, 0, time.UTC)
+	b := time.Date(ty, tm, td, 0, 0, 0, 0, time.UTC)
+	return int(b.Sub(a).Hours() / 24)
+}
@@ -2,9 +2,11 @@ package planner
 
 import (
 	"context"
-	"fmt"
-	"outfit-style-rec/server/internal/core/domain"
-	"outfit-style-rec/server/internal/core/repo"
+	"time"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
 )
 
 type WeatherCondition string
@@ -19,13 +21,23 @@ const (
 	Thunderstorm WeatherCondition = "thunderstorm"
 )
 
+// Conditions — все погодные условия, которые различает планировщик.
+var Conditions = []WeatherCondition{Clear, Clouds, Rain, Drizzle, Snow, Mist, Thunderstorm}
+
 type OutfitPlanner struct {
 	specRepo repo.SubcategorySpecRepository
+
+	forecast ForecastSource
+	climate  ClimateSource
+	rules    alerts.Rules
+	now      func() time.Time
 }
 
 func NewOutfitPlanner(specRepo repo.SubcategorySpecRepository) *OutfitPlanner {
 	return &OutfitPlanner{
 		specRepo: specRepo,
+		rules:    alerts.DefaultRules(),
+		now:      time.Now,
 	}
 }
 
@@ -34,21 +46,33 @@ type OutfitPlan struct {
 	WeatherCondition string                           `json:"weather_condition"`
 	UserPreferences map[string]interface{}           `json:"user_preferences"`
 	Plan           map[string][]domain.SubcategorySpec `json:"plan"`
+
+	// Заполняются PlanForDate: на какую дату план и по прогнозу он или по климатической норме
+	Location string                `json:"location,omitempty"`
+	Date     string                `json:"date,omitempty"`
+	Basis    Basis                 `json:"basis,omitempty"`
+	Climate  *domain.ClimateNormal `json:"climate,omitempty"`
+
+	// Предупреждения об опасной погоде: их защитные подкатегории уже добавлены в Plan
+	Alerts []alerts.Alert `json:"alerts,omitempty"`
 }
 
+// GeneratePlan строит план только по словарю норм, без предупреждений об опасной погоде:
+// на нём линтер проверяет покрытие словаря. План с предупреждениями — GeneratePlanWithAlerts.
 func (p *OutfitPlanner) GeneratePlan(ctx context.Context, temperature float64, weatherCondition string, userPreferences map[string]interface{}) (*OutfitPlan, error) {
-	specs, err := p.specRepo.ListAll(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get subcategory specs: %w", err)
-	}
+	return p.GeneratePlanWithAlerts(ctx, temperature, weatherCondition, userPreferences, nil)
+}
 
+// BuildPlan подбирает подкатегории по словарю норм без обращения к БД.
+// Используется GeneratePlan и офлайн-проверками словаря (предпросмотр изменений, линтер).
+func BuildPlan(specs []domain.SubcategorySpec, temperature float64, weatherCondition string) map[string][]domain.SubcategorySpec {
 	plan := make(map[string][]domain.SubcategorySpec)
 	
 	for _, spec := range specs {
 		// Check if temperature is within recommended range
 		if float64(spec.TempMinReco) <= temperature && float64(spec.TempMaxReco) >= temperature {
 			// Check weather condition appropriateness
-			weatherOK := p.isWeatherConditionAppropriate(spec, WeatherCondition(weatherCondition))
+			weatherOK := isWeatherConditionAppropriate(spec, WeatherCondition(weatherCondition))
 			if weatherOK {
 				plan[spec.Category] = append(plan[spec.Category], spec)
 			}
@@ -72,15 +96,10 @@ func (p *OutfitPlanner) GeneratePlan(ctx context.Context, temperature float64, w
 		}
 	}
 
-	return &OutfitPlan{
-		Temperature:      temperature,
-		WeatherCondition: weatherCondition,
-		UserPreferences:  userPreferences,
-		Plan:            plan,
-	}, nil
+	return plan
 }
 
-func (p *OutfitPlanner) isWeatherConditionAppropriate(spec domain.SubcategorySpec, weather WeatherCondition) bool {
+func isWeatherConditionAppropriate(spec domain.SubcategorySpec, weather WeatherCondition) bool {
 	switch weather {
 	case Rain, Drizzle:
 		return spec.RainOK
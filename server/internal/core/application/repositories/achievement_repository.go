@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// AchievementRepository defines storage for achievement rules and user progress.
+type AchievementRepository interface {
+	// ListDefinitions возвращает активные достижения с правилами. Правило, которое не
+	// удалось разобрать, пропускается с предупреждением в логе.
+	ListDefinitions(ctx context.Context) ([]domain.AchievementDefinition, error)
+	// UserStats возвращает показатели пользователя для правил (ключи — domain.Stat*).
+	UserStats(ctx context.Context, userID int) (map[string]float64, error)
+	// ApplyEvent одной транзакцией отмечает событие обработанным, продвигает прогресс
+	// и открывает достигнутые достижения. Возвращает только впервые открытые; для уже
+	// обработанного eventKey ничего не меняет и возвращает пустой список.
+	ApplyEvent(ctx context.Context, userID int, eventKey string, steps []domain.AchievementStep) ([]domain.Achievement, error)
+}
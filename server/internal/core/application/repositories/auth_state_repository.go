@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// AuthStateRepository хранит состояние аутентификации, общее для всех реплик API.
+// Ключи — хэши кодов и токенов, а не сами значения.
+type AuthStateRepository interface {
+	// SaveToken сохраняет одноразовый токен, заменяя токен с тем же хэшем.
+	SaveToken(ctx context.Context, token domain.AuthToken) error
+	// ConsumeToken атомарно забирает токен одного из видов kinds: второй вызов с тем же
+	// хэшем, в том числе из другой реплики, получит nil. Просроченный токен тоже удаляется
+	// и возвращается — проверка срока на вызывающем.
+	ConsumeToken(ctx context.Context, hash string, kinds []string) (*domain.AuthToken, error)
+	// AllowAction разрешает действие с ключом key не чаще раза в window.
+	AllowAction(ctx context.Context, key string, window time.Duration, now time.Time) (bool, error)
+	// LockedUntil возвращает, до какого момента заблокирован вход по любому из ключей; ноль — не заблокирован.
+	LockedUntil(ctx context.Context, keys []string, now time.Time) (time.Time, error)
+	// RecordLoginFailure засчитывает неудачную попытку входа по ключу. Если попыток за последние
+	// window набралось limit, ключ блокируется на lockFor, счётчик сбрасывается, и возвращается
+	// конец блокировки; иначе — ноль. Попытки одного ключа из разных реплик учитываются по очереди.
+	RecordLoginFailure(ctx context.Context, key string, limit int, window, lockFor time.Duration, now time.Time) (time.Time, error)
+	// ResetLoginFailures сбрасывает счётчики неудачных попыток ключей после успешного входа.
+	ResetLoginFailures(ctx context.Context, keys []string) error
+	// DeleteExpired удаляет токены, ограничения, попытки и блокировки, истёкшие к now.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
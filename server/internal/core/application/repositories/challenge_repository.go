@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// ChallengeRepository defines persistence for check-in streaks and monthly challenges.
+type ChallengeRepository interface {
+	// ListChallenges возвращает активные испытания месяца period (первое число).
+	ListChallenges(ctx context.Context, period time.Time) ([]domain.Challenge, error)
+	// ListProgress возвращает активные испытания месяца period с прогрессом пользователя.
+	ListProgress(ctx context.Context, userID int, period time.Time) ([]domain.ChallengeProgress, error)
+	// GetStreak возвращает серию пользователя (нулевую, если заходов не было).
+	GetStreak(ctx context.Context, userID int) (*domain.Streak, error)
+	// ApplyEvent в одной транзакции засчитывает заход, продвигает испытания и выдаёт награды.
+	// Повторно применённое событие ничего не меняет — тогда возвращается nil.
+	ApplyEvent(ctx context.Context, event domain.GamificationEvent) (*domain.GamificationResult, error)
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// DigestRepository defines the interface for morning digest subscriptions and deliveries.
+type DigestRepository interface {
+	// GetSubscription возвращает подписку пользователя или nil, если её нет.
+	GetSubscription(ctx context.Context, userID int) (*domain.DigestSubscription, error)
+	// SaveSubscription создаёт или заменяет подписку пользователя.
+	SaveSubscription(ctx context.Context, sub *domain.DigestSubscription) error
+
+	// ListDue возвращает включённые подписки с next_run_at не позже now, самые старые первыми.
+	ListDue(ctx context.Context, now time.Time, limit int) ([]domain.DigestSubscription, error)
+	// ScheduleNext переносит следующий запуск подписки.
+	ScheduleNext(ctx context.Context, userID int, next time.Time) error
+	// GetTarget возвращает адрес, город профиля и место по умолчанию пользователя; nil — пользователя нет.
+	GetTarget(ctx context.Context, userID int) (*domain.NotificationTarget, error)
+
+	// ClaimDelivery занимает дайджест на местную дату; false — он уже отправлялся.
+	ClaimDelivery(ctx context.Context, userID int, day time.Time) (bool, error)
+	// FinishDelivery записывает итог доставки: sent, skipped или failed с текстом ошибки.
+	FinishDelivery(ctx context.Context, userID int, day time.Time, status, errText string) error
+}
@@ -3,3 +3,27 @@ package repositories
 import "errors"
 
 var ErrEmailAlreadyExists = errors.New("email already exists")
+
+// ErrLocationKindTaken — у пользователя уже есть место «дом» или «работа».
+var ErrLocationKindTaken = errors.New("location of this kind already exists")
+
+// ErrTooManyLocations — у пользователя уже сохранено максимальное число мест.
+var ErrTooManyLocations = errors.New("too many saved locations")
+
+// ErrDefaultLocationTaken — место по умолчанию одновременно назначил другой запрос.
+var ErrDefaultLocationTaken = errors.New("default location changed concurrently")
+
+// ErrRefreshTokenInvalid — refresh-токена нет, он истёк или его сессия отозвана.
+var ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+
+// ErrRefreshTokenReused — предъявлен уже заменённый refresh-токен; его сессия отозвана.
+var ErrRefreshTokenReused = errors.New("refresh token reused")
+
+// ErrJobLeaseLost — аренда задачи истекла, и её забрал другой воркер; результат не записан.
+var ErrJobLeaseLost = errors.New("job lease lost")
+
+// ErrWebhookLeaseLost — аренда доставки вебхука истекла, и её забрал другой экземпляр; итог попытки не записан.
+var ErrWebhookLeaseLost = errors.New("webhook delivery lease lost")
+
+// ErrOutboxLeaseLost — аренда события outbox истекла, и его забрал другой relay; итог публикации не записан.
+var ErrOutboxLeaseLost = errors.New("outbox event lease lost")
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// JobRepository defines the interface for the background job queue.
+type JobRepository interface {
+	// Enqueue ставит задачу в очередь и заполняет ID; false — задача с тем же
+	// (kind, unique_key) уже есть, и новая не создана.
+	Enqueue(ctx context.Context, job *domain.Job) (bool, error)
+	// Claim забирает до limit готовых задач указанных видов: queued с наступившим run_at
+	// и running с истёкшей арендой. Задачи переходят в running до leaseUntil, attempts растёт.
+	Claim(ctx context.Context, kinds []string, now, leaseUntil time.Time, limit int) ([]domain.Job, error)
+	// Complete, Retry, Bury и Release записывают результат, только пока задача running
+	// с арендой lease, полученной в Claim; иначе — ErrJobLeaseLost.
+
+	// Complete отмечает задачу выполненной.
+	Complete(ctx context.Context, id int64, lease time.Time) error
+	// Retry возвращает задачу в очередь до runAt с текстом ошибки.
+	Retry(ctx context.Context, id int64, lease, runAt time.Time, errText string) error
+	// Bury отмечает задачу dead с текстом ошибки.
+	Bury(ctx context.Context, id int64, lease time.Time, errText string) error
+	// Release возвращает прерванную остановкой сервера задачу в очередь, не засчитывая попытку.
+	Release(ctx context.Context, id int64, lease time.Time) error
+	// DeleteDone удаляет выполненные задачи, завершённые раньше before; dead остаются для разбора.
+	DeleteDone(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// OutboxRepository defines the relay side of the event outbox. События записывают
+// репозитории-источники в своих транзакциях (см. RecommendationRepository.CreateRecommendation,
+// UserRepository.RateRecommendation).
+type OutboxRepository interface {
+	// Add записывает события, не связанные с другими изменениями (см. OutboxPublisher).
+	Add(ctx context.Context, events []domain.OutboxEvent) error
+	// ClaimPending забирает до limit неопубликованных событий с наступившим next_attempt_at
+	// в порядке записи и откладывает их до leaseUntil, чтобы их не взял другой сервер.
+	ClaimPending(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error)
+	// MarkPublished отмечает событие опубликованным. lease — NextAttemptAt из ClaimPending;
+	// если событие уже забрали заново, возвращает ErrOutboxLeaseLost.
+	MarkPublished(ctx context.Context, id int64, lease time.Time) error
+	// MarkFailed засчитывает неудачную попытку и откладывает событие до nextAttemptAt;
+	// lease — как в MarkPublished.
+	MarkFailed(ctx context.Context, id int64, lease, nextAttemptAt time.Time, errText string) error
+	// DeletePublished удаляет события, опубликованные раньше before.
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
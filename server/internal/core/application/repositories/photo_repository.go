@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// PhotoRepository defines the interface for wardrobe item photo metadata.
+type PhotoRepository interface {
+	// IsInWardrobe сообщает, есть ли вещь в гардеробе пользователя (фото можно прикреплять только к своим вещам).
+	IsInWardrobe(ctx context.Context, userID int, itemID int64) (bool, error)
+
+	GetPhoto(ctx context.Context, userID int, itemID int64) (*domain.ItemPhoto, error)
+	GetPhotos(ctx context.Context, userID int, itemIDs []int64) (map[int64]domain.ItemPhoto, error)
+
+	// SavePhoto сохраняет фото и возвращает предыдущее (если было), чтобы удалить его объекты из хранилища.
+	SavePhoto(ctx context.Context, photo *domain.ItemPhoto) (*domain.ItemPhoto, error)
+	DeletePhoto(ctx context.Context, userID int, itemID int64) (*domain.ItemPhoto, error)
+}
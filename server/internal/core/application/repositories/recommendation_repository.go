@@ -8,9 +8,16 @@ import (
 
 // RecommendationRepository defines operations for working with outfit recommendations.
 type RecommendationRepository interface {
-	// CreateRecommendation сохраняет рекомендацию и её вещи.
-	// Возвращает сгенерированный ID рекомендации.
-	CreateRecommendation(ctx context.Context, rec *domain.RecommendationResponse) (int, error)
+	// CreateRecommendation сохраняет рекомендацию и её вещи; origin — domain.RecommendationOrigin*.
+	// Возвращает сгенерированный ID рекомендации (он же записывается в rec.ID).
+	// events, если задан, вызывается внутри транзакции после вставки, и возвращённые
+	// события пишутся в outbox той же транзакцией: либо сохранено всё, либо ничего.
+	CreateRecommendation(
+		ctx context.Context,
+		rec *domain.RecommendationResponse,
+		origin string,
+		events func(rec *domain.RecommendationResponse) ([]domain.OutboxEvent, error),
+	) (int, error)
 
 	// GetUserRecommendations возвращает истории рекомендаций пользователя (последние N штук).
 	GetUserRecommendations(ctx context.Context, userID, limit int) ([]domain.RecommendationResponse, error)
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// SessionRepository хранит сессии и хэши их refresh-токенов.
+type SessionRepository interface {
+	// Create открывает сессию с первым токеном tokenHash.
+	Create(ctx context.Context, session domain.Session, tokenHash string) (*domain.Session, error)
+	// Rotate атомарно заменяет токен oldHash на newHash и продлевает сессию до expiresAt.
+	// Уже заменённый токен отзывает сессию и возвращает ErrRefreshTokenReused.
+	Rotate(ctx context.Context, oldHash, newHash string, client domain.SessionClient, now, expiresAt time.Time) (*domain.Session, error)
+	// RevokeByToken отзывает сессию, которой принадлежит токен; неизвестный токен — не ошибка.
+	RevokeByToken(ctx context.Context, tokenHash string) error
+	// List возвращает действующие сессии пользователя, последние использованные первыми.
+	List(ctx context.Context, userID int, now time.Time) ([]domain.Session, error)
+	// Revoke отзывает сессию пользователя; false, если действующей сессии с таким ID нет.
+	Revoke(ctx context.Context, userID int, sessionID int64) (bool, error)
+	// DeleteExpired удаляет истёкшие и отозванные сессии вместе с их токенами.
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
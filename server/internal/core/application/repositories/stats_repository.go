@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// StatsRepository defines persistence for user statistics (таблица user_stats).
+// Счётчики обновляются по событиям и могут быть пересчитаны из исходных таблиц.
+type StatsRepository interface {
+	// Get возвращает статистику пользователя; nil, если её ещё нет.
+	Get(ctx context.Context, userID int) (*domain.ExtendedUserStats, error)
+	// ApplyRecommendation добавляет сохранённую рекомендацию к счётчикам её владельца.
+	// Повторный вызов для той же рекомендации ничего не меняет.
+	ApplyRecommendation(ctx context.Context, recommendationID int) error
+	// RefreshCounts пересчитывает оценки, избранное и достижения и сдвигает last_active
+	// не раньше at. Вызов идемпотентен.
+	RefreshCounts(ctx context.Context, userID int, at time.Time) error
+	// Rebuild пересчитывает всю статистику пользователя из исходных таблиц.
+	Rebuild(ctx context.Context, userID int) error
+	// ListUserIDs возвращает id всех пользователей.
+	ListUserIDs(ctx context.Context) ([]int, error)
+}
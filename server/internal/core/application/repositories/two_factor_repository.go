@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// TwoFactorRepository хранит TOTP-секреты и хэши кодов восстановления.
+type TwoFactorRepository interface {
+	// Get возвращает настройку 2FA пользователя; nil, если её нет.
+	Get(ctx context.Context, userID int) (*domain.TwoFactor, error)
+	// SavePending сохраняет секрет неподтверждённого подключения; false, если 2FA уже включена.
+	SavePending(ctx context.Context, userID int, secret string) (bool, error)
+	// Enable включает 2FA с принятым шагом step и заменяет коды восстановления;
+	// false, если подключение не начато или уже подтверждено.
+	Enable(ctx context.Context, userID int, step int64, recoveryHashes []string, at time.Time) (bool, error)
+	// UseStep атомарно принимает код шага step, если он новее последнего принятого.
+	UseStep(ctx context.Context, userID int, step int64) (bool, error)
+	// RecoveryCodeHashes возвращает bcrypt-хэши неиспользованных кодов восстановления.
+	RecoveryCodeHashes(ctx context.Context, userID int) ([]string, error)
+	// UseRecoveryCode гасит неиспользованный код восстановления с хэшем hash; false, если
+	// такого нет или его уже погасили.
+	UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) (bool, error)
+	// Delete выключает 2FA и удаляет коды восстановления.
+	Delete(ctx context.Context, userID int) error
+}
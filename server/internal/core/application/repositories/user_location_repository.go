@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// UserLocationRepository defines the interface for users' saved locations.
+// Get, GetDefault и Delete возвращают nil без ошибки, если места нет.
+type UserLocationRepository interface {
+	List(ctx context.Context, userID int) ([]domain.UserLocation, error)
+	Count(ctx context.Context, userID int) (int, error)
+	Get(ctx context.Context, userID, id int) (*domain.UserLocation, error)
+	GetDefault(ctx context.Context, userID int) (*domain.UserLocation, error)
+
+	// Create и Update при IsDefault снимают отметку с прежнего места по умолчанию в той же транзакции.
+	// Занятый «дом» или «работа» — ErrLocationKindTaken, гонка за место по умолчанию — ErrDefaultLocationTaken.
+	// Create проверяет limit под блокировкой пользователя (ErrTooManyLocations); первое место
+	// становится местом по умолчанию.
+	Create(ctx context.Context, loc *domain.UserLocation, limit int) error
+	Update(ctx context.Context, loc *domain.UserLocation) error
+
+	// Delete удаляет место; если оно было по умолчанию, им становится самое старое из оставшихся.
+	Delete(ctx context.Context, userID, id int) (*domain.UserLocation, error)
+	SetDefault(ctx context.Context, userID, id int) (*domain.UserLocation, error)
+}
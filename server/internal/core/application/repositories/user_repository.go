@@ -18,12 +18,15 @@ type UserRepository interface {
 	UpdateUserProfile(ctx context.Context, profile *domain.UserProfile) error
 
 	GetUserAchievements(ctx context.Context, userID int) ([]domain.Achievement, error)
-	UnlockAchievement(ctx context.Context, userID int, achievementCode string) error
+	// UnlockAchievement возвращает false, если достижение уже было открыто.
+	UnlockAchievement(ctx context.Context, userID int, achievementCode string) (bool, error)
 
-	RateRecommendation(ctx context.Context, userID, recommendationID, rating int, feedback string) error
+	// RateRecommendation, AddFavorite и RemoveFavorite пишут events в outbox той же транзакцией,
+	// что и само изменение. AddFavorite не пишет их, если рекомендация уже в избранном.
+	RateRecommendation(ctx context.Context, userID, recommendationID, rating int, feedback string, events []domain.OutboxEvent) error
 
-	AddFavorite(ctx context.Context, userID, recommendationID int) error
-	RemoveFavorite(ctx context.Context, userID, favoriteID int) error
+	AddFavorite(ctx context.Context, userID, recommendationID int, events []domain.OutboxEvent) error
+	RemoveFavorite(ctx context.Context, userID, favoriteID int, events []domain.OutboxEvent) error
 	GetUserFavorites(ctx context.Context, userID int) ([]domain.FavoriteOutfit, error)
 
 	GetUserRatings(ctx context.Context, userID int) ([]domain.UserRating, error)
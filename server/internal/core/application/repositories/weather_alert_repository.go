@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// WeatherAlertRepository defines the interface for severe-weather notification data.
+type WeatherAlertRepository interface {
+	// ListTargets возвращает пользователей с местом по умолчанию или городом в профиле,
+	// постранично по возрастанию id: следующая страница начинается после afterUserID.
+	ListTargets(ctx context.Context, afterUserID, limit int) ([]domain.NotificationTarget, error)
+
+	// MarkNotified отмечает, что о предупреждении на день уже сообщили; false — отметка уже была.
+	MarkNotified(ctx context.Context, userID int, kind alerts.Kind, day time.Time) (bool, error)
+}
@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// WeatherRepository defines the interface for weather observation history (weather_data).
+type WeatherRepository interface {
+	// SaveObservation записывает наблюдение и возвращает id строки weather_data.
+	SaveObservation(ctx context.Context, weather *domain.ExtendedWeatherData) (int, error)
+}
@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+// WebhookRepository defines the interface for webhook subscriptions and their delivery log.
+type WebhookRepository interface {
+	List(ctx context.Context, userID int) ([]domain.Webhook, error)
+	Count(ctx context.Context, userID int) (int, error)
+	// Get возвращает вебхук пользователя или nil, если его нет.
+	Get(ctx context.Context, userID, id int) (*domain.Webhook, error)
+	Create(ctx context.Context, hook *domain.Webhook) error
+	Update(ctx context.Context, hook *domain.Webhook) error
+	// Delete удаляет вебхук вместе с журналом доставок; false — вебхука не было.
+	Delete(ctx context.Context, userID, id int) (bool, error)
+
+	// ListSubscribed возвращает активные вебхуки пользователя, подписанные на событие.
+	ListSubscribed(ctx context.Context, userID int, event string) ([]domain.Webhook, error)
+	// Enqueue ставит доставки в очередь; повтор того же события тому же вебхуку игнорируется.
+	Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error
+	// ClaimDue забирает до limit наступивших доставок активных вебхуков и откладывает их
+	// следующую попытку до leaseUntil, чтобы их не взял другой экземпляр сервера.
+	// Если отправитель упадёт, доставки вернутся в очередь после leaseUntil.
+	ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error)
+	// RecordAttempt сохраняет итог попытки: статус, число попыток, следующую попытку и ответ.
+	// lease — аренда из ClaimDue; если доставку уже забрали заново, возвращает ErrWebhookLeaseLost.
+	RecordAttempt(ctx context.Context, d *domain.WebhookDelivery, lease time.Time) error
+
+	// ListDeliveries возвращает журнал доставок вебхука пользователя, новые первыми;
+	// status — фильтр по статусу, пустая строка — все.
+	ListDeliveries(ctx context.Context, userID, webhookID int, status string, limit, offset int) ([]domain.WebhookDelivery, error)
+	// Redeliver возвращает доставку в очередь с немедленной попыткой; nil — доставки нет.
+	Redeliver(ctx context.Context, userID, webhookID int, id int64) (*domain.WebhookDelivery, error)
+}
@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/jobs"
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// achievementDefinitionsTTL — как быстро подхватываются новые и изменённые достижения.
+const achievementDefinitionsTTL = time.Minute
+
+// achievementsEvaluateJob повторяет проверку достижений, если она не удалась в запросе.
+var achievementsEvaluateJob = jobs.Kind[domain.UserEvent]{
+	Name:        "achievements.evaluate",
+	MaxAttempts: 5,
+	Timeout:     30 * time.Second,
+}
+
+// AchievementService открывает достижения по правилам из таблицы achievements.
+type AchievementService struct {
+	achievementRepo repositories.AchievementRepository
+	events          EventPublisher
+	jobs            *jobs.Queue
+	logger          *zap.Logger
+	now             func() time.Time
+
+	mu          sync.Mutex
+	definitions []domain.AchievementDefinition
+	loadedAt    time.Time
+}
+
+// NewAchievementService creates a new achievement service.
+func NewAchievementService(achievementRepo repositories.AchievementRepository, logger *zap.Logger) *AchievementService {
+	return &AchievementService{
+		achievementRepo: achievementRepo,
+		logger:          logger,
+		now:             time.Now,
+	}
+}
+
+// WithEvents подключает публикацию события achievement.unlocked.
+func (s *AchievementService) WithEvents(events EventPublisher) *AchievementService {
+	s.events = events
+	return s
+}
+
+// WithJobs включает повтор неудавшихся проверок через очередь задач.
+func (s *AchievementService) WithJobs(queue *jobs.Queue) *AchievementService {
+	s.jobs = queue
+	jobs.Handle(queue, achievementsEvaluateJob, func(ctx context.Context, event domain.UserEvent) error {
+		_, err := s.Evaluate(ctx, event)
+		return err
+	})
+	return s
+}
+
+// OnRecommendation проверяет достижения после выдачи сохранённой рекомендации.
+func (s *AchievementService) OnRecommendation(ctx context.Context, rec *domain.RecommendationResponse) []domain.Achievement {
+	return s.Track(ctx, domain.UserEvent{
+		Key:    fmt.Sprintf("recommendation:%d", rec.ID),
+		UserID: int(rec.UserID),
+		Type:   domain.EventRecommendationCreated,
+		Data: map[string]interface{}{
+			"temperature": rec.Temperature,
+			"feels_like":  rec.FeelsLike,
+			"weather":     rec.Weather,
+			"location":    rec.Location,
+			"will_rain":   rec.WillRain,
+			"will_snow":   rec.WillSnow,
+			"wind_speed":  rec.WindSpeed,
+			"item_count":  len(rec.Items),
+			"algorithm":   rec.Algorithm,
+		},
+	})
+}
+
+// OnRating проверяет достижения после оценки рекомендации. Повторная оценка той же
+// рекомендации не считается новым событием.
+func (s *AchievementService) OnRating(ctx context.Context, userID, recommendationID, rating int) []domain.Achievement {
+	return s.Track(ctx, domain.UserEvent{
+		Key:    fmt.Sprintf("rating:%d:%d", userID, recommendationID),
+		UserID: userID,
+		Type:   domain.EventRatingSubmitted,
+		Data: map[string]interface{}{
+			"recommendation_id": recommendationID,
+			"rating":            rating,
+		},
+	})
+}
+
+// OnWardrobeItemAdded проверяет достижения после добавления вещи в гардероб.
+func (s *AchievementService) OnWardrobeItemAdded(ctx context.Context, userID, itemID int) []domain.Achievement {
+	return s.Track(ctx, domain.UserEvent{
+		Key:    fmt.Sprintf("wardrobe:%d:%d", userID, itemID),
+		UserID: userID,
+		Type:   domain.EventWardrobeItemAdded,
+		Data:   map[string]interface{}{"item_id": itemID},
+	})
+}
+
+// Track — Evaluate для обработчиков запросов: ошибка не мешает ответу, а событие
+// проверяется повторно в очереди задач. Возвращает впервые открытые достижения
+// (пустой список, если таких нет).
+func (s *AchievementService) Track(ctx context.Context, event domain.UserEvent) []domain.Achievement {
+	unlocked, err := s.Evaluate(ctx, event)
+	if err == nil {
+		if unlocked == nil {
+			unlocked = []domain.Achievement{}
+		}
+		return unlocked
+	}
+
+	log := s.logger.With(zap.Int("user_id", event.UserID), zap.String("event_key", event.Key))
+	log.Warn("Failed to evaluate achievements", zap.Error(err))
+	if s.jobs != nil {
+		// Отдельный контекст: запрос мог упасть как раз по таймауту
+		retryCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		if err := achievementsEvaluateJob.Enqueue(retryCtx, s.jobs, event, jobs.UniqueKey(event.Key)); err != nil {
+			log.Error("Failed to enqueue achievements evaluation", zap.Error(err))
+		}
+	}
+	return []domain.Achievement{}
+}
+
+// Evaluate применяет событие ко всем правилам и возвращает впервые открытые достижения.
+// Повторная обработка события с тем же Key ничего не меняет.
+func (s *AchievementService) Evaluate(ctx context.Context, event domain.UserEvent) ([]domain.Achievement, error) {
+	definitions, err := s.loadDefinitions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		steps []domain.AchievementStep
+		stats map[string]float64
+	)
+	for _, d := range definitions {
+		rule := d.Rule
+		if rule.Event != "" && rule.Event != event.Type {
+			continue
+		}
+		if !matchesAchievementConditions(rule.Where, event.Data) {
+			continue
+		}
+
+		if rule.Stat == "" {
+			steps = append(steps, domain.AchievementStep{
+				AchievementID: d.ID,
+				Progress:      1,
+				Increment:     true,
+				Target:        max(rule.Count, 1),
+			})
+			continue
+		}
+
+		// Показатели считаются один раз на событие и только если нужны
+		if stats == nil {
+			if stats, err = s.achievementRepo.UserStats(ctx, event.UserID); err != nil {
+				return nil, errors.Wrap(err, "failed to load user stats")
+			}
+		}
+		steps = append(steps, domain.AchievementStep{
+			AchievementID: d.ID,
+			Progress:      int(stats[rule.Stat]),
+			Target:        max(int(math.Ceil(rule.Min)), 1),
+		})
+	}
+	if len(steps) == 0 {
+		return nil, nil
+	}
+
+	unlocked, err := s.achievementRepo.ApplyEvent(ctx, event.UserID, event.Key, steps)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply achievement event")
+	}
+
+	if s.events != nil {
+		for _, a := range unlocked {
+			s.events.Publish(ctx, event.UserID, domain.EventAchievementUnlocked, map[string]interface{}{
+				"achievement_code": a.Code,
+			})
+		}
+	}
+	return unlocked, nil
+}
+
+// loadDefinitions возвращает правила, перечитывая их не чаще раза в achievementDefinitionsTTL.
+func (s *AchievementService) loadDefinitions(ctx context.Context) ([]domain.AchievementDefinition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.definitions != nil && s.now().Sub(s.loadedAt) < achievementDefinitionsTTL {
+		return s.definitions, nil
+	}
+
+	definitions, err := s.achievementRepo.ListDefinitions(ctx)
+	if err != nil {
+		if s.definitions != nil {
+			// Устаревшие правила лучше, чем никаких
+			s.logger.Warn("Failed to reload achievements, using cached", zap.Error(err))
+			return s.definitions, nil
+		}
+		return nil, errors.Wrap(err, "failed to load achievements")
+	}
+
+	valid := make([]domain.AchievementDefinition, 0, len(definitions))
+	for _, d := range definitions {
+		if err := validateAchievementRule(d.Rule); err != nil {
+			s.logger.Warn("Skipping achievement with invalid rule", zap.String("code", d.Code), zap.Error(err))
+			continue
+		}
+		valid = append(valid, d)
+	}
+	s.definitions = valid
+	s.loadedAt = s.now()
+	return valid, nil
+}
+
+// validateAchievementRule отсекает правила, которые никогда не сработают.
+func validateAchievementRule(rule domain.AchievementRule) error {
+	if rule.Event == "" && rule.Stat == "" {
+		return errors.New("rule needs an event or a stat")
+	}
+	if rule.Event != "" && !isWebhookEvent(rule.Event) {
+		return errors.Errorf("unknown event %q", rule.Event)
+	}
+	if rule.Stat != "" && !isAchievementStat(rule.Stat) {
+		return errors.Errorf("unknown stat %q", rule.Stat)
+	}
+	if rule.Count < 0 || rule.Min < 0 {
+		return errors.New("count and min must not be negative")
+	}
+	return nil
+}
+
+// matchesAchievementConditions проверяет все условия; отсутствующее поле не подходит.
+func matchesAchievementConditions(where map[string]domain.AchievementCondition, data map[string]interface{}) bool {
+	for field, cond := range where {
+		value, ok := data[field]
+		if !ok || !matchesAchievementCondition(cond, value) {
+			return false
+		}
+	}
+	return true
+}
+
+func matchesAchievementCondition(cond domain.AchievementCondition, value interface{}) bool {
+	if cond.Eq != nil && !achievementValuesEqual(cond.Eq, value) {
+		return false
+	}
+	if len(cond.In) > 0 {
+		found := false
+		for _, v := range cond.In {
+			if achievementValuesEqual(v, value) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if cond.Lt == nil && cond.Lte == nil && cond.Gt == nil && cond.Gte == nil {
+		return true
+	}
+	n, ok := achievementNumber(value)
+	if !ok {
+		return false
+	}
+	return (cond.Lt == nil || n < *cond.Lt) &&
+		(cond.Lte == nil || n <= *cond.Lte) &&
+		(cond.Gt == nil || n > *cond.Gt) &&
+		(cond.Gte == nil || n >= *cond.Gte)
+}
+
+// achievementValuesEqual сравнивает числа по значению (в правилах они float64, в событиях — любые).
+func achievementValuesEqual(want, got interface{}) bool {
+	if a, ok := achievementNumber(want); ok {
+		b, ok := achievementNumber(got)
+		return ok && a == b
+	}
+	return want == got
+}
+
+func achievementNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func isAchievementStat(stat string) bool {
+	for _, s := range domain.AchievementStats {
+		if s == stat {
+			return true
+		}
+	}
+	return false
+}
@@ -4,15 +4,23 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"log"
 	"time"
 
+	"outfitstyle/server/internal/core/application/repositories"
 	"outfitstyle/server/internal/core/domain"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	passwordResetTTL    = 24 * time.Hour
+	passwordResetWindow = 5 * time.Minute // не больше одного письма на адрес
+	twoFactorLoginTTL   = 5 * time.Minute // сколько вход по паролю ждёт кода 2FA
+)
+
 // UserRepository defines the interface for user data operations
 type UserRepository interface {
 	GetUser(ctx context.Context, id int) (*domain.User, error)
@@ -21,40 +29,70 @@ type UserRepository interface {
 	UpdateUser(ctx context.Context, user *domain.User) error
 }
 
-// AuthService handles authentication-related operations
+// AuthService handles authentication-related operations.
+// Коды и токены сброса хранятся в AuthStateRepository, сессии — в SessionService,
+// поэтому они общие для всех реплик.
 type AuthService struct {
-	userRepo            UserRepository
-	emailService        EmailService
-	tokenService        *TokenService
-	verificationDB      map[string]domain.VerificationCode
-	blacklistDB         map[string]bool
-	passwordResetTokens map[string]PasswordResetToken
-	passwordResetLimiter map[string]time.Time // email -> время последнего запроса
+	userRepo       UserRepository
+	stateRepo      repositories.AuthStateRepository
+	emailService   EmailService
+	tokenService   *TokenService
+	sessionService *SessionService
+	twoFactor      *TwoFactorService
+	throttle       *loginThrottle
+	lockDuration   time.Duration
+}
+
+// LoginResult — следующий шаг входа после проверки пароля.
+type LoginResult struct {
+	// Code — код, отправленный на email; пусто, если нужен код 2FA.
+	Code string
+	// TwoFactorToken — токен входа, который вместе с кодом 2FA передаётся в VerifyTwoFactor.
+	TwoFactorToken string
+}
+
+// AuthTokens — access-токен и refresh-токен сессии, выдаваемые при входе и refresh.
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	SessionID    int64
 }
 
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
 	TokenExpiryHours       int
 	VerificationCodeExpiry time.Duration
-	MaxLoginAttempts       int
-	BlockDuration          time.Duration
+	// MaxLoginAttempts неудачных входов в аккаунт (MaxLoginAttemptsPerIP — с одного IP)
+	// за BlockDuration блокируют вход на BlockDuration.
+	MaxLoginAttempts      int
+	MaxLoginAttemptsPerIP int
+	BlockDuration         time.Duration
 }
 
 // NewAuthService creates a new authentication service
 func NewAuthService(
 	userRepo UserRepository,
+	stateRepo repositories.AuthStateRepository,
 	emailService EmailService,
 	tokenService *TokenService,
+	sessionService *SessionService,
+	twoFactor *TwoFactorService,
 	config AuthConfig,
 ) *AuthService {
 	return &AuthService{
-		userRepo:            userRepo,
-		emailService:        emailService,
-		tokenService:        tokenService,
-		verificationDB:      make(map[string]domain.VerificationCode),
-		blacklistDB:         make(map[string]bool),
-		passwordResetTokens: make(map[string]PasswordResetToken),
-		passwordResetLimiter: make(map[string]time.Time),
+		userRepo:       userRepo,
+		stateRepo:      stateRepo,
+		emailService:   emailService,
+		tokenService:   tokenService,
+		sessionService: sessionService,
+		twoFactor:      twoFactor,
+		throttle: newLoginThrottle(stateRepo, LoginThrottleConfig{
+			MaxAttempts:      config.MaxLoginAttempts,
+			MaxAttemptsPerIP: config.MaxLoginAttemptsPerIP,
+			Window:           config.BlockDuration,
+			BlockDuration:    config.BlockDuration,
+		}),
+		lockDuration: config.BlockDuration,
 	}
 }
 
@@ -88,13 +126,9 @@ func (s *AuthService) RegisterUser(ctx context.Context, userInput domain.UserReg
 	}
 
 	// Save verification code
-	verification := domain.VerificationCode{
-		Code:      code,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().Add(10 * time.Minute),
-		Type:      "registration",
+	if err := s.saveCode(ctx, code, int(user.ID), domain.AuthTokenRegistration, 10*time.Minute); err != nil {
+		return nil, err
 	}
-	s.verificationDB[code] = verification
 
 	// Send verification email
 	if err := s.emailService.SendVerificationEmail(user.Email, code); err != nil {
@@ -104,108 +138,243 @@ func (s *AuthService) RegisterUser(ctx context.Context, userInput domain.UserReg
 	return user, nil
 }
 
+// dummyPasswordHash — bcrypt-хэш случайной строки с bcrypt.DefaultCost: с ним сравнивается
+// пароль, когда email не найден, чтобы по времени ответа нельзя было понять, есть ли аккаунт.
+const dummyPasswordHash = "$2a$10$Yr80PPwWVsVj.Rwl034hfe3Qz353Jhbbm16Jkhq8lBIoPhaGk3B3O"
+
 // LoginUser initiates login process
-func (s *AuthService) LoginUser(ctx context.Context, email, password string) (string, error) {
+// Пользователю с 2FA вместо письма с кодом возвращается токен входа для VerifyTwoFactor.
+// Пока вход заблокирован после неудачных попыток, возвращается *LoginLockedError.
+func (s *AuthService) LoginUser(ctx context.Context, email, password string, client domain.SessionClient) (*LoginResult, error) {
+	if err := s.throttle.Check(ctx, email, client.IP); err != nil {
+		return nil, err
+	}
+
 	// Ищем пользователя по email
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
-	if err != nil || user == nil {
-		return "", fmt.Errorf("invalid credentials")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 
-	// Проверяем пароль
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
-		return "", fmt.Errorf("invalid credentials")
+	// Проверяем пароль; для неизвестного email — с фиктивным хэшем, чтобы ответ не был быстрее
+	passwordHash := dummyPasswordHash
+	if user != nil {
+		passwordHash = user.Password
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) != nil || user == nil {
+		return nil, s.loginFailed(ctx, email, client.IP, user)
+	}
+
+	twoFactorToken, err := s.TwoFactorChallenge(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactorToken != "" {
+		// Счётчик аккаунта сбросит VerifyTwoFactor: неверные коды 2FA тоже считаются неудачными входами
+		return &LoginResult{TwoFactorToken: twoFactorToken}, nil
+	}
+
+	if err := s.throttle.Succeed(ctx, email); err != nil {
+		log.Printf("Warning: %v", err)
 	}
 
 	// Generate verification code
 	code, err := s.generateVerificationCode(6)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate verification code: %w", err)
+		return nil, fmt.Errorf("failed to generate verification code: %w", err)
 	}
 
-	verification := domain.VerificationCode{
-		Code:      code,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().Add(10 * time.Minute),
-		Type:      "login",
+	if err := s.saveCode(ctx, code, int(user.ID), domain.AuthTokenLogin, 10*time.Minute); err != nil {
+		return nil, err
 	}
-	s.verificationDB[code] = verification
 
 	if err := s.emailService.SendVerificationEmail(user.Email, code); err != nil {
 		log.Printf("Warning: failed to send verification email: %v", err)
 	}
 
-	return code, nil
+	return &LoginResult{Code: code}, nil
+}
+
+// loginFailed засчитывает неудачный вход и, если он заблокировал аккаунт, предупреждает владельца.
+func (s *AuthService) loginFailed(ctx context.Context, email, ip string, user *domain.User) error {
+	accountLocked, err := s.throttle.Fail(ctx, email, ip)
+	if accountLocked && user != nil {
+		log.Printf("Login locked for user %d after failed attempts", user.ID)
+		if err := s.emailService.SendAccountLockedEmail(user.Email, s.lockDuration); err != nil {
+			log.Printf("Warning: failed to send account locked email: %v", err)
+		}
+	}
+
+	var locked *LoginLockedError
+	if errors.As(err, &locked) {
+		return err
+	}
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	return fmt.Errorf("invalid credentials")
+}
+
+// TwoFactorChallenge выдаёт токен входа, ждущего кода 2FA; пусто, если 2FA у пользователя выключена.
+func (s *AuthService) TwoFactorChallenge(ctx context.Context, userID domain.ID) (string, error) {
+	enabled, err := s.twoFactor.IsEnabled(ctx, int(userID))
+	if err != nil {
+		return "", fmt.Errorf("failed to check two-factor: %w", err)
+	}
+	if !enabled {
+		return "", nil
+	}
+
+	token, err := randomHex(32)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate two-factor token: %w", err)
+	}
+	if err := s.saveCode(ctx, token, int(userID), domain.AuthTokenTwoFactor, twoFactorLoginTTL); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// VerifyTwoFactor завершает вход пользователя с 2FA: токен входа из LoginUser и код из
+// приложения или код восстановления. Токен одноразовый: после неверного кода вход начинается заново.
+func (s *AuthService) VerifyTwoFactor(ctx context.Context, twoFactorToken, code string, client domain.SessionClient) (*domain.User, *AuthTokens, error) {
+	challenge, err := s.stateRepo.ConsumeToken(ctx, hashAuthToken(twoFactorToken), []string{domain.AuthTokenTwoFactor})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check two-factor token: %w", err)
+	}
+	if challenge == nil || time.Now().After(challenge.ExpiresAt) {
+		return nil, nil, fmt.Errorf("invalid or expired two-factor token")
+	}
+
+	user, err := s.userRepo.GetUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	if user == nil {
+		return nil, nil, fmt.Errorf("user not found")
+	}
+
+	if err := s.throttle.Check(ctx, user.Email, client.IP); err != nil {
+		return nil, nil, err
+	}
+	if err := s.twoFactor.Verify(ctx, challenge.UserID, code); err != nil {
+		if errors.Is(err, ErrInvalidTwoFactorCode) {
+			return nil, nil, s.loginFailed(ctx, user.Email, client.IP, user)
+		}
+		return nil, nil, fmt.Errorf("two-factor verification failed: %w", err)
+	}
+	if err := s.throttle.Succeed(ctx, user.Email); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+
+	tokens, err := s.startSession(ctx, user, client)
+	if err != nil {
+		return nil, nil, err
+	}
+	return user, tokens, nil
 }
 
-// VerifyCode verifies a verification code
-func (s *AuthService) VerifyCode(ctx context.Context, code string) (*domain.User, string, error) {
-	verification, ok := s.verificationDB[code]
-	if !ok {
-		return nil, "", fmt.Errorf("invalid verification code")
+// VerifyCode verifies a verification code and starts a session for the client.
+func (s *AuthService) VerifyCode(ctx context.Context, code string, client domain.SessionClient) (*domain.User, *AuthTokens, error) {
+	// Код забирается атомарно: параллельный запрос с тем же кодом его уже не найдёт
+	verification, err := s.stateRepo.ConsumeToken(ctx, hashAuthToken(code),
+		[]string{domain.AuthTokenRegistration, domain.AuthTokenLogin})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check verification code: %w", err)
+	}
+	if verification == nil {
+		return nil, nil, fmt.Errorf("invalid verification code")
 	}
 
 	if time.Now().After(verification.ExpiresAt) {
-		delete(s.verificationDB, code)
-		return nil, "", fmt.Errorf("verification code expired")
+		return nil, nil, fmt.Errorf("verification code expired")
 	}
 
-	user, err := s.userRepo.GetUser(ctx, int(verification.UserID))
+	user, err := s.userRepo.GetUser(ctx, verification.UserID)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to get user: %w", err)
+		return nil, nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return nil, "", fmt.Errorf("user not found")
+		return nil, nil, fmt.Errorf("user not found")
+	}
+
+	if verification.Kind == domain.AuthTokenLogin {
+		// Код из письма, выданный до включения 2FA, не заменяет второй фактор
+		enabled, err := s.twoFactor.IsEnabled(ctx, verification.UserID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check two-factor: %w", err)
+		}
+		if enabled {
+			return nil, nil, fmt.Errorf("two-factor authentication required")
+		}
 	}
 
-	if verification.Type == "registration" {
+	if verification.Kind == domain.AuthTokenRegistration {
 		user.IsVerified = true
 		user.UpdatedAt = time.Now()
 		if err := s.userRepo.UpdateUser(ctx, user); err != nil {
-			return nil, "", fmt.Errorf("failed to update user: %w", err)
+			return nil, nil, fmt.Errorf("failed to update user: %w", err)
 		}
 	}
 
-	accessToken, err := s.tokenService.GenerateAccessToken(user)
+	tokens, err := s.startSession(ctx, user, client)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to generate access token: %w", err)
+		return nil, nil, err
 	}
 
-	delete(s.verificationDB, code)
-
-	return user, accessToken, nil
+	return user, tokens, nil
 }
 
-// RefreshToken refreshes an access token
-func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string) (string, error) {
-	userID, err := s.tokenService.ValidateRefreshToken(refreshToken)
+// RefreshToken обменивает refresh-токен на новую пару токенов. Старый refresh-токен
+// больше не действует; его повторное предъявление отзывает всю сессию.
+func (s *AuthService) RefreshToken(ctx context.Context, refreshToken string, client domain.SessionClient) (*AuthTokens, error) {
+	session, newRefreshToken, err := s.sessionService.Rotate(ctx, refreshToken, client)
 	if err != nil {
-		return "", fmt.Errorf("invalid refresh token: %w", err)
+		return nil, fmt.Errorf("failed to rotate refresh token: %w", err)
 	}
 
-	if s.blacklistDB[refreshToken] {
-		return "", fmt.Errorf("token has been revoked")
-	}
-
-	user, err := s.userRepo.GetUser(ctx, int(userID))
+	user, err := s.userRepo.GetUser(ctx, session.UserID)
 	if err != nil {
-		return "", fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return "", fmt.Errorf("user not found")
+		return nil, fmt.Errorf("user not found")
 	}
 
 	accessToken, err := s.tokenService.GenerateAccessToken(user)
 	if err != nil {
-		return "", fmt.Errorf("failed to generate accesstoken: %w", err)
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
-	return accessToken, nil
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: newRefreshToken,
+		SessionID:    session.ID,
+	}, nil
 }
 
-// RevokeToken revokes a refresh token
-func (s *AuthService) RevokeToken(refreshToken string) {
-	s.blacklistDB[refreshToken] = true
+// RevokeToken закрывает сессию refresh-токена (выход).
+func (s *AuthService) RevokeToken(ctx context.Context, refreshToken string) error {
+	return s.sessionService.RevokeByToken(ctx, refreshToken)
+}
+
+// startSession выдаёт access-токен и открывает сессию с первым refresh-токеном.
+func (s *AuthService) startSession(ctx context.Context, user *domain.User, client domain.SessionClient) (*AuthTokens, error) {
+	accessToken, err := s.tokenService.GenerateAccessToken(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	session, refreshToken, err := s.sessionService.Start(ctx, int(user.ID), client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		SessionID:    session.ID,
+	}, nil
 }
 
 // generateVerificationCode generates a random verification code
@@ -217,18 +386,8 @@ func (s *AuthService) generateVerificationCode(length int) (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes)[:length], nil
 }
 
-// PasswordResetToken holds information about a password reset token
-type PasswordResetToken struct {
-	Token     string
-	UserID    domain.ID
-	ExpiresAt time.Time
-}
-
 // ForgotPassword initiates password reset process
 func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
-	// Очищаем устаревшие данные
-	s.cleanupExpiredData()
-
 	user, err := s.userRepo.GetUserByEmail(ctx, email)
 	if err != nil || user == nil {
 		// не раскрываем, есть ли пользователь
@@ -236,13 +395,14 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
 	}
 
 	// Проверяем ограничение частоты запросов - один раз в 5 минут
-	if lastRequest, exists := s.passwordResetLimiter[email]; exists {
-		if time.Since(lastRequest) < 5*time.Minute {
-			// Не раскрываем причину, просто выходим
-			return nil
-		}
+	allowed, err := s.stateRepo.AllowAction(ctx, "password_reset:"+email, passwordResetWindow, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to check password reset limit: %w", err)
+	}
+	if !allowed {
+		// Не раскрываем причину, просто выходим
+		return nil
 	}
-	s.passwordResetLimiter[email] = time.Now()
 
 	resetToken, err := s.generatePasswordResetToken(32) // 32-byte token
 	if err != nil {
@@ -250,14 +410,10 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
 	}
 
 	// Сохраняем токен с временем истечения
-	reset := PasswordResetToken{
-		Token:     resetToken,
-		UserID:    user.ID,
-		ExpiresAt: time.Now().Add(24 * time.Hour), // токен действителен 24 часа
+	if err := s.saveCode(ctx, resetToken, int(user.ID), domain.AuthTokenPasswordReset, passwordResetTTL); err != nil {
+		return err
 	}
 
-	s.passwordResetTokens[resetToken] = reset
-
 	if err := s.emailService.SendPasswordResetEmail(user.Email, resetToken); err != nil {
 		log.Printf("Warning: failed to send password reset email: %v", err)
 	}
@@ -267,16 +423,16 @@ func (s *AuthService) ForgotPassword(ctx context.Context, email string) error {
 
 // ResetPassword resets a user's password
 func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword string) error {
-	// Очищаем устаревшие данные
-	s.cleanupExpiredData()
-
-	// Проверяем токен
-	reset, exists := s.passwordResetTokens[token]
-	if !exists {
+	// Проверяем и сразу гасим токен: второй сброс по нему невозможен
+	reset, err := s.stateRepo.ConsumeToken(ctx, hashAuthToken(token), []string{domain.AuthTokenPasswordReset})
+	if err != nil {
+		return fmt.Errorf("failed to check reset token: %w", err)
+	}
+	if reset == nil || time.Now().After(reset.ExpiresAt) {
 		return fmt.Errorf("invalid or expired reset token")
 	}
 
-	user, err := s.userRepo.GetUser(ctx, int(reset.UserID))
+	user, err := s.userRepo.GetUser(ctx, reset.UserID)
 	if err != nil {
 		return fmt.Errorf("failed to get user: %w", err)
 	}
@@ -296,9 +452,6 @@ func (s *AuthService) ResetPassword(ctx context.Context, token, newPassword stri
 		return fmt.Errorf("failed to update user password: %w", err)
 	}
 
-	// Удаляем использованный токен
-	delete(s.passwordResetTokens, token)
-
 	return nil
 }
 
@@ -311,30 +464,51 @@ func (s *AuthService) generatePasswordResetToken(length int) (string, error) {
 	return base64.URLEncoding.EncodeToString(bytes), nil
 }
 
-// cleanupExpiredData удаляет устаревшие данные из хранилищ
-func (s *AuthService) cleanupExpiredData() {
-	now := time.Now()
+// saveCode сохраняет хэш одноразового кода или токена.
+func (s *AuthService) saveCode(ctx context.Context, code string, userID int, kind string, ttl time.Duration) error {
+	err := s.stateRepo.SaveToken(ctx, domain.AuthToken{
+		Hash:      hashAuthToken(code),
+		Kind:      kind,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save %s code: %w", kind, err)
+	}
+	return nil
+}
 
-	// Очищаем устаревшие токены сброса пароля
-	for token, reset := range s.passwordResetTokens {
-		if now.After(reset.ExpiresAt) {
-			delete(s.passwordResetTokens, token)
+// RunCleanup удаляет истёкшие коды, токены, ограничения и сессии сразу и затем каждые interval,
+// пока ctx не отменён. Каждая реплика может запускать свою очистку.
+func (s *AuthService) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		deleted, err := s.stateRepo.DeleteExpired(ctx, time.Now())
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Warning: failed to clean up auth state: %v", err)
+		} else if deleted > 0 {
+			log.Printf("Expired auth state cleaned up: %d rows", deleted)
 		}
-	}
 
-	// Очищаем устаревшие ограничения частоты (старше 1 часа)
-	for email, lastRequest := range s.passwordResetLimiter {
-		if now.Sub(lastRequest) > time.Hour {
-			delete(s.passwordResetLimiter, email)
+		sessions, err := s.sessionService.DeleteExpired(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("Warning: failed to clean up sessions: %v", err)
+		} else if sessions > 0 {
+			log.Printf("Expired and revoked sessions cleaned up: %d", sessions)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
 		}
 	}
 }
 
 // ValidateToken validates an access token and returns the associated user.
 func (s *AuthService) ValidateToken(tokenString string) (*domain.User, error) {
-	// Очищаем устаревшие данные периодически
-	s.cleanupExpiredData()
-
 	userID, err := s.tokenService.ValidateAccessToken(tokenString)
 	if err != nil {
 		return nil, fmt.Errorf("invalid token: %w", err)
@@ -379,26 +553,15 @@ func (s *AuthService) RegisterOAuthUser(ctx context.Context, input domain.UserRe
 	return user, nil
 }
 
-// GenerateTokens генерирует access + (пока пустой) refresh токен
-func (s *AuthService) GenerateTokens(userID domain.ID) (string, string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
+// GenerateTokens выдаёт пару токенов пользователю, вошедшему через OAuth.
+func (s *AuthService) GenerateTokens(ctx context.Context, userID domain.ID, client domain.SessionClient) (*AuthTokens, error) {
 	user, err := s.userRepo.GetUser(ctx, int(userID))
 	if err != nil {
-		return "", "", fmt.Errorf("failed to get user: %w", err)
+		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
 	if user == nil {
-		return "", "", fmt.Errorf("user not found")
-	}
-
-	accessToken, err := s.tokenService.GenerateAccessToken(user)
-	if err != nil {
-		return "", "", fmt.Errorf("failed to generate access token: %w", err)
+		return nil, fmt.Errorf("user not found")
 	}
 
-	// Пока refresh-токен не используем
-	refreshToken := ""
-
-	return accessToken, refreshToken, nil
+	return s.startSession(ctx, user, client)
 }
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// challengeValueMaxLen — длина user_challenge_values.value.
+const challengeValueMaxLen = 100
+
+// ChallengeService ведёт серии ежедневных заходов и ежемесячные испытания. Как EventSink
+// получает события из outbox; повтор события ничего не меняет.
+type ChallengeService struct {
+	challengeRepo repositories.ChallengeRepository
+	events        EventPublisher
+	logger        *zap.Logger
+	now           func() time.Time
+
+	mu         sync.Mutex
+	challenges []domain.Challenge
+	period     time.Time
+	loadedAt   time.Time
+}
+
+// NewChallengeService creates a new streak and challenge service.
+func NewChallengeService(challengeRepo repositories.ChallengeRepository, logger *zap.Logger) *ChallengeService {
+	return &ChallengeService{
+		challengeRepo: challengeRepo,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// WithEvents подключает публикацию событий challenge.completed и achievement.unlocked.
+func (s *ChallengeService) WithEvents(events EventPublisher) *ChallengeService {
+	s.events = events
+	return s
+}
+
+// GetStreak возвращает серию пользователя на сегодня.
+func (s *ChallengeService) GetStreak(ctx context.Context, userID int) (*domain.Streak, error) {
+	streak, err := s.challengeRepo.GetStreak(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get streak")
+	}
+	streak.AsOf(utcDay(s.now()))
+	return streak, nil
+}
+
+// ListChallenges возвращает испытания текущего месяца с прогрессом пользователя.
+func (s *ChallengeService) ListChallenges(ctx context.Context, userID int) ([]domain.ChallengeProgress, error) {
+	period := domain.MonthStart(s.now())
+	progress, err := s.challengeRepo.ListProgress(ctx, userID, period)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list challenges")
+	}
+
+	result := make([]domain.ChallengeProgress, 0, len(progress))
+	for _, p := range progress {
+		if err := validateChallengeRule(p.Rule); err != nil {
+			continue
+		}
+		p.Period = period.Format("2006-01")
+		p.EndsAt = period.AddDate(0, 1, 0)
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// PublishEvent засчитывает заход и продвигает испытания месяца, в котором произошло событие.
+// Рекомендации дайджеста пользователь не запрашивал — они не учитываются вовсе.
+func (s *ChallengeService) PublishEvent(ctx context.Context, event domain.WebhookEvent) error {
+	var data map[string]interface{}
+	if err := decodeEventData(event.Data, &data); err != nil {
+		s.logger.Warn("Failed to decode event data", zap.String("event_id", event.ID), zap.Error(err))
+	}
+	if event.Type == domain.EventRecommendationCreated && data["origin"] == domain.RecommendationOriginDigest {
+		return nil
+	}
+	checkIn := event.Type == domain.EventRecommendationCreated || event.Type == domain.EventRatingSubmitted
+
+	at := event.CreatedAt
+	if at.IsZero() {
+		at = s.now()
+	}
+	challenges, err := s.loadChallenges(ctx, domain.MonthStart(at))
+	if err != nil {
+		return err
+	}
+
+	var steps []domain.ChallengeStep
+	for _, c := range challenges {
+		rule := c.Rule
+		onCheckIn := rule.Event == domain.EventCheckIn
+		if onCheckIn && !checkIn || !onCheckIn && rule.Event != event.Type {
+			continue
+		}
+		if !matchesAchievementConditions(rule.Where, data) {
+			continue
+		}
+
+		step := domain.ChallengeStep{
+			ChallengeID: c.ID,
+			Target:      c.Target,
+			OnCheckIn:   onCheckIn,
+			NewOnly:     rule.NewOnly,
+		}
+		if rule.Distinct != "" {
+			if step.Values = challengeValues(data[rule.Distinct]); len(step.Values) == 0 {
+				continue
+			}
+		}
+		steps = append(steps, step)
+	}
+	if !checkIn && len(steps) == 0 {
+		return nil
+	}
+
+	result, err := s.challengeRepo.ApplyEvent(ctx, domain.GamificationEvent{
+		ID:      event.ID,
+		UserID:  event.UserID,
+		At:      at,
+		CheckIn: checkIn,
+		Steps:   steps,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to apply gamification event")
+	}
+	if result == nil || s.events == nil {
+		return nil
+	}
+
+	period := domain.MonthStart(at).Format("2006-01")
+	for _, c := range result.Completed {
+		s.events.Publish(ctx, event.UserID, domain.EventChallengeCompleted, map[string]interface{}{
+			"challenge_code": c.Code,
+			"period":         period,
+		})
+	}
+	for _, a := range result.Unlocked {
+		s.events.Publish(ctx, event.UserID, domain.EventAchievementUnlocked, map[string]interface{}{
+			"achievement_code": a.Code,
+		})
+	}
+	return nil
+}
+
+// loadChallenges возвращает испытания месяца, перечитывая их не чаще раза в achievementDefinitionsTTL.
+func (s *ChallengeService) loadChallenges(ctx context.Context, period time.Time) ([]domain.Challenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.challenges != nil && s.period.Equal(period) && s.now().Sub(s.loadedAt) < achievementDefinitionsTTL {
+		return s.challenges, nil
+	}
+
+	challenges, err := s.challengeRepo.ListChallenges(ctx, period)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load challenges")
+	}
+
+	valid := make([]domain.Challenge, 0, len(challenges))
+	for _, c := range challenges {
+		if err := validateChallengeRule(c.Rule); err != nil {
+			s.logger.Warn("Skipping challenge with invalid rule", zap.String("code", c.Code), zap.Error(err))
+			continue
+		}
+		valid = append(valid, c)
+	}
+	s.challenges = valid
+	s.period = period
+	s.loadedAt = s.now()
+	return valid, nil
+}
+
+// validateChallengeRule отсекает правила, которые никогда не сработают.
+func validateChallengeRule(rule domain.ChallengeRule) error {
+	if rule.Event != domain.EventCheckIn && !isWebhookEvent(rule.Event) {
+		return errors.Errorf("unknown event %q", rule.Event)
+	}
+	if rule.Count < 0 {
+		return errors.New("count must not be negative")
+	}
+	if rule.NewOnly && rule.Distinct == "" {
+		return errors.New("new_only needs distinct")
+	}
+	return nil
+}
+
+// challengeValues превращает поле события (значение или массив) в список разных строк.
+func challengeValues(v interface{}) []string {
+	items, ok := v.([]interface{})
+	if !ok {
+		items = []interface{}{v}
+	}
+
+	seen := make(map[string]bool, len(items))
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if item == nil {
+			continue
+		}
+		value := truncate(fmt.Sprint(item), challengeValueMaxLen)
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		values = append(values, value)
+	}
+	return values
+}
+
+func utcDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
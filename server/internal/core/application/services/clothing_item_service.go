@@ -6,11 +6,11 @@ import (
 	"log"
 	"math"
 	"outfit-style-rec/contracts"
-	"outfit-style-rec/server/internal/core/application/planner"
-	"outfit-style-rec/server/internal/core/domain"
-	"outfit-style-rec/server/internal/core/repo"
-	"outfit-style-rec/server/internal/infrastructure/clients"
-	"outfit-style-rec/server/internal/infrastructure/services"
+	"outfitstyle/server/internal/core/application/planner"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
+	"outfitstyle/server/internal/infrastructure/clients"
+	"outfitstyle/server/internal/infrastructure/services"
 	"time"
 )
 
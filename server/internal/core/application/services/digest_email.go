@@ -0,0 +1,72 @@
+package services
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"math"
+	"time"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/domain"
+)
+
+//go:embed templates/digest.html
+var digestTemplateSource string
+
+var digestTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"temp": formatDigestTemp,
+	"date": formatDigestDate,
+}).Parse(digestTemplateSource))
+
+// DigestEmail — содержимое утреннего дайджеста: погода, рекомендованный образ,
+// предупреждения и запланированные на день образы.
+type DigestEmail struct {
+	Date     time.Time
+	Location string
+	Weather  domain.WeatherData
+	Items    []domain.ClothingItem
+	Alerts   []alerts.Alert
+	Plans    []DigestPlan
+}
+
+// DigestPlan — запланированный образ (OutfitPlan) с вещами.
+type DigestPlan struct {
+	Notes string
+	Items []domain.ClothingItem
+}
+
+// Subject — тема письма, например «OutfitStyle: образ на 18 октября».
+func (d DigestEmail) Subject() string {
+	return "OutfitStyle: образ на " + formatDigestDate(d.Date)
+}
+
+// HTML renders the digest body.
+func (d DigestEmail) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := digestTemplate.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("failed to render digest: %w", err)
+	}
+	return buf.String(), nil
+}
+
+var genitiveMonths = [...]string{
+	"января", "февраля", "марта", "апреля", "мая", "июня",
+	"июля", "августа", "сентября", "октября", "ноября", "декабря",
+}
+
+func formatDigestDate(t time.Time) string {
+	return fmt.Sprintf("%d %s", t.Day(), genitiveMonths[t.Month()-1])
+}
+
+func formatDigestTemp(t float64) string {
+	r := math.Round(t)
+	if r == 0 {
+		r = 0 // без «-0°C»
+	}
+	if r > 0 {
+		return fmt.Sprintf("+%.0f°C", r)
+	}
+	return fmt.Sprintf("%.0f°C", r)
+}
@@ -0,0 +1,349 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/external"
+	"outfitstyle/server/internal/pkg/gazetteer"
+)
+
+const (
+	// digestBatch — сколько подписок обрабатывается за один запрос к БД.
+	digestBatch = 100
+	// digestMaxDelay — насколько можно опоздать с дайджестом (например, после простоя сервера);
+	// позже утреннее письмо уже бесполезно и пропускается.
+	digestMaxDelay = 2 * time.Hour
+	// digestSource — источник вещей: гардероб пользователя, дополненный каталогом.
+	digestSource = "mixed"
+)
+
+var (
+	// ErrDigestNotFound — пользователь не подписан на дайджест.
+	ErrDigestNotFound = errors.New("digest subscription not found")
+	// ErrInvalidDigest — ошибка валидации подписки; текст причины в обёртке.
+	ErrInvalidDigest = errors.New("invalid digest subscription")
+)
+
+// DigestSubscriptionInput — подписка из запроса. Timezone можно не передавать, если у
+// пользователя есть место по умолчанию или город профиля есть в справочнике.
+type DigestSubscriptionInput struct {
+	SendTime string `json:"send_time"`
+	Timezone string `json:"timezone"`
+}
+
+// DigestService sends the morning outfit digest at each subscriber's local time.
+type DigestService struct {
+	digestRepo            repositories.DigestRepository
+	userRepo              repositories.UserRepository
+	clothingItemRepo      repositories.ClothingItemRepository
+	recommendationService *RecommendationService
+	alertService          *WeatherAlertService
+	weather               external.WeatherProvider
+	emailService          EmailService
+	places                *gazetteer.Gazetteer
+	logger                *zap.Logger
+	now                   func() time.Time
+}
+
+// NewDigestService creates a new morning digest service
+func NewDigestService(
+	digestRepo repositories.DigestRepository,
+	userRepo repositories.UserRepository,
+	clothingItemRepo repositories.ClothingItemRepository,
+	recommendationService *RecommendationService,
+	alertService *WeatherAlertService,
+	weather external.WeatherProvider,
+	emailService EmailService,
+	places *gazetteer.Gazetteer,
+	logger *zap.Logger,
+) *DigestService {
+	return &DigestService{
+		digestRepo:            digestRepo,
+		userRepo:              userRepo,
+		clothingItemRepo:      clothingItemRepo,
+		recommendationService: recommendationService,
+		alertService:          alertService,
+		weather:               weather,
+		emailService:          emailService,
+		places:                places,
+		logger:                logger,
+		now:                   time.Now,
+	}
+}
+
+// NextDigestRun возвращает ближайший момент строго после after, когда в loc наступает
+// sendTime (ЧЧ:ММ). Если из-за перехода на летнее время такого момента в сутках нет,
+// письмо уходит в соседний час.
+func NextDigestRun(sendTime string, loc *time.Location, after time.Time) (time.Time, error) {
+	t, err := time.Parse("15:04", sendTime)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	local := after.In(loc)
+	next := time.Date(local.Year(), local.Month(), local.Day(), t.Hour(), t.Minute(), 0, 0, loc)
+	if !next.After(after) {
+		next = time.Date(local.Year(), local.Month(), local.Day()+1, t.Hour(), t.Minute(), 0, 0, loc)
+	}
+	return next, nil
+}
+
+// GetSubscription returns the user's subscription or ErrDigestNotFound.
+func (s *DigestService) GetSubscription(ctx context.Context, userID int) (*domain.DigestSubscription, error) {
+	sub, err := s.digestRepo.GetSubscription(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, ErrDigestNotFound
+	}
+	return sub, nil
+}
+
+// Subscribe включает дайджест или меняет его время. Дайджест собирается для места
+// по умолчанию, а без него — для города из профиля, поэтому одно из них обязательно.
+func (s *DigestService) Subscribe(ctx context.Context, userID int, in DigestSubscriptionInput) (*domain.DigestSubscription, error) {
+	target, err := s.digestRepo.GetTarget(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrUserNotFound
+	}
+	if target.Location == nil && strings.TrimSpace(target.City) == "" {
+		return nil, errors.Wrapf(ErrInvalidDigest, "set a city in the profile or save a default location first")
+	}
+
+	sendTime := strings.TrimSpace(in.SendTime)
+	if _, err := time.Parse("15:04", sendTime); err != nil {
+		return nil, errors.Wrapf(ErrInvalidDigest, "send_time must be HH:MM")
+	}
+
+	timezone := strings.TrimSpace(in.Timezone)
+	if timezone == "" {
+		tz, ok := targetTimezone(s.places, *target)
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidDigest, "timezone is required for city %q", target.City)
+		}
+		timezone = tz
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil || timezone == "Local" {
+		return nil, errors.Wrapf(ErrInvalidDigest, "unknown timezone %q", timezone)
+	}
+
+	next, err := NextDigestRun(sendTime, loc, s.now())
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidDigest, err.Error())
+	}
+
+	sub := &domain.DigestSubscription{
+		UserID:    userID,
+		Enabled:   true,
+		SendTime:  sendTime,
+		Timezone:  timezone,
+		NextRunAt: next,
+	}
+	if err := s.digestRepo.SaveSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Unsubscribe выключает дайджест; время и часовой пояс сохраняются до повторной подписки.
+func (s *DigestService) Unsubscribe(ctx context.Context, userID int) (*domain.DigestSubscription, error) {
+	sub, err := s.GetSubscription(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Enabled {
+		return sub, nil
+	}
+
+	sub.Enabled = false
+	if err := s.digestRepo.SaveSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+// Run рассылает наступившие дайджесты сразу и затем каждые interval, пока ctx не отменён.
+func (s *DigestService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.SendDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Outfit digest run failed", zap.Error(err))
+		} else if sent > 0 {
+			s.logger.Info("Outfit digests sent", zap.Int("emails", sent))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDue sends every digest whose local send time has come and returns the number of emails sent.
+//
+// Перед отправкой подписка переносится на следующий день, а доставка занимается в
+// digest_deliveries по местной дате, поэтому ни повторный запуск после сбоя, ни несколько
+// экземпляров сервера не пришлют дайджест дважды. Письмо, прерванное сбоем, не повторяется.
+func (s *DigestService) SendDue(ctx context.Context) (int, error) {
+	sent := 0
+	for {
+		now := s.now()
+		subs, err := s.digestRepo.ListDue(ctx, now, digestBatch)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, sub := range subs {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			ok, err := s.deliver(ctx, sub, now)
+			if err != nil {
+				return sent, err
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(subs) < digestBatch {
+			return sent, nil
+		}
+	}
+}
+
+// deliver обрабатывает одну наступившую подписку; true — письмо отправлено.
+// Ошибка возвращается, только если не удалось перенести подписку: иначе она снова
+// попала бы в ListDue.
+func (s *DigestService) deliver(ctx context.Context, sub domain.DigestSubscription, now time.Time) (bool, error) {
+	loc := loadLocationOrUTC(sub.Timezone)
+	day := sub.NextRunAt.In(loc)
+
+	next, err := NextDigestRun(sub.SendTime, loc, now)
+	if err != nil {
+		return false, errors.Wrapf(err, "invalid send time for user %d", sub.UserID)
+	}
+	if err := s.digestRepo.ScheduleNext(ctx, sub.UserID, next); err != nil {
+		return false, err
+	}
+
+	log := s.logger.With(zap.Int("user_id", sub.UserID), zap.String("digest_date", day.Format("2006-01-02")))
+
+	if late := now.Sub(sub.NextRunAt); late > digestMaxDelay {
+		log.Warn("Outfit digest skipped: too late", zap.Duration("late", late))
+		return false, nil
+	}
+
+	claimed, err := s.digestRepo.ClaimDelivery(ctx, sub.UserID, day)
+	if err != nil {
+		log.Warn("Failed to claim outfit digest", zap.Error(err))
+		return false, nil
+	}
+	if !claimed {
+		return false, nil
+	}
+
+	status, sendErr := s.send(ctx, sub.UserID, day)
+	errText := ""
+	if sendErr != nil {
+		errText = sendErr.Error()
+		log.Warn("Outfit digest not sent", zap.String("status", status), zap.Error(sendErr))
+	}
+	if err := s.digestRepo.FinishDelivery(ctx, sub.UserID, day, status, errText); err != nil {
+		log.Warn("Failed to record outfit digest delivery", zap.Error(err))
+	}
+	return status == domain.DigestStatusSent, nil
+}
+
+// send собирает и отправляет дайджест на день day; возвращает статус доставки.
+func (s *DigestService) send(ctx context.Context, userID int, day time.Time) (string, error) {
+	target, err := s.digestRepo.GetTarget(ctx, userID)
+	if err != nil {
+		return domain.DigestStatusFailed, err
+	}
+	if target == nil || (target.Location == nil && strings.TrimSpace(target.City) == "") {
+		return domain.DigestStatusSkipped, errors.New("no location to build the digest for")
+	}
+
+	weather, name, _, err := targetWeather(ctx, s.weather, s.places, *target)
+	if err != nil {
+		if errors.Is(err, external.ErrCityNotFound) {
+			return domain.DigestStatusSkipped, err
+		}
+		return domain.DigestStatusFailed, errors.Wrap(err, "failed to get weather")
+	}
+
+	digest := DigestEmail{
+		Date:     day,
+		Location: name,
+		Weather:  weather.WeatherData,
+	}
+
+	// Без ML-рекомендации дайджест всё равно полезен: погода, предупреждения и планы
+	rec, err := s.recommendationService.GetDigestRecommendations(ctx, domain.RecommendationRequest{
+		UserID:      domain.ID(userID),
+		WeatherData: weather.WeatherData,
+	}, digestSource)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return domain.DigestStatusSkipped, err
+		}
+		s.logger.Warn("Digest built without recommendation", zap.Int("user_id", userID), zap.Error(err))
+	} else {
+		digest.Items = rec.Items
+	}
+
+	if s.alertService != nil {
+		digest.Alerts = s.alertService.Evaluate(weather.WeatherData)
+	}
+
+	digest.Plans = s.dayPlans(ctx, userID, day)
+
+	if err := s.emailService.SendDigestEmail(target.Email, digest); err != nil {
+		return domain.DigestStatusFailed, errors.Wrap(err, "failed to send digest email")
+	}
+	return domain.DigestStatusSent, nil
+}
+
+// dayPlans загружает образы, запланированные на местную дату day. Ошибки только
+// логируются: дайджест без планов лучше, чем никакого.
+func (s *DigestService) dayPlans(ctx context.Context, userID int, day time.Time) []DigestPlan {
+	date := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC)
+	plans, err := s.userRepo.GetOutfitPlans(ctx, userID, date, date)
+	if err != nil {
+		s.logger.Warn("Failed to load outfit plans for digest", zap.Int("user_id", userID), zap.Error(err))
+		return nil
+	}
+
+	var out []DigestPlan
+	for _, plan := range plans {
+		p := DigestPlan{Notes: plan.Notes}
+		for _, id := range plan.ItemIDs {
+			item, err := s.clothingItemRepo.GetByID(ctx, domain.ID(id))
+			if err != nil || item == nil {
+				continue
+			}
+			p.Items = append(p.Items, *item)
+		}
+		if p.Notes != "" || len(p.Items) > 0 {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"net/smtp"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/alerts"
 )
 
 // EmailService defines the interface for email operations
 type EmailService interface {
 	SendVerificationEmail(to, code string) error
 	SendPasswordResetEmail(to, token string) error
+	SendWeatherAlertEmail(to, location string, active []alerts.Alert) error
+	SendDigestEmail(to string, digest DigestEmail) error
+	SendAccountLockedEmail(to string, lockedFor time.Duration) error
 }
 
 // SMTPConfig holds SMTP configuration
@@ -50,8 +56,13 @@ func NewEmailService(
 	}
 }
 
-// sendEmail — общий метод для отправки писем
+// sendEmail — общий метод для отправки текстовых писем
 func (s *SMTPEmailService) sendEmail(to, subject, body string) error {
+	return s.send(to, subject, "text/plain", body)
+}
+
+// send отправляет письмо с телом типа contentType (text/plain или text/html)
+func (s *SMTPEmailService) send(to, subject, contentType, body string) error {
 	addr := fmt.Sprintf("%s:%d", s.config.Host, s.config.Port)
 
 	// PlainAuth: подходит для Mailhog/локального SMTP и многих провайдеров.
@@ -66,7 +77,7 @@ func (s *SMTPEmailService) sendEmail(to, subject, body string) error {
 		"To":           to,
 		"Subject":      subject,
 		"MIME-Version": "1.0",
-		"Content-Type": "text/plain; charset=\"UTF-8\"",
+		"Content-Type": contentType + "; charset=\"UTF-8\"",
 	}
 
 	var msgBuilder strings.Builder
@@ -105,6 +116,44 @@ func (s *SMTPEmailService) SendPasswordResetEmail(to, token string) error {
 	return s.sendEmail(to, subject, body)
 }
 
+// SendWeatherAlertEmail sends severe-weather alerts for the user's location
+func (s *SMTPEmailService) SendWeatherAlertEmail(to, location string, active []alerts.Alert) error {
+	subject := "OutfitStyle: предупреждение о погоде"
+	if alerts.MaxSeverity(active) == alerts.Danger {
+		subject = "OutfitStyle: опасная погода"
+	}
+	return s.sendEmail(to, subject, weatherAlertBody(location, active))
+}
+
+// SendDigestEmail sends the morning outfit digest as HTML
+func (s *SMTPEmailService) SendDigestEmail(to string, digest DigestEmail) error {
+	body, err := digest.HTML()
+	if err != nil {
+		return err
+	}
+	return s.send(to, digest.Subject(), "text/html", body)
+}
+
+// SendAccountLockedEmail warns the user that login is locked after failed attempts
+func (s *SMTPEmailService) SendAccountLockedEmail(to string, lockedFor time.Duration) error {
+	subject := "OutfitStyle: вход временно заблокирован"
+	body := fmt.Sprintf("Из-за нескольких неудачных попыток входа вход в ваш аккаунт заблокирован на %d мин.\n\n"+
+		"Если это были не вы, смените пароль и включите двухфакторную аутентификацию.",
+		int(lockedFor.Round(time.Minute)/time.Minute))
+	return s.sendEmail(to, subject, body)
+}
+
+// weatherAlertBody — текст письма: по абзацу на предупреждение
+func weatherAlertBody(location string, active []alerts.Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Сегодня в месте «%s»:\n", location)
+	for _, a := range active {
+		fmt.Fprintf(&b, "\n%s. %s\n", a.Title, a.Message)
+	}
+	b.WriteString("\nОбраз с учётом погоды — в приложении OutfitStyle.")
+	return b.String()
+}
+
 // NoopEmailService implements EmailService with no-op operations
 type NoopEmailService struct{}
 
@@ -124,3 +173,21 @@ func (n *NoopEmailService) SendPasswordResetEmail(to, token string) error {
 	fmt.Printf("NOOP: Would send password reset email to %s with token %s\n", to, token)
 	return nil
 }
+
+// SendWeatherAlertEmail does nothing
+func (n *NoopEmailService) SendWeatherAlertEmail(to, location string, active []alerts.Alert) error {
+	fmt.Printf("NOOP: Would send %d weather alerts for %s to %s\n", len(active), location, to)
+	return nil
+}
+
+// SendDigestEmail does nothing
+func (n *NoopEmailService) SendDigestEmail(to string, digest DigestEmail) error {
+	fmt.Printf("NOOP: Would send outfit digest for %s with %d items to %s\n", digest.Location, len(digest.Items), to)
+	return nil
+}
+
+// SendAccountLockedEmail does nothing
+func (n *NoopEmailService) SendAccountLockedEmail(to string, lockedFor time.Duration) error {
+	fmt.Printf("NOOP: Would send account locked email to %s (locked for %s)\n", to, lockedFor)
+	return nil
+}
@@ -0,0 +1,115 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"outfitstyle/server/internal/core/application/repositories"
+)
+
+// loginKeyMaxLen — длина ключей auth_login_failures и auth_lockouts.
+const loginKeyMaxLen = 255
+
+// LoginLockedError — вход временно заблокирован после неудачных попыток.
+type LoginLockedError struct {
+	Until time.Time
+}
+
+func (e *LoginLockedError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, login is locked until %s", e.Until.UTC().Format(time.RFC3339))
+}
+
+// LoginThrottleConfig — лимиты неудачных попыток входа за скользящее окно Window.
+type LoginThrottleConfig struct {
+	MaxAttempts      int // на аккаунт (email)
+	MaxAttemptsPerIP int // на IP: за одним адресом бывает много пользователей
+	Window           time.Duration
+	BlockDuration    time.Duration
+}
+
+// loginThrottle считает неудачные входы по аккаунту и по IP в AuthStateRepository,
+// поэтому лимиты общие для всех реплик.
+type loginThrottle struct {
+	stateRepo repositories.AuthStateRepository
+	config    LoginThrottleConfig
+	now       func() time.Time
+}
+
+func newLoginThrottle(stateRepo repositories.AuthStateRepository, config LoginThrottleConfig) *loginThrottle {
+	return &loginThrottle{
+		stateRepo: stateRepo,
+		config:    config,
+		now:       time.Now,
+	}
+}
+
+// Check возвращает *LoginLockedError, если вход с аккаунта или IP заблокирован.
+func (t *loginThrottle) Check(ctx context.Context, email, ip string) error {
+	until, err := t.stateRepo.LockedUntil(ctx, loginThrottleKeys(email, ip), t.now())
+	if err != nil {
+		return errors.Wrap(err, "failed to check login lockout")
+	}
+	if !until.IsZero() {
+		return &LoginLockedError{Until: until}
+	}
+	return nil
+}
+
+// Fail засчитывает неудачную попытку. accountLocked — эта попытка заблокировала аккаунт
+// (пора предупредить владельца); при любой новой блокировке возвращается *LoginLockedError.
+func (t *loginThrottle) Fail(ctx context.Context, email, ip string) (accountLocked bool, err error) {
+	now := t.now()
+	until, err := t.stateRepo.RecordLoginFailure(ctx, loginAccountKey(email),
+		t.config.MaxAttempts, t.config.Window, t.config.BlockDuration, now)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to record login failure")
+	}
+	accountLocked = !until.IsZero()
+
+	if ip != "" {
+		ipUntil, err := t.stateRepo.RecordLoginFailure(ctx, loginIPKey(ip),
+			t.config.MaxAttemptsPerIP, t.config.Window, t.config.BlockDuration, now)
+		if err != nil {
+			return accountLocked, errors.Wrap(err, "failed to record login failure")
+		}
+		if ipUntil.After(until) {
+			until = ipUntil
+		}
+	}
+
+	if !until.IsZero() {
+		return accountLocked, &LoginLockedError{Until: until}
+	}
+	return false, nil
+}
+
+// Succeed сбрасывает счётчик аккаунта после успешного входа. Счётчик IP не трогается:
+// иначе подбор паролей к чужим аккаунтам с одного адреса можно продлевать входом в свой.
+func (t *loginThrottle) Succeed(ctx context.Context, email string) error {
+	if err := t.stateRepo.ResetLoginFailures(ctx, []string{loginAccountKey(email)}); err != nil {
+		return errors.Wrap(err, "failed to reset login failures")
+	}
+	return nil
+}
+
+func loginThrottleKeys(email, ip string) []string {
+	keys := []string{loginAccountKey(email)}
+	if ip != "" {
+		keys = append(keys, loginIPKey(ip))
+	}
+	return keys
+}
+
+// loginAccountKey строится по email, а не по ID: несуществующий email считается так же,
+// и по блокировке нельзя понять, есть ли такой аккаунт.
+func loginAccountKey(email string) string {
+	return truncate("login:account:"+strings.ToLower(strings.TrimSpace(email)), loginKeyMaxLen)
+}
+
+func loginIPKey(ip string) string {
+	return truncate("login:ip:"+ip, loginKeyMaxLen)
+}
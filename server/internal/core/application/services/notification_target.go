@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/external"
+	"outfitstyle/server/internal/pkg/gazetteer"
+)
+
+// targetWeather запрашивает прогноз для места получателя и возвращает название места
+// для письма и его часовой пояс. Без сохранённого места используется город из профиля:
+// если он есть в справочнике — по его координатам и часовому поясу, иначе по названию и в UTC.
+func targetWeather(
+	ctx context.Context,
+	weather external.WeatherProvider,
+	places *gazetteer.Gazetteer,
+	t domain.NotificationTarget,
+) (*domain.ExtendedWeatherData, string, *time.Location, error) {
+	if saved := t.Location; saved != nil {
+		name := saved.Name
+		if places != nil && saved.LocationID != "" {
+			if place, ok := places.Get(saved.LocationID); ok {
+				name = place.DisplayName()
+			}
+		}
+		data, err := weather.GetWeatherByCoords(ctx, saved.Coordinates)
+		return data, name, loadLocationOrUTC(saved.Timezone), err
+	}
+
+	if places != nil {
+		if place, ok := places.Resolve(t.City); ok {
+			data, err := weather.GetWeatherByCoords(ctx, domain.Coordinates{Lat: place.Lat, Lon: place.Lon})
+			return data, place.DisplayName(), loadLocationOrUTC(place.Timezone), err
+		}
+	}
+
+	data, err := weather.GetWeather(ctx, t.City)
+	return data, t.City, time.UTC, err
+}
+
+// targetTimezone — часовой пояс места получателя: сохранённого места или города из справочника.
+// false, если пояс неизвестен (города нет в справочнике).
+func targetTimezone(places *gazetteer.Gazetteer, t domain.NotificationTarget) (string, bool) {
+	if t.Location != nil && t.Location.Timezone != "" {
+		return t.Location.Timezone, true
+	}
+	if places != nil && t.City != "" {
+		if place, ok := places.Resolve(t.City); ok && place.Timezone != "" {
+			return place.Timezone, true
+		}
+	}
+	return "", false
+}
+
+func loadLocationOrUTC(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
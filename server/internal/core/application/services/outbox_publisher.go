@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// OutboxPublisher — EventPublisher, который пишет события в outbox. Оттуда OutboxRelay
+// раздаёт их всем получателям (вебхукам, статистике) с повторами при ошибках.
+type OutboxPublisher struct {
+	outboxRepo repositories.OutboxRepository
+	logger     *zap.Logger
+}
+
+// NewOutboxPublisher creates a new outbox publisher.
+func NewOutboxPublisher(outboxRepo repositories.OutboxRepository, logger *zap.Logger) *OutboxPublisher {
+	return &OutboxPublisher{
+		outboxRepo: outboxRepo,
+		logger:     logger,
+	}
+}
+
+// Publish записывает событие в outbox.
+func (p *OutboxPublisher) Publish(ctx context.Context, userID int, event string, data interface{}) {
+	log := p.logger.With(zap.Int("user_id", userID), zap.String("event", event))
+
+	e, err := newOutboxEvent(userID, event, data)
+	if err != nil {
+		log.Warn("Failed to build event", zap.Error(err))
+		return
+	}
+	if err := p.outboxRepo.Add(ctx, []domain.OutboxEvent{e}); err != nil {
+		log.Warn("Failed to publish event", zap.Error(err))
+	}
+}
+
+// newOutboxEvent строит событие для outbox: репозитории пишут его той же транзакцией,
+// что и изменение, о котором оно сообщает.
+func newOutboxEvent(userID int, event string, data interface{}) (domain.OutboxEvent, error) {
+	eventID, err := randomHex(16)
+	if err != nil {
+		return domain.OutboxEvent{}, errors.Wrap(err, "failed to generate event ID")
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return domain.OutboxEvent{}, errors.Wrap(err, "failed to encode event")
+	}
+	return domain.OutboxEvent{
+		EventID: "evt_" + eventID,
+		UserID:  userID,
+		Type:    event,
+		Data:    payload,
+	}, nil
+}
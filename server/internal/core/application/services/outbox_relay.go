@@ -0,0 +1,170 @@
+package services
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+const (
+	outboxBatchSize  = 100
+	outboxLease      = time.Minute // событие, забранное упавшим сервером, снова доступно через минуту
+	outboxMaxBackoff = 10 * time.Minute
+	outboxRetention  = 7 * 24 * time.Hour
+)
+
+// EventSink принимает события из outbox. Повтор события с тем же ID
+// не должен повторять его эффект (см. WebhookService.PublishEvent).
+type EventSink interface {
+	PublishEvent(ctx context.Context, event domain.WebhookEvent) error
+}
+
+// EventSinks раздаёт событие нескольким получателям. Ошибка одного не мешает остальным,
+// но возвращается — и при повторе событие снова получат все.
+type EventSinks []EventSink
+
+// PublishEvent передаёт событие каждому получателю и возвращает первую ошибку.
+func (s EventSinks) PublishEvent(ctx context.Context, event domain.WebhookEvent) error {
+	var firstErr error
+	for _, sink := range s {
+		if err := sink.PublishEvent(ctx, event); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// OutboxRelay публикует события, записанные в outbox вместе с породившими их изменениями.
+// Событие публикуется не раньше коммита и не теряется при падении сервера.
+type OutboxRelay struct {
+	outboxRepo repositories.OutboxRepository
+	sink       EventSink
+	logger     *zap.Logger
+	now        func() time.Time
+}
+
+// NewOutboxRelay creates a new outbox relay.
+func NewOutboxRelay(outboxRepo repositories.OutboxRepository, sink EventSink, logger *zap.Logger) *OutboxRelay {
+	return &OutboxRelay{
+		outboxRepo: outboxRepo,
+		sink:       sink,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// Run публикует накопившиеся события сразу и затем каждые interval, пока ctx не отменён.
+// Раз в час удаляет опубликованные события старше недели.
+func (r *OutboxRelay) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	cleanup := time.NewTicker(time.Hour)
+	defer cleanup.Stop()
+
+	for {
+		published, err := r.RelayPending(ctx)
+		if err != nil && ctx.Err() == nil {
+			r.logger.Error("Outbox relay failed", zap.Error(err))
+		} else if published > 0 {
+			r.logger.Debug("Outbox events published", zap.Int("events", published))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-cleanup.C:
+			if deleted, err := r.outboxRepo.DeletePublished(ctx, r.now().Add(-outboxRetention)); err != nil {
+				r.logger.Error("Failed to clean up outbox", zap.Error(err))
+			} else if deleted > 0 {
+				r.logger.Info("Published outbox events cleaned up", zap.Int64("deleted", deleted))
+			}
+		}
+	}
+}
+
+// RelayPending публикует готовые события, пока они не кончатся, и возвращает число
+// опубликованных. Неудачная публикация откладывает событие, не задерживая остальные.
+func (r *OutboxRelay) RelayPending(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		now := r.now()
+		events, err := r.outboxRepo.ClaimPending(ctx, now, now.Add(outboxLease), outboxBatchSize)
+		if err != nil {
+			return published, err
+		}
+
+		for _, e := range events {
+			if err := ctx.Err(); err != nil {
+				// Остальные забранные события вернутся после истечения аренды
+				return published, err
+			}
+			if r.publish(ctx, e) {
+				published++
+			}
+		}
+
+		if len(events) < outboxBatchSize {
+			return published, nil
+		}
+	}
+}
+
+// publish публикует одно событие и записывает результат.
+func (r *OutboxRelay) publish(ctx context.Context, e domain.OutboxEvent) bool {
+	err := r.sink.PublishEvent(ctx, domain.WebhookEvent{
+		ID:        e.EventID,
+		Type:      e.Type,
+		CreatedAt: e.CreatedAt.UTC(),
+		UserID:    e.UserID,
+		Data:      e.Data,
+	})
+	if err == nil {
+		err = r.outboxRepo.MarkPublished(ctx, e.ID, e.NextAttemptAt)
+		if err == nil {
+			return true
+		}
+		if errors.Is(err, repositories.ErrOutboxLeaseLost) {
+			// Событие уже публикует другой relay — его итог главнее
+			r.logger.Warn("Outbox event lease expired before it was marked published", zap.String("event_id", e.EventID))
+			return false
+		}
+		// Событие опубликовано, но не отмечено: повтор по тому же ID безопасен
+		r.logger.Error("Failed to mark outbox event published", zap.String("event_id", e.EventID), zap.Error(err))
+		return false
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+
+	next := r.now().Add(outboxBackoff(e.Attempts + 1))
+	r.logger.Warn("Failed to publish outbox event",
+		zap.String("event_id", e.EventID),
+		zap.String("type", e.Type),
+		zap.Int("attempt", e.Attempts+1),
+		zap.Time("next_attempt_at", next),
+		zap.Error(err),
+	)
+	err = r.outboxRepo.MarkFailed(ctx, e.ID, e.NextAttemptAt, next, truncate(err.Error(), 500))
+	if errors.Is(err, repositories.ErrOutboxLeaseLost) {
+		r.logger.Warn("Outbox event lease expired before the attempt was recorded", zap.String("event_id", e.EventID))
+	} else if err != nil {
+		r.logger.Error("Failed to record outbox attempt", zap.String("event_id", e.EventID), zap.Error(err))
+	}
+	return false
+}
+
+// outboxBackoff — пауза после attempts неудачных попыток: 5с, 10с, 20с… до 10 минут.
+func outboxBackoff(attempts int) time.Duration {
+	d := float64(5*time.Second) * math.Pow(2, float64(attempts-1))
+	if d > float64(outboxMaxBackoff) {
+		return outboxMaxBackoff
+	}
+	return time.Duration(d)
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/storage"
+	"outfitstyle/server/internal/pkg/imaging"
+)
+
+var (
+	// ErrItemNotInWardrobe возвращается при попытке прикрепить фото к чужой вещи.
+	ErrItemNotInWardrobe = errors.New("item is not in user's wardrobe")
+	// ErrPhotoTooLarge — файл или разрешение больше допустимого.
+	ErrPhotoTooLarge = errors.New("photo is too large")
+	// ErrUnsupportedPhotoType — поддерживаются только JPEG и PNG.
+	ErrUnsupportedPhotoType = errors.New("unsupported photo type: only JPEG and PNG are allowed")
+)
+
+// allowedPhotoTypes — MIME-тип по сигнатуре файла -> расширение ключа в хранилище.
+var allowedPhotoTypes = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+// PhotoConfig — ограничения загрузки и параметры ссылок.
+type PhotoConfig struct {
+	MaxBytes      int64
+	ThumbnailSize int
+	URLTTL        time.Duration
+}
+
+// PhotoService handles wardrobe item photos: validation, thumbnails, storage and signed URLs.
+type PhotoService struct {
+	photoRepo repositories.PhotoRepository
+	storage   storage.Storage
+	cfg       PhotoConfig
+	logger    *zap.Logger
+}
+
+// NewPhotoService creates a new photo service
+func NewPhotoService(
+	photoRepo repositories.PhotoRepository,
+	store storage.Storage,
+	cfg PhotoConfig,
+	logger *zap.Logger,
+) *PhotoService {
+	return &PhotoService{
+		photoRepo: photoRepo,
+		storage:   store,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// MaxBytes — предел размера файла, handler ограничивает им тело запроса.
+func (s *PhotoService) MaxBytes() int64 {
+	return s.cfg.MaxBytes
+}
+
+// Check проверяет размер файла и тип по содержимому (не по Content-Type клиента).
+func (s *PhotoService) Check(data []byte) (string, error) {
+	if int64(len(data)) > s.cfg.MaxBytes {
+		return "", ErrPhotoTooLarge
+	}
+	contentType := http.DetectContentType(data)
+	if _, ok := allowedPhotoTypes[contentType]; !ok {
+		return "", ErrUnsupportedPhotoType
+	}
+	return contentType, nil
+}
+
+// Upload проверяет фото, строит превью, кладёт оба объекта в хранилище и заменяет прежнее фото вещи.
+func (s *PhotoService) Upload(ctx context.Context, userID int, itemID int64, data []byte) (*domain.ItemPhoto, error) {
+	contentType, err := s.Check(data)
+	if err != nil {
+		return nil, err
+	}
+	ext := allowedPhotoTypes[contentType]
+
+	inWardrobe, err := s.photoRepo.IsInWardrobe(ctx, userID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	if !inWardrobe {
+		return nil, ErrItemNotInWardrobe
+	}
+
+	info, err := imaging.Inspect(data)
+	if err != nil {
+		if errors.Is(err, imaging.ErrTooLarge) {
+			return nil, ErrPhotoTooLarge
+		}
+		return nil, ErrUnsupportedPhotoType
+	}
+
+	thumb, err := imaging.Thumbnail(data, s.cfg.ThumbnailSize, 80)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate thumbnail")
+	}
+
+	// Случайное имя: ссылка на старое фото не должна показывать новое, и наоборот
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	prefix := fmt.Sprintf("wardrobe/%d/%d/%s", userID, itemID, name)
+
+	photo := &domain.ItemPhoto{
+		UserID:       userID,
+		ItemID:       itemID,
+		StorageKey:   prefix + "." + ext,
+		ThumbnailKey: prefix + "_thumb.jpg",
+		ContentType:  contentType,
+		SizeBytes:    int64(len(data)),
+		Width:        info.Width,
+		Height:       info.Height,
+	}
+
+	if err := s.storage.Put(ctx, photo.StorageKey, data, contentType); err != nil {
+		return nil, errors.Wrap(err, "failed to store photo")
+	}
+	if err := s.storage.Put(ctx, photo.ThumbnailKey, thumb, "image/jpeg"); err != nil {
+		s.deleteObjects(ctx, photo)
+		return nil, errors.Wrap(err, "failed to store thumbnail")
+	}
+
+	previous, err := s.photoRepo.SavePhoto(ctx, photo)
+	if err != nil {
+		s.deleteObjects(ctx, photo)
+		return nil, err
+	}
+	if previous != nil {
+		s.deleteObjects(ctx, previous)
+	}
+
+	s.logger.Info("Wardrobe photo uploaded",
+		zap.Int("user_id", userID),
+		zap.Int64("item_id", itemID),
+		zap.Int64("size_bytes", photo.SizeBytes),
+	)
+	return photo, nil
+}
+
+// Delete удаляет фото вещи из БД и хранилища.
+func (s *PhotoService) Delete(ctx context.Context, userID int, itemID int64) error {
+	photo, err := s.photoRepo.DeletePhoto(ctx, userID, itemID)
+	if err != nil {
+		return err
+	}
+	if photo != nil {
+		s.deleteObjects(ctx, photo)
+	}
+	return nil
+}
+
+// URLs возвращает подписанные ссылки на фото и превью.
+func (s *PhotoService) URLs(ctx context.Context, photo *domain.ItemPhoto) (photoURL, thumbnailURL string, err error) {
+	if photoURL, err = s.storage.SignedURL(ctx, photo.StorageKey, s.cfg.URLTTL); err != nil {
+		return "", "", err
+	}
+	if thumbnailURL, err = s.storage.SignedURL(ctx, photo.ThumbnailKey, s.cfg.URLTTL); err != nil {
+		return "", "", err
+	}
+	return photoURL, thumbnailURL, nil
+}
+
+// AttachURLs заполняет PhotoURL/ThumbnailURL у вещей, к которым пользователь загрузил фото.
+// Ошибка хранилища не ломает ответ: вещь просто вернётся без фото.
+func (s *PhotoService) AttachURLs(ctx context.Context, userID int, items []domain.ClothingItem) {
+	if len(items) == 0 {
+		return
+	}
+
+	ids := make([]int64, len(items))
+	for i := range items {
+		ids[i] = items[i].ID
+	}
+
+	photos, err := s.photoRepo.GetPhotos(ctx, userID, ids)
+	if err != nil {
+		s.logger.Warn("Failed to load wardrobe photos", zap.Error(err), zap.Int("user_id", userID))
+		return
+	}
+
+	for i := range items {
+		photo, ok := photos[items[i].ID]
+		if !ok {
+			continue
+		}
+		photoURL, thumbURL, err := s.URLs(ctx, &photo)
+		if err != nil {
+			s.logger.Warn("Failed to sign photo URL", zap.Error(err), zap.Int64("item_id", items[i].ID))
+			continue
+		}
+		items[i].PhotoURL = photoURL
+		items[i].ThumbnailURL = thumbURL
+	}
+}
+
+func (s *PhotoService) deleteObjects(ctx context.Context, photo *domain.ItemPhoto) {
+	for _, key := range []string{photo.StorageKey, photo.ThumbnailKey} {
+		if err := s.storage.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			s.logger.Warn("Failed to delete photo object", zap.Error(err), zap.String("key", key))
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "failed to generate photo name")
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"outfitstyle/server/internal/core/application/planner"
+	"outfitstyle/server/internal/infrastructure/external"
+)
+
+var errNoForecastForDate = errors.New("no forecast for date")
+
+// PlanForecast отдаёт планировщику образа (planner.ForecastSource) прогноз на день
+// от провайдеров погоды.
+type PlanForecast struct {
+	provider external.DailyForecastProvider
+}
+
+// NewPlanForecast создаёт источник прогноза для planner.OutfitPlanner.WithWeather.
+func NewPlanForecast(provider external.DailyForecastProvider) *PlanForecast {
+	return &PlanForecast{provider: provider}
+}
+
+// DayForecast возвращает погоду на местную дату date. Температура дня — середина между
+// дневными минимумом и максимумом.
+func (f *PlanForecast) DayForecast(ctx context.Context, location string, date time.Time) (planner.DayWeather, error) {
+	days, err := f.provider.GetDailyForecast(ctx, location, planner.ForecastHorizon+1)
+	if err != nil {
+		return planner.DayWeather{}, err
+	}
+
+	want := date.Format("2006-01-02")
+	for _, d := range days {
+		if d.Date.Format("2006-01-02") != want {
+			continue
+		}
+		return planner.DayWeather{
+			Temperature: (d.MinTemp + d.MaxTemp) / 2,
+			Condition:   planner.WeatherCondition(d.Condition),
+			WindSpeed:   d.WindSpeed,
+		}, nil
+	}
+	return planner.DayWeather{}, errNoForecastForDate
+}
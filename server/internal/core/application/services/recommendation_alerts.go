@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"math"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// CatalogCandidateFinder ищет в каталоге вещи подкатегорий, подходящие по температуре
+// (каталожный ClothingItemRepo; вещи на карантине он не отдаёт).
+type CatalogCandidateFinder interface {
+	FindCandidatesByPlan(ctx context.Context, category string, subcategories []string, warmthMin int16, temp int16, limit int) ([]domain.ClothingItem, error)
+}
+
+// WithAlerts включает предупреждения об опасной погоде в рекомендациях: защитная вещь заменяет
+// в комплекте вещь своей категории, нежелательные вещи убираются. Защитные вещи ищутся
+// в гардеробе, затем в catalog (может быть nil).
+func (s *RecommendationService) WithAlerts(alertService *WeatherAlertService, catalog CatalogCandidateFinder) *RecommendationService {
+	s.alerts = alertService
+	s.catalog = catalog
+	return s
+}
+
+// applyAlerts применяет к вещам рекомендации предупреждения по её погоде. Категории, для которых
+// защитной вещи не нашлось ни в гардеробе, ни в каталоге, остаются как были.
+func (s *RecommendationService) applyAlerts(
+	ctx context.Context,
+	userID domain.ID,
+	weather domain.WeatherData,
+	items []domain.ClothingItem,
+	source string,
+) []domain.ClothingItem {
+	if s.alerts == nil {
+		return items
+	}
+	active := s.alerts.Evaluate(weather)
+	if len(active) == 0 {
+		return items
+	}
+
+	avoid := make(map[alerts.Item]bool)
+	protective := make(map[string][]string)
+	var categories []string
+	for _, a := range active {
+		for _, item := range a.Avoid {
+			avoid[item] = true
+		}
+		for _, item := range a.Protective {
+			if _, ok := protective[item.Category]; !ok {
+				categories = append(categories, item.Category)
+			}
+			if !slices.Contains(protective[item.Category], item.Subcategory) {
+				protective[item.Category] = append(protective[item.Category], item.Subcategory)
+			}
+		}
+	}
+
+	kept := make([]domain.ClothingItem, 0, len(items))
+	for _, item := range items {
+		if !avoid[alerts.Item{Category: item.Category, Subcategory: item.Subcategory}] {
+			kept = append(kept, item)
+		}
+	}
+
+	finder := protectiveFinder{service: s, userID: userID, source: source, temperature: weather.Temperature}
+	for _, category := range categories {
+		subcategories := protective[category]
+		replace := -1
+		covered := false
+		for i, item := range kept {
+			if item.Category != category {
+				continue
+			}
+			if slices.Contains(subcategories, item.Subcategory) {
+				covered = true
+				break
+			}
+			if replace < 0 {
+				replace = i
+			}
+		}
+		if covered {
+			continue
+		}
+
+		item, ok := finder.find(ctx, category, subcategories)
+		if !ok {
+			continue
+		}
+		if replace >= 0 {
+			kept[replace] = item
+		} else {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// protectiveFinder ищет защитную вещь категории: сначала в гардеробе пользователя
+// (если source его допускает), затем в каталоге. Гардероб читается один раз.
+type protectiveFinder struct {
+	service     *RecommendationService
+	userID      domain.ID
+	source      string
+	temperature float64
+
+	wardrobe       []domain.ClothingItem
+	wardrobeLoaded bool
+}
+
+func (f *protectiveFinder) find(ctx context.Context, category string, subcategories []string) (domain.ClothingItem, bool) {
+	s := f.service
+
+	if f.source != "catalog" {
+		if !f.wardrobeLoaded {
+			f.wardrobeLoaded = true
+			wardrobe, err := s.clothingItemRepo.GetByUserWardrobe(ctx, f.userID)
+			if err != nil {
+				s.logger.Warn("Failed to load wardrobe for weather alerts", zap.Int64("user_id", int64(f.userID)), zap.Error(err))
+			}
+			f.wardrobe = wardrobe
+		}
+		// Порядок подкатегорий — порядок предпочтения в правиле
+		for _, subcategory := range subcategories {
+			for _, item := range f.wardrobe {
+				if item.Category == category && item.Subcategory == subcategory {
+					return item, true
+				}
+			}
+		}
+	}
+
+	if f.source == "wardrobe" || s.catalog == nil {
+		return domain.ClothingItem{}, false
+	}
+	candidates, err := s.catalog.FindCandidatesByPlan(ctx, category, subcategories, 0, int16(math.Round(f.temperature)), 1)
+	if err != nil {
+		s.logger.Warn("Failed to find protective items", zap.String("category", category), zap.Error(err))
+		return domain.ClothingItem{}, false
+	}
+	if len(candidates) == 0 {
+		return domain.ClothingItem{}, false
+	}
+	return candidates[0], true
+}
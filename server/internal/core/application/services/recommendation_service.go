@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"strings"
 	"time"
 
@@ -21,8 +22,10 @@ type RecommendationService struct {
 	recommendationRepo repositories.RecommendationRepository
 	userRepo           repositories.UserRepository
 	clothingItemRepo   repositories.ClothingItemRepository
-	weatherService     *external.WeatherService
+	weatherService     external.WeatherProvider
 	mlService          *external.MLService
+	alerts             *WeatherAlertService
+	catalog            CatalogCandidateFinder
 	logger             *zap.Logger
 }
 
@@ -31,7 +34,7 @@ func NewRecommendationService(
 	recommendationRepo repositories.RecommendationRepository,
 	userRepo repositories.UserRepository,
 	clothingItemRepo repositories.ClothingItemRepository,
-	weatherService *external.WeatherService,
+	weatherService external.WeatherProvider,
 	mlService *external.MLService,
 	logger *zap.Logger,
 ) *RecommendationService {
@@ -56,6 +59,25 @@ func (s *RecommendationService) GetRecommendations(
 	req domain.RecommendationRequest,
 	source string,
 ) (*domain.RecommendationResponse, error) {
+	return s.recommend(ctx, req, source, domain.RecommendationOriginUser)
+}
+
+// GetDigestRecommendations — GetRecommendations для рекомендаций, которые сервер собирает сам
+// (утренний дайджест): они сохраняются с origin=digest и не считаются активностью пользователя.
+func (s *RecommendationService) GetDigestRecommendations(
+	ctx context.Context,
+	req domain.RecommendationRequest,
+	source string,
+) (*domain.RecommendationResponse, error) {
+	return s.recommend(ctx, req, source, domain.RecommendationOriginDigest)
+}
+
+func (s *RecommendationService) recommend(
+	ctx context.Context,
+	req domain.RecommendationRequest,
+	source string,
+	origin string,
+) (*domain.RecommendationResponse, error) {
 
 	if req.UserID <= 0 {
 		return nil, ErrUserNotFound
@@ -131,25 +153,67 @@ func (s *RecommendationService) GetRecommendations(
 	}
 
 	recommendation := mlRec
+	recommendation.Items = s.applyAlerts(ctx, req.UserID, req.WeatherData, recommendation.Items, source)
 
 	// 5. Сохраняем рекомендацию в БД (теперь все вещи из базы данных, без костылей)
 	// Так как теперь все вещи находятся в базе данных (wardrobe, catalog, kaggle_seed),
-	// можно сохранять все рекомендации без исключений
-	go func(rec *domain.RecommendationResponse, uid domain.ID) {
-		saveCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
-
-		if _, err := s.recommendationRepo.CreateRecommendation(saveCtx, rec); err != nil {
-			s.logger.Error("Failed to save recommendation",
-				zap.Int64("user_id", int64(uid)),
-				zap.Error(err),
-			)
-		}
-	}(recommendation, req.UserID)
+	// можно сохранять все рекомендации без исключений.
+	// Сохраняем до ответа: пользователь получает ID существующей записи и может её оценить.
+	// Событие recommendation.created коммитится той же транзакцией через outbox.
+	events := func(rec *domain.RecommendationResponse) ([]domain.OutboxEvent, error) {
+		return recommendationCreatedEvent(rec, origin)
+	}
+	if _, err := s.recommendationRepo.CreateRecommendation(ctx, recommendation, origin, events); err != nil {
+		s.logger.Error("Failed to save recommendation", zap.Int("user_id", userID), zap.Error(err))
+		return nil, errors.Wrap(err, "failed to save recommendation")
+	}
 
 	return recommendation, nil
 }
 
+// recommendationCreatedEvent строит событие recommendation.created для уже вставленной рекомендации.
+func recommendationCreatedEvent(rec *domain.RecommendationResponse, origin string) ([]domain.OutboxEvent, error) {
+	eventID, err := randomHex(16)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate event ID")
+	}
+
+	itemIDs := make([]int64, 0, len(rec.Items))
+	ownedItemIDs := make([]int64, 0, len(rec.Items))
+	styles := make([]string, 0, len(rec.Items))
+	for _, item := range rec.Items {
+		itemIDs = append(itemIDs, item.ID)
+		if item.IsOwned {
+			ownedItemIDs = append(ownedItemIDs, item.ID)
+		}
+		if item.Style != "" {
+			styles = append(styles, item.Style)
+		}
+	}
+	data, err := json.Marshal(map[string]interface{}{
+		"recommendation_id": rec.ID,
+		"location":          rec.Location,
+		"temperature":       rec.Temperature,
+		"weather":           rec.Weather,
+		"item_ids":          itemIDs,
+		"owned_item_ids":    ownedItemIDs,
+		"styles":            styles,
+		"algorithm":         rec.Algorithm,
+		"origin":            origin,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode event")
+	}
+
+	return []domain.OutboxEvent{{
+		EventID:   "evt_" + eventID,
+		UserID:    int(rec.UserID),
+		Type:      domain.EventRecommendationCreated,
+		Data:      data,
+		CreatedAt: rec.Timestamp,
+	}}, nil
+}
+
 // GetRecommendationHistory retrieves recommendation history for a user.
 func (s *RecommendationService) GetRecommendationHistory(
 	ctx context.Context,
@@ -188,7 +252,15 @@ func (s *RecommendationService) RateRecommendation(
 		return errors.New("rating must be between 1 and 5")
 	}
 
-	if err := s.userRepo.RateRecommendation(ctx, userID, recommendationID, rating, feedback); err != nil {
+	event, err := newOutboxEvent(userID, domain.EventRatingSubmitted, map[string]interface{}{
+		"recommendation_id": recommendationID,
+		"rating":            rating,
+		"feedback":          feedback,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.RateRecommendation(ctx, userID, recommendationID, rating, feedback, []domain.OutboxEvent{event}); err != nil {
 		return errors.Wrap(err, "failed to rate recommendation")
 	}
 	return nil
@@ -200,7 +272,13 @@ func (s *RecommendationService) AddFavorite(
 	userID, recommendationID int,
 ) error {
 
-	if err := s.userRepo.AddFavorite(ctx, userID, recommendationID); err != nil {
+	event, err := newOutboxEvent(userID, domain.EventFavoriteAdded, map[string]interface{}{
+		"recommendation_id": recommendationID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.AddFavorite(ctx, userID, recommendationID, []domain.OutboxEvent{event}); err != nil {
 		return errors.Wrap(err, "failed to add favorite")
 	}
 	return nil
@@ -212,7 +290,13 @@ func (s *RecommendationService) RemoveFavorite(
 	userID, favoriteID int,
 ) error {
 
-	if err := s.userRepo.RemoveFavorite(ctx, userID, favoriteID); err != nil {
+	event, err := newOutboxEvent(userID, domain.EventFavoriteRemoved, map[string]interface{}{
+		"favorite_id": favoriteID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.userRepo.RemoveFavorite(ctx, userID, favoriteID, []domain.OutboxEvent{event}); err != nil {
 		return errors.Wrap(err, "failed to remove favorite")
 	}
 	return nil
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// ErrSessionNotFound — у пользователя нет действующей сессии с таким ID.
+var ErrSessionNotFound = errors.New("session not found")
+
+const (
+	refreshTokenBytes   = 32
+	sessionDeviceMaxLen = 255 // auth_sessions.device
+	sessionIPMaxLen     = 64  // auth_sessions.ip
+)
+
+// SessionService выдаёт и меняет непрозрачные refresh-токены. Сессия — семейство токенов
+// одного входа: каждый refresh выдаёт новый токен, а повтор старого отзывает всю сессию.
+type SessionService struct {
+	sessionRepo repositories.SessionRepository
+	refreshTTL  time.Duration
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewSessionService creates a new session service; refreshTTL — срок жизни сессии без refresh.
+func NewSessionService(sessionRepo repositories.SessionRepository, refreshTTL time.Duration, logger *zap.Logger) *SessionService {
+	return &SessionService{
+		sessionRepo: sessionRepo,
+		refreshTTL:  refreshTTL,
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// Start открывает сессию и возвращает её первый refresh-токен.
+func (s *SessionService) Start(ctx context.Context, userID int, client domain.SessionClient) (*domain.Session, string, error) {
+	token, err := randomHex(refreshTokenBytes)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to generate refresh token")
+	}
+
+	now := s.now()
+	client = normalizeSessionClient(client)
+	session, err := s.sessionRepo.Create(ctx, domain.Session{
+		UserID:    userID,
+		Device:    client.Device,
+		IP:        client.IP,
+		CreatedAt: now,
+		ExpiresAt: now.Add(s.refreshTTL),
+	}, hashAuthToken(token))
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to start session")
+	}
+	return session, token, nil
+}
+
+// Rotate меняет refresh-токен на новый. Для неизвестного, истёкшего или отозванного токена
+// возвращает repositories.ErrRefreshTokenInvalid, для повторно предъявленного —
+// repositories.ErrRefreshTokenReused (сессия при этом уже отозвана).
+func (s *SessionService) Rotate(ctx context.Context, refreshToken string, client domain.SessionClient) (*domain.Session, string, error) {
+	token, err := randomHex(refreshTokenBytes)
+	if err != nil {
+		return nil, "", errors.Wrap(err, "failed to generate refresh token")
+	}
+
+	now := s.now()
+	session, err := s.sessionRepo.Rotate(ctx, hashAuthToken(refreshToken), hashAuthToken(token),
+		normalizeSessionClient(client), now, now.Add(s.refreshTTL))
+	if err != nil {
+		return nil, "", err
+	}
+	return session, token, nil
+}
+
+// RevokeByToken закрывает сессию, которой принадлежит refresh-токен (выход).
+func (s *SessionService) RevokeByToken(ctx context.Context, refreshToken string) error {
+	if err := s.sessionRepo.RevokeByToken(ctx, hashAuthToken(refreshToken)); err != nil {
+		return errors.Wrap(err, "failed to revoke session")
+	}
+	return nil
+}
+
+// ListSessions возвращает действующие сессии пользователя.
+func (s *SessionService) ListSessions(ctx context.Context, userID int) ([]domain.Session, error) {
+	sessions, err := s.sessionRepo.List(ctx, userID, s.now())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list sessions")
+	}
+	return sessions, nil
+}
+
+// RevokeSession закрывает сессию пользователя: её refresh-токен больше не примут.
+// Уже выданный access-токен действует до своего срока.
+func (s *SessionService) RevokeSession(ctx context.Context, userID int, sessionID int64) error {
+	revoked, err := s.sessionRepo.Revoke(ctx, userID, sessionID)
+	if err != nil {
+		return errors.Wrap(err, "failed to revoke session")
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	s.logger.Info("Session revoked", zap.Int("user_id", userID), zap.Int64("session_id", sessionID))
+	return nil
+}
+
+// DeleteExpired удаляет истёкшие и отозванные сессии.
+func (s *SessionService) DeleteExpired(ctx context.Context) (int64, error) {
+	return s.sessionRepo.DeleteExpired(ctx, s.now())
+}
+
+func normalizeSessionClient(client domain.SessionClient) domain.SessionClient {
+	return domain.SessionClient{
+		Device: truncate(client.Device, sessionDeviceMaxLen),
+		IP:     truncate(client.IP, sessionIPMaxLen),
+	}
+}
+
+// hashAuthToken — ключ кода или токена в хранилище.
+func hashAuthToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// StatsService ведёт статистику пользователей (user_stats). Как EventSink получает
+// события из outbox; повтор события счётчики не искажает.
+type StatsService struct {
+	statsRepo repositories.StatsRepository
+	logger    *zap.Logger
+}
+
+// NewStatsService creates a new user stats service.
+func NewStatsService(statsRepo repositories.StatsRepository, logger *zap.Logger) *StatsService {
+	return &StatsService{
+		statsRepo: statsRepo,
+		logger:    logger,
+	}
+}
+
+// GetUserStats возвращает статистику пользователя; nil, если её ещё нет.
+func (s *StatsService) GetUserStats(ctx context.Context, userID int) (*domain.ExtendedUserStats, error) {
+	stats, err := s.statsRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user stats")
+	}
+	return stats, nil
+}
+
+// PublishEvent обновляет статистику по событию.
+func (s *StatsService) PublishEvent(ctx context.Context, event domain.WebhookEvent) error {
+	switch event.Type {
+	case domain.EventRecommendationCreated:
+		var data struct {
+			RecommendationID int    `json:"recommendation_id"`
+			Origin           string `json:"origin"`
+		}
+		if err := decodeEventData(event.Data, &data); err != nil || data.RecommendationID == 0 {
+			// Повтор не поможет — пропускаем событие
+			s.logger.Warn("Skipping recommendation event without recommendation_id",
+				zap.String("event_id", event.ID), zap.Error(err))
+			return nil
+		}
+		if data.Origin == domain.RecommendationOriginDigest {
+			// Рекомендацию собрал сервер, а не пользователь
+			return nil
+		}
+		return s.statsRepo.ApplyRecommendation(ctx, data.RecommendationID)
+
+	case domain.EventRatingSubmitted,
+		domain.EventFavoriteAdded,
+		domain.EventFavoriteRemoved,
+		domain.EventAchievementUnlocked:
+		return s.statsRepo.RefreshCounts(ctx, event.UserID, event.CreatedAt)
+	}
+	return nil
+}
+
+// Rebuild пересчитывает статистику пользователя из исходных таблиц.
+func (s *StatsService) Rebuild(ctx context.Context, userID int) error {
+	if err := s.statsRepo.Rebuild(ctx, userID); err != nil {
+		return errors.Wrapf(err, "failed to rebuild stats of user %d", userID)
+	}
+	return nil
+}
+
+// RebuildAll пересчитывает статистику всех пользователей и возвращает число пересчитанных.
+// Ошибка одного пользователя не останавливает остальных.
+func (s *StatsService) RebuildAll(ctx context.Context) (int, error) {
+	userIDs, err := s.statsRepo.ListUserIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	rebuilt, failed := 0, 0
+	for _, id := range userIDs {
+		if err := ctx.Err(); err != nil {
+			return rebuilt, err
+		}
+		if err := s.Rebuild(ctx, id); err != nil {
+			s.logger.Error("Failed to rebuild user stats", zap.Int("user_id", id), zap.Error(err))
+			failed++
+			continue
+		}
+		rebuilt++
+	}
+	if failed > 0 {
+		return rebuilt, errors.Errorf("failed to rebuild stats of %d users", failed)
+	}
+	return rebuilt, nil
+}
+
+// decodeEventData разбирает Data события: из outbox приходит json.RawMessage,
+// при прямой публикации — произвольное значение.
+func decodeEventData(data interface{}, v interface{}) error {
+	raw, ok := data.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(data); err != nil {
+			return err
+		}
+	}
+	return json.Unmarshal(raw, v)
+}
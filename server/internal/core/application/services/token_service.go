@@ -8,23 +8,21 @@ import (
 	"outfitstyle/server/internal/core/domain"
 )
 
-// TokenService handles JWT token generation and validation
+// TokenService handles JWT access token generation and validation.
+// Refresh-токены непрозрачные, их выдаёт SessionService.
 type TokenService struct {
-	jwtSecret          []byte
-	accessTokenExpiry  time.Duration
-	refreshTokenExpiry time.Duration
+	jwtSecret         []byte
+	accessTokenExpiry time.Duration
 }
 
 // NewTokenService creates a new token service
 func NewTokenService(
 	jwtSecret string,
 	accessTokenExpiry time.Duration,
-	refreshTokenExpiry time.Duration,
 ) *TokenService {
 	return &TokenService{
-		jwtSecret:          []byte(jwtSecret),
-		accessTokenExpiry:  accessTokenExpiry,
-		refreshTokenExpiry: refreshTokenExpiry,
+		jwtSecret:         []byte(jwtSecret),
+		accessTokenExpiry: accessTokenExpiry,
 	}
 }
 
@@ -41,17 +39,6 @@ func (s *TokenService) GenerateAccessToken(user *domain.User) (string, error) {
 	return token.SignedString(s.jwtSecret)
 }
 
-// GenerateRefreshToken generates a new refresh token for a user
-func (s *TokenService) GenerateRefreshToken(user *domain.User) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": user.ID,
-		"exp":     time.Now().Add(s.refreshTokenExpiry).Unix(),
-	}
-
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(s.jwtSecret)
-}
-
 // ValidateAccessToken validates an access token and returns the user ID
 func (s *TokenService) ValidateAccessToken(tokenString string) (int, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
@@ -73,25 +60,3 @@ func (s *TokenService) ValidateAccessToken(tokenString string) (int, error) {
 
 	return 0, fmt.Errorf("invalid token")
 }
-
-// ValidateRefreshToken validates a refresh token and returns the user ID
-func (s *TokenService) ValidateRefreshToken(tokenString string) (int, error) {
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return s.jwtSecret, nil
-	})
-
-	if err != nil {
-		return 0, err
-	}
-
-	if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-		if userID, ok := claims["user_id"].(float64); ok {
-			return int(userID), nil
-		}
-	}
-
-	return 0, fmt.Errorf("invalid token")
-}
@@ -0,0 +1,276 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/pkg/totp"
+)
+
+var (
+	// ErrTwoFactorEnabled — 2FA уже включена; чтобы сменить секрет, её нужно выключить.
+	ErrTwoFactorEnabled = errors.New("two-factor authentication is already enabled")
+	// ErrTwoFactorNotEnabled — у пользователя нет включённой 2FA.
+	ErrTwoFactorNotEnabled = errors.New("two-factor authentication is not enabled")
+	// ErrTwoFactorNotEnrolled — подтверждение без начатого подключения.
+	ErrTwoFactorNotEnrolled = errors.New("two-factor enrolment is not started")
+	// ErrInvalidTwoFactorCode — код неверен, устарел или уже использован.
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	// ErrReauthenticationFailed — неверный пароль при выключении 2FA.
+	ErrReauthenticationFailed = errors.New("invalid password")
+)
+
+const (
+	twoFactorIssuer = "OutfitStyle"
+	// twoFactorSkew — сколько соседних шагов принимать из-за расхождения часов телефона.
+	twoFactorSkew     = 1
+	recoveryCodeCount = 10
+	recoveryCodeBytes = 5 // 10 hex-символов, показываются как xxxxx-xxxxx
+)
+
+// TwoFactorService ведёт TOTP-аутентификацию (RFC 6238): подключение, проверку кодов
+// и одноразовые коды восстановления. Коды восстановления хранятся только bcrypt-хэшами.
+type TwoFactorService struct {
+	twoFactorRepo repositories.TwoFactorRepository
+	userRepo      UserRepository
+	logger        *zap.Logger
+	now           func() time.Time
+}
+
+// NewTwoFactorService creates a new two-factor authentication service.
+func NewTwoFactorService(twoFactorRepo repositories.TwoFactorRepository, userRepo UserRepository, logger *zap.Logger) *TwoFactorService {
+	return &TwoFactorService{
+		twoFactorRepo: twoFactorRepo,
+		userRepo:      userRepo,
+		logger:        logger,
+		now:           time.Now,
+	}
+}
+
+// WithClock подменяет часы, например для проверки кодов с фиксированным временем.
+func (s *TwoFactorService) WithClock(now func() time.Time) *TwoFactorService {
+	s.now = now
+	return s
+}
+
+// Status возвращает состояние 2FA пользователя.
+func (s *TwoFactorService) Status(ctx context.Context, userID int) (*domain.TwoFactorStatus, error) {
+	t, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return &domain.TwoFactorStatus{}, nil
+	}
+	status := &domain.TwoFactorStatus{
+		Enabled:   t.Enabled(),
+		Pending:   !t.Enabled(),
+		EnabledAt: t.EnabledAt,
+	}
+	if t.Enabled() {
+		status.RecoveryCodesLeft = t.RecoveryCodesLeft
+	}
+	return status, nil
+}
+
+// IsEnabled сообщает, нужен ли пользователю код 2FA при входе.
+func (s *TwoFactorService) IsEnabled(ctx context.Context, userID int) (bool, error) {
+	t, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+	return t.Enabled(), nil
+}
+
+// Enroll начинает подключение: новый секрет и otpauth://-ссылка для приложения.
+// 2FA включится после Confirm; повторный Enroll заменяет неподтверждённый секрет.
+func (s *TwoFactorService) Enroll(ctx context.Context, userID int) (*domain.TwoFactorEnrollment, error) {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate two-factor secret")
+	}
+	saved, err := s.twoFactorRepo.SavePending(ctx, userID, secret)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	return &domain.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    totp.URI(twoFactorIssuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm включает 2FA по первому коду из приложения и возвращает коды восстановления —
+// они показываются один раз.
+func (s *TwoFactorService) Confirm(ctx context.Context, userID int, code string) ([]string, error) {
+	t, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, ErrTwoFactorNotEnrolled
+	}
+	if t.Enabled() {
+		return nil, ErrTwoFactorEnabled
+	}
+
+	now := s.now()
+	step, ok := totp.Match(t.Secret, code, now, twoFactorSkew)
+	if !ok {
+		return nil, ErrInvalidTwoFactorCode
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	enabled, err := s.twoFactorRepo.Enable(ctx, userID, step, hashes, now)
+	if err != nil {
+		return nil, err
+	}
+	if !enabled {
+		// Параллельно подтвердили или выключили
+		return nil, ErrTwoFactorNotEnrolled
+	}
+
+	s.logger.Info("🔐 Two-factor authentication enabled", zap.Int("user_id", userID))
+	return codes, nil
+}
+
+// Verify проверяет код из приложения или код восстановления. Каждый код принимается один раз.
+func (s *TwoFactorService) Verify(ctx context.Context, userID int, code string) error {
+	t, err := s.twoFactorRepo.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !t.Enabled() {
+		return ErrTwoFactorNotEnabled
+	}
+
+	now := s.now()
+	if step, ok := totp.Match(t.Secret, code, now, twoFactorSkew); ok {
+		accepted, err := s.twoFactorRepo.UseStep(ctx, userID, step)
+		if err != nil {
+			return err
+		}
+		if !accepted {
+			return ErrInvalidTwoFactorCode
+		}
+		return nil
+	}
+
+	normalized := normalizeRecoveryCode(code)
+	if len(normalized) != recoveryCodeBytes*2 {
+		return ErrInvalidTwoFactorCode
+	}
+	hash, err := s.matchRecoveryCode(ctx, userID, normalized)
+	if err != nil {
+		return err
+	}
+	if hash == "" {
+		return ErrInvalidTwoFactorCode
+	}
+	used, err := s.twoFactorRepo.UseRecoveryCode(ctx, userID, hash, now)
+	if err != nil {
+		return err
+	}
+	if !used {
+		// Тот же код параллельно погасил другой запрос
+		return ErrInvalidTwoFactorCode
+	}
+	s.logger.Info("Recovery code used",
+		zap.Int("user_id", userID),
+		zap.Int("recovery_codes_left", max(t.RecoveryCodesLeft-1, 0)))
+	return nil
+}
+
+// Disable выключает 2FA после повторной проверки: пароль (если он задан — у входа через
+// Google его нет) и код из приложения или код восстановления.
+func (s *TwoFactorService) Disable(ctx context.Context, userID int, password, code string) error {
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user.Password != "" {
+		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+			return ErrReauthenticationFailed
+		}
+	}
+
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return err
+	}
+	if err := s.twoFactorRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	s.logger.Info("Two-factor authentication disabled", zap.Int("user_id", userID))
+	return nil
+}
+
+// matchRecoveryCode ищет среди неиспользованных кодов тот, чей хэш подходит к code.
+// bcrypt солит каждый хэш, поэтому код сравнивается с каждой записью; "" — не найден.
+func (s *TwoFactorService) matchRecoveryCode(ctx context.Context, userID int, code string) (string, error) {
+	hashes, err := s.twoFactorRepo.RecoveryCodeHashes(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	for _, h := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(h), []byte(code)) == nil {
+			return h, nil
+		}
+	}
+	return "", nil
+}
+
+func (s *TwoFactorService) getUser(ctx context.Context, userID int) (*domain.User, error) {
+	user, err := s.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get user")
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// generateRecoveryCodes возвращает коды для показа и их хэши для хранения.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	for len(codes) < recoveryCodeCount {
+		raw, err := randomHex(recoveryCodeBytes)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to generate recovery code")
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(raw), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to hash recovery code")
+		}
+		codes = append(codes, fmt.Sprintf("%s-%s", raw[:recoveryCodeBytes], raw[recoveryCodeBytes:]))
+		hashes = append(hashes, string(hash))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode допускает код в любом регистре, с дефисом и пробелами.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
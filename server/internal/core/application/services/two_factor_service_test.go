@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/infrastructure/persistence/memory"
+	"outfitstyle/server/internal/pkg/totp"
+)
+
+func TestTwoFactorVerifyRejectsReplayedStep(t *testing.T) {
+	ctx := context.Background()
+	const userID = 1
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memory.NewTwoFactorRepository()
+	if _, err := repo.SavePending(ctx, userID, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	svc := NewTwoFactorService(repo, nil, zap.NewNop()).WithClock(func() time.Time { return now })
+	code := func(offset int64) string {
+		c, err := totp.Code(secret, totp.Step(now)+offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	if _, err := svc.Confirm(ctx, userID, code(0)); err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+	// Код, которым подтвердили подключение, уже использован
+	if err := svc.Verify(ctx, userID, code(0)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("Verify(confirmed step) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+
+	now = now.Add(totp.Period)
+	if err := svc.Verify(ctx, userID, code(0)); err != nil {
+		t.Fatalf("Verify(next step) = %v", err)
+	}
+	if err := svc.Verify(ctx, userID, code(0)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("Verify(replayed step) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	// Предыдущий шаг ещё в окне расхождения часов, но старше принятого
+	if err := svc.Verify(ctx, userID, code(-1)); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("Verify(older step) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+	if err := svc.Verify(ctx, userID, code(1)); err != nil {
+		t.Fatalf("Verify(step ahead within skew) = %v", err)
+	}
+}
+
+func TestTwoFactorRecoveryCodeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	const userID = 1
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := memory.NewTwoFactorRepository()
+	if _, err := repo.SavePending(ctx, userID, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+	svc := NewTwoFactorService(repo, nil, zap.NewNop()).WithClock(func() time.Time { return now })
+	first, err := totp.Code(secret, totp.Step(now))
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, err := svc.Confirm(ctx, userID, first)
+	if err != nil {
+		t.Fatalf("Confirm: %v", err)
+	}
+
+	if err := svc.Verify(ctx, userID, codes[0]); err != nil {
+		t.Fatalf("Verify(recovery code) = %v", err)
+	}
+	if err := svc.Verify(ctx, userID, codes[0]); !errors.Is(err, ErrInvalidTwoFactorCode) {
+		t.Fatalf("Verify(used recovery code) = %v, want ErrInvalidTwoFactorCode", err)
+	}
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"time"
+	_ "time/tzdata" // часовые пояса мест проверяются и в образах без системной tzdata
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/pkg/gazetteer"
+)
+
+// MaxUserLocations — сколько мест может сохранить один пользователь.
+const MaxUserLocations = 20
+
+var (
+	// ErrLocationNotFound возвращается, если у пользователя нет места с таким id.
+	ErrLocationNotFound = errors.New("location not found")
+	// ErrInvalidLocation — ошибка валидации места; текст причины в обёртке.
+	ErrInvalidLocation = errors.New("invalid location")
+)
+
+// defaultLocationNames — имена дома и работы, если пользователь не задал своё.
+var defaultLocationNames = map[string]string{
+	domain.LocationKindHome: "Дом",
+	domain.LocationKindWork: "Работа",
+}
+
+// UserLocationInput — место из запроса: город из справочника (location_id) или координаты с часовым поясом.
+type UserLocationInput struct {
+	Kind       string   `json:"kind"`
+	Name       string   `json:"name"`
+	LocationID string   `json:"location_id"`
+	Lat        *float64 `json:"lat"`
+	Lon        *float64 `json:"lon"`
+	Timezone   string   `json:"timezone"`
+	IsDefault  bool     `json:"is_default"`
+}
+
+// UserLocationService manages users' saved locations.
+type UserLocationService struct {
+	locationRepo repositories.UserLocationRepository
+	places       *gazetteer.Gazetteer
+	logger       *zap.Logger
+}
+
+// NewUserLocationService creates a new saved locations service
+func NewUserLocationService(
+	locationRepo repositories.UserLocationRepository,
+	places *gazetteer.Gazetteer,
+	logger *zap.Logger,
+) *UserLocationService {
+	return &UserLocationService{
+		locationRepo: locationRepo,
+		places:       places,
+		logger:       logger,
+	}
+}
+
+// List returns the user's saved locations, the default one first.
+func (s *UserLocationService) List(ctx context.Context, userID int) ([]domain.UserLocation, error) {
+	return s.locationRepo.List(ctx, userID)
+}
+
+// Get returns one saved location or ErrLocationNotFound.
+func (s *UserLocationService) Get(ctx context.Context, userID, id int) (*domain.UserLocation, error) {
+	loc, err := s.locationRepo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, ErrLocationNotFound
+	}
+	return loc, nil
+}
+
+// GetDefault returns the user's default location or nil if none is saved.
+// Используется рекомендациями без города и фоновыми задачами.
+func (s *UserLocationService) GetDefault(ctx context.Context, userID int) (*domain.UserLocation, error) {
+	return s.locationRepo.GetDefault(ctx, userID)
+}
+
+// Create saves a new location. Первое сохранённое место становится местом по умолчанию.
+func (s *UserLocationService) Create(ctx context.Context, userID int, in UserLocationInput) (*domain.UserLocation, error) {
+	loc := &domain.UserLocation{UserID: userID}
+	if err := s.apply(loc, in); err != nil {
+		return nil, err
+	}
+	loc.IsDefault = in.IsDefault
+
+	if err := s.locationRepo.Create(ctx, loc, MaxUserLocations); err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// Update replaces a saved location. Снять отметку «по умолчанию» можно только выбрав другое место.
+func (s *UserLocationService) Update(ctx context.Context, userID, id int, in UserLocationInput) (*domain.UserLocation, error) {
+	loc, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	wasDefault := loc.IsDefault
+	if err := s.apply(loc, in); err != nil {
+		return nil, err
+	}
+	loc.IsDefault = in.IsDefault || wasDefault
+
+	if err := s.locationRepo.Update(ctx, loc); err != nil {
+		return nil, err
+	}
+	return loc, nil
+}
+
+// Delete removes a saved location; if it was the default, the oldest remaining one becomes default.
+func (s *UserLocationService) Delete(ctx context.Context, userID, id int) error {
+	deleted, err := s.locationRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if deleted == nil {
+		return ErrLocationNotFound
+	}
+	return nil
+}
+
+// SetDefault makes the location the user's default.
+func (s *UserLocationService) SetDefault(ctx context.Context, userID, id int) (*domain.UserLocation, error) {
+	loc, err := s.locationRepo.SetDefault(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, ErrLocationNotFound
+	}
+	return loc, nil
+}
+
+// apply проверяет ввод и переносит его в место. Для города из справочника координаты,
+// часовой пояс и название берутся из справочника; явно переданное название важнее.
+func (s *UserLocationService) apply(loc *domain.UserLocation, in UserLocationInput) error {
+	kind := strings.TrimSpace(in.Kind)
+	if kind == "" {
+		kind = domain.LocationKindCustom
+	}
+	if kind != domain.LocationKindHome && kind != domain.LocationKindWork && kind != domain.LocationKindCustom {
+		return errors.Wrapf(ErrInvalidLocation, "kind must be one of home, work, custom")
+	}
+
+	name := strings.TrimSpace(in.Name)
+	if len([]rune(name)) > 100 {
+		return errors.Wrapf(ErrInvalidLocation, "name must be at most 100 characters")
+	}
+
+	var (
+		coords   domain.Coordinates
+		timezone = strings.TrimSpace(in.Timezone)
+		placeID  = strings.TrimSpace(in.LocationID)
+	)
+	switch {
+	case placeID != "":
+		if s.places == nil {
+			return errors.Wrapf(ErrInvalidLocation, "location_id is not supported")
+		}
+		place, ok := s.places.Get(placeID)
+		if !ok {
+			return errors.Wrapf(ErrInvalidLocation, "unknown location_id %q", placeID)
+		}
+		coords = domain.Coordinates{Lat: place.Lat, Lon: place.Lon}
+		timezone = place.Timezone
+		if name == "" && kind == domain.LocationKindCustom {
+			name = place.DisplayName()
+		}
+	case in.Lat != nil && in.Lon != nil:
+		coords = domain.Coordinates{Lat: *in.Lat, Lon: *in.Lon}
+		if err := coords.Validate(); err != nil {
+			return errors.Wrap(ErrInvalidLocation, err.Error())
+		}
+		if timezone == "" {
+			return errors.Wrapf(ErrInvalidLocation, "timezone is required with lat/lon")
+		}
+	default:
+		return errors.Wrapf(ErrInvalidLocation, "location_id or lat/lon is required")
+	}
+
+	if _, err := time.LoadLocation(timezone); err != nil || timezone == "Local" {
+		return errors.Wrapf(ErrInvalidLocation, "unknown timezone %q", timezone)
+	}
+
+	if name == "" {
+		name = defaultLocationNames[kind]
+	}
+	if name == "" {
+		return errors.Wrapf(ErrInvalidLocation, "name is required for custom locations")
+	}
+
+	loc.Kind = kind
+	loc.Name = name
+	loc.LocationID = placeID
+	loc.Coordinates = coords
+	loc.Timezone = timezone
+	return nil
+}
@@ -2,9 +2,11 @@ package services
 
 import (
 	"context"
+	"strings"
 
 	"go.uber.org/zap"
 
+	"outfitstyle/server/internal/core/application/planner"
 	"outfitstyle/server/internal/core/application/repositories"
 	"outfitstyle/server/internal/core/domain"
 )
@@ -12,6 +14,8 @@ import (
 // UserService handles user-related business logic
 type UserService struct {
 	userRepo repositories.UserRepository
+	events   EventPublisher
+	planner  *planner.OutfitPlanner
 	logger   *zap.Logger
 }
 
@@ -41,14 +45,31 @@ func (s *UserService) GetUserAchievements(ctx context.Context, userID int) ([]do
 	return s.userRepo.GetUserAchievements(ctx, userID)
 }
 
+// WithEvents подключает публикацию события achievement.unlocked.
+func (s *UserService) WithEvents(events EventPublisher) *UserService {
+	s.events = events
+	return s
+}
+
 // UnlockAchievement unlocks an achievement for a user
 func (s *UserService) UnlockAchievement(ctx context.Context, userID int, achievementCode string) error {
-	return s.userRepo.UnlockAchievement(ctx, userID, achievementCode)
+	unlocked, err := s.userRepo.UnlockAchievement(ctx, userID, achievementCode)
+	if err != nil {
+		return err
+	}
+
+	// Повторная разблокировка ничего не меняет и события не порождает
+	if unlocked && s.events != nil {
+		s.events.Publish(ctx, userID, domain.EventAchievementUnlocked, map[string]interface{}{
+			"achievement_code": achievementCode,
+		})
+	}
+	return nil
 }
 
 // RateRecommendation saves a user's rating for a recommendation
 func (s *UserService) RateRecommendation(ctx context.Context, userID, recommendationID, rating int, feedback string) error {
-	return s.userRepo.RateRecommendation(ctx, userID, recommendationID, rating, feedback)
+	return s.userRepo.RateRecommendation(ctx, userID, recommendationID, rating, feedback, nil)
 }
 
 // GetUserRatings retrieves user's ratings
@@ -58,12 +79,12 @@ func (s *UserService) GetUserRatings(ctx context.Context, userID int) ([]domain.
 
 // AddFavorite adds a recommendation to user's favorites
 func (s *UserService) AddFavorite(ctx context.Context, userID, recommendationID int) error {
-	return s.userRepo.AddFavorite(ctx, userID, recommendationID)
+	return s.userRepo.AddFavorite(ctx, userID, recommendationID, nil)
 }
 
 // RemoveFavorite removes a recommendation from user's favorites
 func (s *UserService) RemoveFavorite(ctx context.Context, userID, favoriteID int) error {
-	return s.userRepo.RemoveFavorite(ctx, userID, favoriteID)
+	return s.userRepo.RemoveFavorite(ctx, userID, favoriteID, nil)
 }
 
 // GetUserFavorites retrieves user's favorite recommendations
@@ -71,9 +92,30 @@ func (s *UserService) GetUserFavorites(ctx context.Context, userID int) ([]domai
 	return s.userRepo.GetUserFavorites(ctx, userID)
 }
 
-// CreateOutfitPlan creates a new outfit plan
-func (s *UserService) CreateOutfitPlan(ctx context.Context, plan *domain.OutfitPlan) error {
-	return s.userRepo.CreateOutfitPlan(ctx, plan)
+// WithPlanner подключает планировщик, по которому CreateOutfitPlan подбирает подкатегории
+// на дату плана (planner.OutfitPlanner.PlanForDate).
+func (s *UserService) WithPlanner(outfitPlanner *planner.OutfitPlanner) *UserService {
+	s.planner = outfitPlanner
+	return s
+}
+
+// CreateOutfitPlan creates a new outfit plan. Если передано место и подключён планировщик,
+// сначала строит план подкатегорий на дату по прогнозу или климатической норме и возвращает его;
+// planner.ErrDateInPast и planner.ErrNoWeather возвращаются без сохранения плана.
+func (s *UserService) CreateOutfitPlan(ctx context.Context, plan *domain.OutfitPlan, location string) (*planner.OutfitPlan, error) {
+	var weatherPlan *planner.OutfitPlan
+	if location = strings.TrimSpace(location); location != "" && s.planner != nil {
+		p, err := s.planner.PlanForDate(ctx, location, plan.Date, nil)
+		if err != nil {
+			return nil, err
+		}
+		weatherPlan = p
+	}
+
+	if err := s.userRepo.CreateOutfitPlan(ctx, plan); err != nil {
+		return nil, err
+	}
+	return weatherPlan, nil
 }
 
 // GetUserOutfitPlans retrieves user's outfit plans
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/external"
+	"outfitstyle/server/internal/pkg/gazetteer"
+)
+
+// alertTargetsPage — сколько пользователей проверяется за один запрос к БД.
+const alertTargetsPage = 100
+
+// WeatherAlertService evaluates severe-weather rules and notifies users about triggered alerts.
+type WeatherAlertService struct {
+	alertRepo    repositories.WeatherAlertRepository
+	weather      external.WeatherProvider
+	emailService EmailService
+	places       *gazetteer.Gazetteer
+	rules        alerts.Rules
+	logger       *zap.Logger
+	now          func() time.Time
+}
+
+// NewWeatherAlertService creates a new severe-weather alert service
+func NewWeatherAlertService(
+	alertRepo repositories.WeatherAlertRepository,
+	weather external.WeatherProvider,
+	emailService EmailService,
+	places *gazetteer.Gazetteer,
+	rules alerts.Rules,
+	logger *zap.Logger,
+) *WeatherAlertService {
+	return &WeatherAlertService{
+		alertRepo:    alertRepo,
+		weather:      weather,
+		emailService: emailService,
+		places:       places,
+		rules:        rules,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// WeatherConditions сводит погоду провайдера к условиям правил: прогноз на день даёт
+// минимум и максимум, текущая погода — ощущаемую температуру, ветер и описание.
+func WeatherConditions(w domain.WeatherData) alerts.Conditions {
+	return alerts.Conditions{
+		Temperature: w.Temperature,
+		FeelsLike:   w.FeelsLike,
+		MinTemp:     w.MinTemp,
+		MaxTemp:     w.MaxTemp,
+		WindSpeed:   w.WindSpeed,
+		Weather:     w.Weather,
+	}
+}
+
+// Evaluate returns alerts triggered by the weather.
+func (s *WeatherAlertService) Evaluate(w domain.WeatherData) []alerts.Alert {
+	return s.rules.Evaluate(WeatherConditions(w))
+}
+
+// Run проверяет всех пользователей сразу и затем каждые interval, пока ctx не отменён.
+func (s *WeatherAlertService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		sent, err := s.NotifyAll(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Weather alert check failed", zap.Error(err))
+		} else if sent > 0 {
+			s.logger.Info("Weather alerts sent", zap.Int("emails", sent))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// NotifyAll checks the forecast for every user with a location and emails new alerts.
+// О каждом виде предупреждения пользователь узнаёт не чаще раза в день (по местному времени места).
+// Возвращает число отправленных писем.
+func (s *WeatherAlertService) NotifyAll(ctx context.Context) (int, error) {
+	sent := 0
+	after := 0
+	for {
+		targets, err := s.alertRepo.ListTargets(ctx, after, alertTargetsPage)
+		if err != nil {
+			return sent, err
+		}
+
+		for _, t := range targets {
+			if ctx.Err() != nil {
+				return sent, ctx.Err()
+			}
+			ok, err := s.notify(ctx, t)
+			if err != nil {
+				// Один недоступный город или ошибка почты не должны останавливать рассылку остальным
+				s.logger.Warn("Failed to check weather alerts for user",
+					zap.Int("user_id", t.UserID),
+					zap.Error(err))
+				continue
+			}
+			if ok {
+				sent++
+			}
+		}
+
+		if len(targets) < alertTargetsPage {
+			return sent, nil
+		}
+		after = targets[len(targets)-1].UserID
+	}
+}
+
+// notify проверяет прогноз для одного пользователя; true — письмо отправлено.
+func (s *WeatherAlertService) notify(ctx context.Context, t domain.NotificationTarget) (bool, error) {
+	weather, name, loc, err := targetWeather(ctx, s.weather, s.places, t)
+	if err != nil {
+		if errors.Is(err, external.ErrCityNotFound) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to get weather")
+	}
+
+	active := s.Evaluate(weather.WeatherData)
+	if len(active) == 0 {
+		return false, nil
+	}
+
+	// Отметка ставится до отправки: при сбое почты письмо не повторится,
+	// зато несколько экземпляров сервера не пришлют его дважды
+	day := s.now().In(loc)
+	var fresh []alerts.Alert
+	for _, a := range active {
+		isNew, err := s.alertRepo.MarkNotified(ctx, t.UserID, a.Kind, day)
+		if err != nil {
+			return false, err
+		}
+		if isNew {
+			fresh = append(fresh, a)
+		}
+	}
+	if len(fresh) == 0 {
+		return false, nil
+	}
+
+	if err := s.emailService.SendWeatherAlertEmail(t.Email, name, fresh); err != nil {
+		return false, errors.Wrap(err, "failed to send weather alert email")
+	}
+	return true, nil
+}
@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Заголовки запроса к вебхуку.
+const (
+	WebhookEventHeader     = "X-OutfitStyle-Event"
+	WebhookEventIDHeader   = "X-OutfitStyle-Event-Id"
+	WebhookSignatureHeader = "X-OutfitStyle-Signature"
+)
+
+// errPrivateAddress — адрес вебхука ведёт во внутреннюю сеть.
+var errPrivateAddress = errors.New("webhook address is not publicly routable")
+
+// SignWebhookPayload возвращает значение заголовка X-OutfitStyle-Signature: "t=<unix>,v1=<hex>",
+// где v1 — HMAC-SHA256 секрета от строки "<unix>.<тело>". Метка времени в подписи
+// позволяет получателю отбрасывать старые перехваченные запросы.
+func SignWebhookPayload(secret string, payload []byte, at time.Time) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return fmt.Sprintf("t=%s,v1=%s", ts, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// newWebhookHTTPClient — клиент для доставки вебхуков. Перенаправления не выполняются
+// (3xx считается неудачей), а без allowPrivate соединения с loopback, частными и
+// link-local адресами запрещены: адрес вебхука задаёт пользователь, и сервер не должен
+// ходить по нему во внутреннюю сеть. Проверка идёт при соединении, после разрешения DNS.
+func newWebhookHTTPClient(timeout time.Duration, allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublicIP(ip) {
+				return errPrivateAddress
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		ip.IsMulticast())
+}
@@ -0,0 +1,507 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	mathrand "math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+const (
+	// MaxUserWebhooks — сколько вебхуков может завести один пользователь.
+	MaxUserWebhooks = 10
+	// webhookBatch — сколько доставок забирается из очереди за раз.
+	webhookBatch = 50
+	// webhookWorkers — сколько доставок отправляется параллельно.
+	webhookWorkers = 8
+	// webhookMaxResponse — сколько байт ответа вебхука читается (остальное не нужно).
+	webhookMaxResponse = 64 << 10
+	// webhookMaxError — до скольких символов обрезается ошибка в журнале.
+	webhookMaxError = 500
+)
+
+var (
+	// ErrWebhookNotFound возвращается, если у пользователя нет вебхука (или доставки) с таким id.
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrTooManyWebhooks — достигнут MaxUserWebhooks.
+	ErrTooManyWebhooks = errors.New("too many webhooks")
+	// ErrInvalidWebhook — ошибка валидации вебхука; текст причины в обёртке.
+	ErrInvalidWebhook = errors.New("invalid webhook")
+)
+
+// EventPublisher публикует доменные события. Публикация не должна мешать самой операции:
+// ошибки логируются, а не возвращаются.
+type EventPublisher interface {
+	Publish(ctx context.Context, userID int, event string, data interface{})
+}
+
+// WebhookConfig — параметры доставки вебхуков.
+type WebhookConfig struct {
+	MaxAttempts int           // после стольких неудачных попыток доставка становится dead
+	Timeout     time.Duration // на одну попытку
+	BaseBackoff time.Duration // пауза после первой неудачи, дальше удваивается
+	MaxBackoff  time.Duration
+	// AllowPrivate разрешает http:// и адреса во внутренней сети — для локальной разработки
+	AllowPrivate bool
+}
+
+// WebhookInput — вебхук из запроса. Active по умолчанию true.
+type WebhookInput struct {
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Events      []string `json:"events"`
+	Active      *bool    `json:"active"`
+}
+
+// WebhookService manages webhook subscriptions and delivers domain events to them.
+type WebhookService struct {
+	webhookRepo repositories.WebhookRepository
+	config      WebhookConfig
+	client      *http.Client
+	logger      *zap.Logger
+	now         func() time.Time
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	webhookRepo repositories.WebhookRepository,
+	config WebhookConfig,
+	logger *zap.Logger,
+) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		config:      config,
+		client:      newWebhookHTTPClient(config.Timeout, config.AllowPrivate),
+		logger:      logger,
+		now:         time.Now,
+	}
+}
+
+// List returns the user's webhooks without secrets.
+func (s *WebhookService) List(ctx context.Context, userID int) ([]domain.Webhook, error) {
+	hooks, err := s.webhookRepo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range hooks {
+		hooks[i].Secret = ""
+	}
+	return hooks, nil
+}
+
+// Get returns a webhook without its secret or ErrWebhookNotFound.
+func (s *WebhookService) Get(ctx context.Context, userID, id int) (*domain.Webhook, error) {
+	hook, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+func (s *WebhookService) get(ctx context.Context, userID, id int) (*domain.Webhook, error) {
+	hook, err := s.webhookRepo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if hook == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return hook, nil
+}
+
+// Create saves a new webhook with a generated secret. Секрет возвращается только здесь и в RotateSecret.
+func (s *WebhookService) Create(ctx context.Context, userID int, in WebhookInput) (*domain.Webhook, error) {
+	count, err := s.webhookRepo.Count(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= MaxUserWebhooks {
+		return nil, ErrTooManyWebhooks
+	}
+
+	hook := &domain.Webhook{UserID: userID, Active: true}
+	if err := s.apply(hook, in); err != nil {
+		return nil, err
+	}
+	if hook.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+
+	if err := s.webhookRepo.Create(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Update replaces the webhook's URL, description, events and active flag; the secret is kept.
+func (s *WebhookService) Update(ctx context.Context, userID, id int, in WebhookInput) (*domain.Webhook, error) {
+	hook, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.apply(hook, in); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	hook.Secret = ""
+	return hook, nil
+}
+
+// RotateSecret заменяет секрет; доставки, которые ещё в очереди, подписываются новым.
+func (s *WebhookService) RotateSecret(ctx context.Context, userID, id int) (*domain.Webhook, error) {
+	hook, err := s.get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if hook.Secret, err = newWebhookSecret(); err != nil {
+		return nil, err
+	}
+	if err := s.webhookRepo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Delete removes the webhook together with its delivery log.
+func (s *WebhookService) Delete(ctx context.Context, userID, id int) error {
+	deleted, err := s.webhookRepo.Delete(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrWebhookNotFound
+	}
+	return nil
+}
+
+// ListDeliveries returns the webhook's delivery log, newest first.
+func (s *WebhookService) ListDeliveries(ctx context.Context, userID, webhookID int, status string, limit, offset int) ([]domain.WebhookDelivery, error) {
+	switch status {
+	case "", domain.WebhookDeliveryPending, domain.WebhookDeliveryDelivered, domain.WebhookDeliveryDead:
+	default:
+		return nil, errors.Wrapf(ErrInvalidWebhook, "status must be one of pending, delivered, dead")
+	}
+	if _, err := s.get(ctx, userID, webhookID); err != nil {
+		return nil, err
+	}
+	return s.webhookRepo.ListDeliveries(ctx, userID, webhookID, status, limit, offset)
+}
+
+// Redeliver ставит доставку (обычно dead) в очередь заново с полным числом попыток.
+func (s *WebhookService) Redeliver(ctx context.Context, userID, webhookID int, id int64) (*domain.WebhookDelivery, error) {
+	d, err := s.webhookRepo.Redeliver(ctx, userID, webhookID, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return d, nil
+}
+
+// Publish ставит событие в очередь доставки всем подписанным вебхукам пользователя.
+// Отправляет их Run, поэтому Publish не ждёт получателей.
+func (s *WebhookService) Publish(ctx context.Context, userID int, event string, data interface{}) {
+	log := s.logger.With(zap.Int("user_id", userID), zap.String("event", event))
+
+	eventID, err := randomHex(16)
+	if err != nil {
+		log.Warn("Failed to generate webhook event ID", zap.Error(err))
+		return
+	}
+	err = s.PublishEvent(ctx, domain.WebhookEvent{
+		ID:        "evt_" + eventID,
+		Type:      event,
+		CreatedAt: s.now().UTC(),
+		UserID:    userID,
+		Data:      data,
+	})
+	if err != nil {
+		log.Warn("Failed to publish webhook event", zap.Error(err))
+	}
+}
+
+// PublishEvent ставит в очередь доставки готовое событие. Повторный вызов с тем же
+// event.ID не создаёт новых доставок, поэтому его можно безопасно повторять.
+func (s *WebhookService) PublishEvent(ctx context.Context, event domain.WebhookEvent) error {
+	hooks, err := s.webhookRepo.ListSubscribed(ctx, event.UserID, event.Type)
+	if err != nil {
+		return errors.Wrap(err, "failed to find webhooks for event")
+	}
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode webhook event")
+	}
+
+	deliveries := make([]domain.WebhookDelivery, 0, len(hooks))
+	for _, h := range hooks {
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			WebhookID: h.ID,
+			EventID:   event.ID,
+			Event:     event.Type,
+			Payload:   payload,
+		})
+	}
+	return s.webhookRepo.Enqueue(ctx, deliveries)
+}
+
+// Run отправляет наступившие доставки сразу и затем каждые interval, пока ctx не отменён.
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		delivered, err := s.DispatchDue(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.Error("Webhook dispatch failed", zap.Error(err))
+		} else if delivered > 0 {
+			s.logger.Info("Webhooks delivered", zap.Int("deliveries", delivered))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue отправляет наступившие доставки, пока очередь не опустеет, и возвращает
+// число успешных. Забранные доставки откладываются на время попытки: если сервер упадёт
+// посреди отправки, их доставит следующий запуск (получатель отбросит повтор по id события).
+func (s *WebhookService) DispatchDue(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		now := s.now()
+		lease := now.Add(2*s.config.Timeout + time.Minute)
+		batch, err := s.webhookRepo.ClaimDue(ctx, now, lease, webhookBatch)
+		if err != nil {
+			return delivered, err
+		}
+
+		var (
+			wg  sync.WaitGroup
+			mu  sync.Mutex
+			sem = make(chan struct{}, webhookWorkers)
+		)
+		for i := range batch {
+			d := &batch[i]
+			wg.Add(1)
+			sem <- struct{}{}
+			go func() {
+				defer wg.Done()
+				defer func() { <-sem }()
+				if s.attempt(ctx, d) {
+					mu.Lock()
+					delivered++
+					mu.Unlock()
+				}
+			}()
+		}
+		wg.Wait()
+
+		if ctx.Err() != nil {
+			return delivered, ctx.Err()
+		}
+		if len(batch) < webhookBatch {
+			return delivered, nil
+		}
+	}
+}
+
+// attempt отправляет доставку один раз и записывает итог; true — получатель ответил 2xx.
+func (s *WebhookService) attempt(ctx context.Context, d *domain.WebhookDelivery) bool {
+	statusCode, sendErr := s.send(ctx, d)
+	if sendErr != nil && ctx.Err() != nil {
+		// Сервер останавливается: попытка не засчитывается, доставку повторит следующий запуск
+		return false
+	}
+
+	// Аренда из ClaimDue: по ней репозиторий отличает нашу попытку от повторной у другого экземпляра
+	var lease time.Time
+	if d.NextAttemptAt != nil {
+		lease = *d.NextAttemptAt
+	}
+
+	now := s.now()
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+
+	switch {
+	case sendErr == nil:
+		d.Status = domain.WebhookDeliveryDelivered
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+	case d.Attempts >= s.config.MaxAttempts:
+		d.Status = domain.WebhookDeliveryDead
+		d.NextAttemptAt = nil
+		d.LastError = truncate(sendErr.Error(), webhookMaxError)
+	default:
+		next := now.Add(s.backoff(d.Attempts))
+		d.Status = domain.WebhookDeliveryPending
+		d.NextAttemptAt = &next
+		d.LastError = truncate(sendErr.Error(), webhookMaxError)
+	}
+
+	// Итог записывается и при остановке сервера: иначе успешная доставка повторится
+	recordCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	err := s.webhookRepo.RecordAttempt(recordCtx, d, lease)
+	if errors.Is(err, repositories.ErrWebhookLeaseLost) {
+		// Доставку уже отправляет другой экземпляр — его итог главнее
+		s.logger.Warn("Webhook delivery lease expired before the attempt was recorded", zap.Int64("delivery_id", d.ID))
+		return sendErr == nil
+	}
+	if err != nil {
+		s.logger.Warn("Failed to record webhook attempt", zap.Int64("delivery_id", d.ID), zap.Error(err))
+	}
+
+	if d.Status == domain.WebhookDeliveryDead {
+		s.logger.Warn("Webhook delivery dead-lettered",
+			zap.Int64("delivery_id", d.ID),
+			zap.Int("webhook_id", d.WebhookID),
+			zap.String("event", d.Event),
+			zap.Int("attempts", d.Attempts),
+			zap.String("error", d.LastError))
+	}
+	return sendErr == nil
+}
+
+// send выполняет POST с подписанным телом; возвращает код ответа (0, если ответа не было).
+func (s *WebhookService) send(ctx context.Context, d *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OutfitStyle-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, d.Event)
+	req.Header.Set(WebhookEventIDHeader, d.EventID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(d.Secret, d.Payload, s.now()))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, webhookMaxResponse))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff — пауза после attempts неудачных попыток: BaseBackoff·2^(attempts-1), не больше
+// MaxBackoff, с разбросом ±10%, чтобы повторы к одному получателю не шли пачкой.
+func (s *WebhookService) backoff(attempts int) time.Duration {
+	d := float64(s.config.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if limit := float64(s.config.MaxBackoff); d > limit {
+		d = limit
+	}
+	d *= 0.9 + 0.2*mathrand.Float64()
+	return time.Duration(d)
+}
+
+// apply проверяет ввод и переносит его в вебхук.
+func (s *WebhookService) apply(hook *domain.Webhook, in WebhookInput) error {
+	rawURL := strings.TrimSpace(in.URL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && u.Scheme != "http") {
+		return errors.Wrapf(ErrInvalidWebhook, "url must be an absolute http(s) URL")
+	}
+	if u.Scheme == "http" && !s.config.AllowPrivate {
+		return errors.Wrapf(ErrInvalidWebhook, "url must use https")
+	}
+	if u.User != nil {
+		return errors.Wrapf(ErrInvalidWebhook, "url must not contain credentials")
+	}
+	if len(rawURL) > 2000 {
+		return errors.Wrapf(ErrInvalidWebhook, "url must be at most 2000 characters")
+	}
+
+	description := strings.TrimSpace(in.Description)
+	if len([]rune(description)) > 200 {
+		return errors.Wrapf(ErrInvalidWebhook, "description must be at most 200 characters")
+	}
+
+	if len(in.Events) == 0 {
+		return errors.Wrapf(ErrInvalidWebhook, "events must list at least one of: %s", strings.Join(domain.WebhookEvents, ", "))
+	}
+	seen := make(map[string]bool, len(in.Events))
+	events := make([]string, 0, len(in.Events))
+	for _, e := range in.Events {
+		e = strings.TrimSpace(e)
+		if !isWebhookEvent(e) {
+			return errors.Wrapf(ErrInvalidWebhook, "unknown event %q", e)
+		}
+		if !seen[e] {
+			seen[e] = true
+			events = append(events, e)
+		}
+	}
+
+	hook.URL = rawURL
+	hook.Description = description
+	hook.Events = events
+	if in.Active != nil {
+		hook.Active = *in.Active
+	}
+	return nil
+}
+
+func isWebhookEvent(event string) bool {
+	for _, e := range domain.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func newWebhookSecret() (string, error) {
+	secret, err := randomHex(32)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to generate webhook secret")
+	}
+	return "whsec_" + secret, nil
+}
+
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
@@ -0,0 +1,83 @@
+package domain
+
+import "time"
+
+// Показатели пользователя, доступные правилам достижений через stat.
+const (
+	StatRecommendations = "recommendations"
+	StatRatings         = "ratings"
+	StatFavorites       = "favorites"
+	StatWardrobeItems   = "wardrobe_items"
+	StatStreakDays      = "streak_days" // текущая серия заходов (Streak)
+)
+
+// AchievementStats — все показатели для правил.
+var AchievementStats = []string{
+	StatRecommendations,
+	StatRatings,
+	StatFavorites,
+	StatWardrobeItems,
+	StatStreakDays,
+}
+
+// Achievement — достижение из таблицы achievements.
+type Achievement struct {
+	ID          int       `json:"id"`
+	Code        string    `json:"code"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Icon        string    `json:"icon"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AchievementDefinition — достижение вместе с правилом из achievements.rule.
+type AchievementDefinition struct {
+	ID          int
+	Code        string
+	Name        string
+	Description string
+	Icon        string
+	Rule        AchievementRule
+}
+
+// AchievementRule — условие получения достижения. Правило срабатывает на событии Event
+// (пусто — на любом), если данные события подходят под Where. Без Stat достижение
+// открывается после Count таких событий (по умолчанию одного), со Stat — когда
+// показатель пользователя достигает Min.
+type AchievementRule struct {
+	Event string                          `json:"event,omitempty"`
+	Where map[string]AchievementCondition `json:"where,omitempty"`
+	Count int                             `json:"count,omitempty"`
+	Stat  string                          `json:"stat,omitempty"`
+	Min   float64                         `json:"min,omitempty"`
+}
+
+// AchievementCondition — условие на одно поле данных события. Заданные операторы
+// объединяются через И.
+type AchievementCondition struct {
+	Eq  interface{}   `json:"eq,omitempty"`
+	Lt  *float64      `json:"lt,omitempty"`
+	Lte *float64      `json:"lte,omitempty"`
+	Gt  *float64      `json:"gt,omitempty"`
+	Gte *float64      `json:"gte,omitempty"`
+	In  []interface{} `json:"in,omitempty"`
+}
+
+// AchievementStep — продвижение пользователя к одному достижению. Increment прибавляет
+// Progress к накопленному, иначе прогресс поднимается до Progress. Достижение
+// открывается, когда прогресс достигает Target.
+type AchievementStep struct {
+	AchievementID int
+	Progress      int
+	Increment     bool
+	Target        int
+}
+
+// UserEvent — доменное событие пользователя для правил достижений. Key однозначно
+// определяет событие: повторная обработка того же Key ничего не меняет.
+type UserEvent struct {
+	Key    string                 `json:"key"`
+	UserID int                    `json:"user_id"`
+	Type   string                 `json:"type"`
+	Data   map[string]interface{} `json:"data"`
+}
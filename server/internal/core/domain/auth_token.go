@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Виды одноразовых токенов аутентификации.
+const (
+	AuthTokenRegistration  = "registration"   // код подтверждения email
+	AuthTokenLogin         = "login"          // код входа
+	AuthTokenPasswordReset = "password_reset" // токен сброса пароля
+	AuthTokenTwoFactor     = "two_factor"     // вход по паролю ждёт кода 2FA
+)
+
+// AuthToken — одноразовый код или токен. Хранится только хэш: утечка таблицы
+// не даёт войти по ещё не использованным кодам.
+type AuthToken struct {
+	Hash      string
+	Kind      string
+	UserID    int
+	ExpiresAt time.Time
+}
@@ -0,0 +1,129 @@
+package domain
+
+import "time"
+
+// EventCheckIn — первый за день (UTC) просмотр или оценка рекомендации. Не публикуется,
+// используется только в правилах испытаний.
+const EventCheckIn = "check_in"
+
+// Заморозки серии: одна начисляется за каждые StreakFreezeEvery дней серии, копится не больше MaxStreakFreezes.
+const (
+	StreakFreezeEvery = 7
+	MaxStreakFreezes  = 2
+)
+
+// Streak — серия ежедневных заходов пользователя.
+type Streak struct {
+	CurrentStreak  int        `json:"current_streak"` // 0, если пропущено больше дней, чем есть заморозок
+	LongestStreak  int        `json:"longest_streak"`
+	LastCheckIn    *time.Time `json:"last_check_in"`
+	CheckedInToday bool       `json:"checked_in_today"`
+	FreezeTokens   int        `json:"freeze_tokens"`
+	FreezesUsed    int        `json:"freezes_used"`
+}
+
+// ChallengeRule — правило ежемесячного испытания (challenges.rule). Прогресс — число
+// подходящих событий, а с Distinct — число разных значений поля Distinct в них.
+type ChallengeRule struct {
+	Event    string                          `json:"event"`
+	Where    map[string]AchievementCondition `json:"where,omitempty"`
+	Count    int                             `json:"count,omitempty"`    // цель, по умолчанию 1
+	Distinct string                          `json:"distinct,omitempty"` // поле-значение или массив значений
+	NewOnly  bool                            `json:"new_only,omitempty"` // только значения, которых не было в прошлые месяцы
+}
+
+// Challenge — испытание из таблицы challenges.
+type Challenge struct {
+	ID                  int           `json:"id"`
+	Code                string        `json:"code"`
+	Name                string        `json:"name"`
+	Description         string        `json:"description"`
+	Icon                string        `json:"icon"`
+	Rule                ChallengeRule `json:"-"`
+	Month               *time.Time    `json:"-"` // nil — испытание каждый месяц
+	Target              int           `json:"target"`
+	RewardFreezes       int           `json:"reward_freezes"`
+	RewardAchievementID *int          `json:"-"`
+}
+
+// ChallengeProgress — испытание текущего месяца с прогрессом пользователя.
+type ChallengeProgress struct {
+	Challenge
+	Period      string     `json:"period"` // ГГГГ-ММ
+	EndsAt      time.Time  `json:"ends_at"`
+	Progress    int        `json:"progress"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// ChallengeStep — вклад события в одно испытание.
+type ChallengeStep struct {
+	ChallengeID int
+	Target      int
+	OnCheckIn   bool     // учитывается, только если событие — первый заход за день
+	Values      []string // для Distinct-правил; пусто — прибавить 1
+	NewOnly     bool
+}
+
+// GamificationEvent — событие пользователя для серий и испытаний.
+type GamificationEvent struct {
+	ID      string
+	UserID  int
+	At      time.Time
+	CheckIn bool // событие засчитывается как заход
+	Steps   []ChallengeStep
+}
+
+// GamificationResult — что изменило событие.
+type GamificationResult struct {
+	Streak    Streak
+	Completed []Challenge
+	Unlocked  []Achievement
+}
+
+// MonthStart возвращает первый день месяца t (UTC).
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// CheckIn засчитывает заход в день day (полночь UTC). Пропущенные дни покрываются
+// заморозками, если их хватает. Возвращает false, если день уже учтён или раньше последнего.
+func (s *Streak) CheckIn(day time.Time) bool {
+	if s.LastCheckIn != nil && !day.After(*s.LastCheckIn) {
+		return false
+	}
+
+	if s.LastCheckIn == nil {
+		s.CurrentStreak = 1
+	} else if missed := daysBetween(*s.LastCheckIn, day) - 1; missed <= s.FreezeTokens {
+		s.FreezeTokens -= missed
+		s.FreezesUsed += missed
+		s.CurrentStreak++
+	} else {
+		s.CurrentStreak = 1
+	}
+
+	if s.CurrentStreak%StreakFreezeEvery == 0 && s.FreezeTokens < MaxStreakFreezes {
+		s.FreezeTokens++
+	}
+	s.LongestStreak = max(s.LongestStreak, s.CurrentStreak)
+	s.LastCheckIn = &day
+	return true
+}
+
+// AsOf приводит серию к дню today: серия, которую не покрыть заморозками, уже прервана.
+func (s *Streak) AsOf(today time.Time) {
+	if s.LastCheckIn == nil {
+		s.CurrentStreak = 0
+		return
+	}
+	missed := daysBetween(*s.LastCheckIn, today) - 1
+	s.CheckedInToday = missed < 0
+	if missed > s.FreezeTokens {
+		s.CurrentStreak = 0
+	}
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
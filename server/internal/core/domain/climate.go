@@ -0,0 +1,25 @@
+package domain
+
+// Источники климатической нормы.
+const (
+	ClimateSourceDataset = "dataset" // поставляемый набор норм (climate/normals.csv)
+	ClimateSourceHistory = "history" // агрегат наших наблюдений из weather_data
+)
+
+// ClimateNormal — месячная климатическая норма для места.
+// Location хранится нормализованным: в нижнем регистре, без лишних пробелов.
+type ClimateNormal struct {
+	Location string `db:"location" json:"location"`
+	Month    int    `db:"month" json:"month"` // 1..12
+
+	TempMean float64 `db:"temp_mean" json:"temp_mean"`
+	TempMin  float64 `db:"temp_min" json:"temp_min"`
+	TempMax  float64 `db:"temp_max" json:"temp_max"`
+
+	// PrecipMM — сумма осадков за месяц; в истории наблюдений её нет, поэтому nil
+	PrecipMM   *float64 `db:"precip_mm" json:"precip_mm,omitempty"`
+	PrecipDays float64  `db:"precip_days" json:"precip_days"`
+
+	Source       string `db:"source" json:"source"`
+	Observations int    `db:"observations" json:"observations,omitempty"` // дней наблюдений для source=history
+}
@@ -54,4 +54,8 @@ type ClothingItem struct {
 	TranslatedBaseColour string `db:"-" json:"translated_base_colour,omitempty"`
 	TranslatedFit        string `db:"-" json:"translated_fit,omitempty"`
 	TranslatedPattern    string `db:"-" json:"translated_pattern,omitempty"`
+
+	// Фото вещи пользователя (not stored in DB, подписанные ссылки с ограниченным сроком)
+	PhotoURL     string `db:"-" json:"photo_url,omitempty"`
+	ThumbnailURL string `db:"-" json:"thumbnail_url,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// Статусы доставки утреннего дайджеста.
+const (
+	DigestStatusSending = "sending"
+	DigestStatusSent    = "sent"
+	DigestStatusSkipped = "skipped"
+	DigestStatusFailed  = "failed"
+)
+
+// DigestSubscription — подписка на утренний дайджест: письмо с рекомендацией на день
+// приходит в SendTime по местному времени Timezone.
+type DigestSubscription struct {
+	UserID    int       `json:"user_id"`
+	Enabled   bool      `json:"enabled"`
+	SendTime  string    `json:"send_time"` // ЧЧ:ММ
+	Timezone  string    `json:"timezone"`
+	NextRunAt time.Time `json:"next_run_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Coordinates — точка в градусах WGS84 (как отдаёт GPS телефона).
+type Coordinates struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// Validate проверяет диапазоны широты и долготы.
+func (c Coordinates) Validate() error {
+	if math.IsNaN(c.Lat) || c.Lat < -90 || c.Lat > 90 {
+		return fmt.Errorf("lat must be between -90 and 90, got %v", c.Lat)
+	}
+	if math.IsNaN(c.Lon) || c.Lon < -180 || c.Lon > 180 {
+		return fmt.Errorf("lon must be between -180 and 180, got %v", c.Lon)
+	}
+	return nil
+}
+
+// String форматирует точку для логов и как запасное название места.
+func (c Coordinates) String() string {
+	return strconv.FormatFloat(c.Lat, 'f', 4, 64) + ", " + strconv.FormatFloat(c.Lon, 'f', 4, 64)
+}
+
+// Виды сохранённых мест: дом и работа у пользователя по одному, своих мест — сколько угодно.
+const (
+	LocationKindHome   = "home"
+	LocationKindWork   = "work"
+	LocationKindCustom = "custom"
+)
+
+// UserLocation — сохранённое место пользователя. Место по умолчанию подставляется в запросы
+// рекомендаций без города и используется фоновыми задачами (рассылки, автозаполнение планов).
+type UserLocation struct {
+	ID         int    `db:"id" json:"id"`
+	UserID     int    `db:"user_id" json:"user_id"`
+	Kind       string `db:"kind" json:"kind"`
+	Name       string `db:"name" json:"name"`
+	LocationID string `db:"location_id" json:"location_id,omitempty"` // id города из справочника, если место выбрано из подсказок
+
+	Coordinates
+	Timezone  string `db:"timezone" json:"timezone"`
+	IsDefault bool   `db:"is_default" json:"is_default"`
+
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+}
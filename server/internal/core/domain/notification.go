@@ -0,0 +1,10 @@
+package domain
+
+// NotificationTarget — получатель фоновых уведомлений (предупреждения о погоде, утренняя рассылка).
+// Location — сохранённое место по умолчанию; если его нет, используется город из users.location.
+type NotificationTarget struct {
+	UserID   int
+	Email    string
+	City     string
+	Location *UserLocation
+}
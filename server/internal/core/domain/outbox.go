@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent — доменное событие, записанное в одной транзакции с изменением,
+// которое его породило. Публикует его OutboxRelay; EventID стабилен во всех попытках.
+type OutboxEvent struct {
+	ID            int64           `json:"id"`
+	EventID       string          `json:"event_id"`
+	UserID        int             `json:"user_id"`
+	Type          string          `json:"type"`
+	Data          json.RawMessage `json:"data"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Attempts      int             `json:"attempts"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	LastError     string          `json:"last_error,omitempty"`
+	PublishedAt   *time.Time      `json:"published_at,omitempty"`
+}
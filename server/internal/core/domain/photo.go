@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// ItemPhoto — фото вещи из гардероба пользователя. Сами файлы лежат в хранилище (локально или в S3),
+// в БД — только ключи объектов и метаданные.
+type ItemPhoto struct {
+	UserID       int    `db:"user_id" json:"user_id"`
+	ItemID       int64  `db:"clothing_item_id" json:"item_id"`
+	StorageKey   string `db:"storage_key" json:"-"`
+	ThumbnailKey string `db:"thumbnail_key" json:"-"`
+	ContentType  string `db:"content_type" json:"content_type"`
+	SizeBytes    int64  `db:"size_bytes" json:"size_bytes"`
+	Width        int    `db:"width" json:"width"`
+	Height       int    `db:"height" json:"height"`
+
+	UploadedAt time.Time `db:"uploaded_at" json:"uploaded_at"`
+}
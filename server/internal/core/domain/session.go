@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Session — сессия входа: семейство refresh-токенов одного устройства. Каждый refresh
+// заменяет токен новым; предъявление уже заменённого токена отзывает всю сессию.
+type Session struct {
+	ID         int64     `json:"id"`
+	UserID     int       `json:"-"`
+	Device     string    `json:"device"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// SessionClient — откуда пришёл запрос входа или refresh.
+type SessionClient struct {
+	Device string // User-Agent
+	IP     string
+}
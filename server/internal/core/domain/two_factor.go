@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// TwoFactor — настройка TOTP пользователя. Без EnabledAt — начатое, но не подтверждённое подключение.
+type TwoFactor struct {
+	UserID            int
+	Secret            string
+	EnabledAt         *time.Time
+	LastUsedStep      int64 // шаг последнего принятого кода: повтор кода не пройдёт
+	RecoveryCodesLeft int
+}
+
+// Enabled сообщает, требуется ли код при входе.
+func (t *TwoFactor) Enabled() bool {
+	return t != nil && t.EnabledAt != nil
+}
+
+// TwoFactorStatus — состояние 2FA для клиента.
+type TwoFactorStatus struct {
+	Enabled           bool       `json:"enabled"`
+	Pending           bool       `json:"pending"`
+	EnabledAt         *time.Time `json:"enabled_at,omitempty"`
+	RecoveryCodesLeft int        `json:"recovery_codes_left"`
+}
+
+// TwoFactorEnrollment — секрет для приложения-аутентификатора; показывается один раз.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
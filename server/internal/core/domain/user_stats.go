@@ -0,0 +1,46 @@
+package domain
+
+import "time"
+
+// Сезоны для статистики категорий (по месяцу UTC, северное полушарие).
+const (
+	SeasonWinter = "winter"
+	SeasonSpring = "spring"
+	SeasonSummer = "summer"
+	SeasonAutumn = "autumn"
+)
+
+// UserStats — основная статистика пользователя из таблицы user_stats.
+type UserStats struct {
+	TotalRecommendations int       `json:"total_recommendations"`
+	AverageRating        float64   `json:"average_rating"`
+	FavoriteCount        int       `json:"favorite_count"`
+	AchievementCount     int       `json:"achievement_count"`
+	LastActive           time.Time `json:"last_active"`
+	MostUsedCategory     string    `json:"most_used_category"`
+}
+
+// ExtendedUserStats — статистика пользователя вместе с полями, которые ведёт StatsService.
+type ExtendedUserStats struct {
+	UserStats
+	RatingCount        int               `json:"rating_count"`
+	CurrentStreak      int               `json:"current_streak"` // серия заходов из user_streaks, как в Streak
+	LongestStreak      int               `json:"longest_streak"`
+	AverageTemperature *float64          `json:"average_temperature"` // nil, пока нет рекомендаций
+	SeasonCategories   map[string]string `json:"season_categories"`   // сезон -> самая частая категория
+	UpdatedAt          time.Time         `json:"updated_at"`
+}
+
+// SeasonOf возвращает сезон месяца.
+func SeasonOf(month time.Month) string {
+	switch month {
+	case time.December, time.January, time.February:
+		return SeasonWinter
+	case time.March, time.April, time.May:
+		return SeasonSpring
+	case time.June, time.July, time.August:
+		return SeasonSummer
+	default:
+		return SeasonAutumn
+	}
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// События, на которые можно подписать вебхук.
+const (
+	EventRecommendationCreated = "recommendation.created"
+	EventRatingSubmitted       = "rating.submitted"
+	EventWardrobeItemAdded     = "wardrobe.item_added"
+	EventWardrobeItemRemoved   = "wardrobe.item_removed"
+	EventAchievementUnlocked   = "achievement.unlocked"
+	EventFavoriteAdded         = "favorite.added"
+	EventFavoriteRemoved       = "favorite.removed"
+	EventChallengeCompleted    = "challenge.completed"
+)
+
+// WebhookEvents — все поддерживаемые события.
+var WebhookEvents = []string{
+	EventRecommendationCreated,
+	EventRatingSubmitted,
+	EventWardrobeItemAdded,
+	EventWardrobeItemRemoved,
+	EventAchievementUnlocked,
+	EventFavoriteAdded,
+	EventFavoriteRemoved,
+	EventChallengeCompleted,
+}
+
+// Происхождение рекомендации (recommendations.origin, поле origin события recommendation.created).
+// Рекомендации дайджеста не засчитываются как заход, в испытания, статистику и достижения.
+const (
+	RecommendationOriginUser   = "user"
+	RecommendationOriginDigest = "digest"
+)
+
+// Статусы доставки вебхука: pending — ждёт (первой или повторной) попытки,
+// dead — попытки исчерпаны, доставку можно повторить вручную.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryDead      = "dead"
+)
+
+// Webhook — подписка пользователя на события. Secret подписывает тело запроса (HMAC-SHA256)
+// и возвращается только при создании и смене секрета.
+type Webhook struct {
+	ID          int       `json:"id"`
+	UserID      int       `json:"user_id"`
+	URL         string    `json:"url"`
+	Description string    `json:"description,omitempty"`
+	Events      []string  `json:"events"`
+	Secret      string    `json:"secret,omitempty"`
+	Active      bool      `json:"active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// WebhookEvent — тело запроса к вебхуку. ID одинаков во всех попытках доставки,
+// по нему получатель отбрасывает повторы.
+type WebhookEvent struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	UserID    int         `json:"user_id"`
+	Data      interface{} `json:"data"`
+}
+
+// WebhookDelivery — доставка одного события одному вебхуку (журнал доставок).
+type WebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int             `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	Event          string          `json:"event"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	LastStatusCode int             `json:"last_status_code,omitempty"`
+	LastError      string          `json:"last_error,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+
+	// Адрес и секрет вебхука — только для отправки, в журнал не попадают
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
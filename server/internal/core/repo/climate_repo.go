@@ -0,0 +1,17 @@
+package repo
+
+import (
+	"context"
+	"outfitstyle/server/internal/core/domain"
+)
+
+type ClimateNormalRepository interface {
+	UpsertMany(ctx context.Context, normals []domain.ClimateNormal) error
+
+	// Get возвращает нормы места за месяц из всех источников (пустой срез, если их нет).
+	Get(ctx context.Context, location string, month int) ([]domain.ClimateNormal, error)
+
+	// AggregateHistory сводит weather_data в месячные нормы для мест, где набралось
+	// не меньше minDays дней наблюдений за месяц.
+	AggregateHistory(ctx context.Context, minDays int) ([]domain.ClimateNormal, error)
+}
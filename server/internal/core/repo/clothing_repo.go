@@ -2,18 +2,49 @@ package repo
 
 import (
 	"context"
-	"outfit-style-rec/server/internal/core/domain"
+	"errors"
+	"outfitstyle/server/internal/core/domain"
+	"time"
 )
 
+var (
+	ErrSpecNotFound = errors.New("subcategory spec not found")
+	ErrSpecExists   = errors.New("subcategory spec already exists")
+	ErrSpecInUse    = errors.New("subcategory spec is referenced by clothing items")
+)
+
+// ClothingItemFilter — фильтр выгрузки каталога. Пустые поля не ограничивают выборку.
+type ClothingItemFilter struct {
+	Sources      []string
+	Categories   []string
+	CreatedAfter time.Time
+}
+
 type SubcategorySpecRepository interface {
 	ListAll(ctx context.Context) ([]domain.SubcategorySpec, error)
 	Get(ctx context.Context, category, subcategory string) (domain.SubcategorySpec, error)
+	UpsertMany(ctx context.Context, specs []domain.SubcategorySpec) error
+
+	Create(ctx context.Context, spec domain.SubcategorySpec) error
+	Update(ctx context.Context, spec domain.SubcategorySpec) error
+	Delete(ctx context.Context, category, subcategory string) error
 }
 
 type ClothingItemRepository interface {
 	BulkInsert(ctx context.Context, items []domain.ClothingItem) error
+	CopyItems(ctx context.Context, items []domain.ClothingItem, upsert bool) (int64, error)
 
 	GetByID(ctx context.Context, id int64) (domain.ClothingItem, error)
 
+	Stream(ctx context.Context, filter ClothingItemFilter, fn func(domain.ClothingItem) error) error
+
+	// CountBySubcategory возвращает число всех вещей подкатегории (включая карантин — на них тоже
+	// ссылается FK) и сколько из них вне карантина и теплее warmthMin, то есть остаются кандидатами
+	// FindCandidatesByPlan при такой норме.
+	CountBySubcategory(ctx context.Context, category, subcategory string, warmthMin int16) (total, eligible int64, err error)
+
+	// Quarantine исключает вещи из подбора (id -> причины из отчёта о качестве).
+	Quarantine(ctx context.Context, reasons map[int64][]string) (int64, error)
+
 	FindCandidatesByPlan(ctx context.Context, category string, subcategories []string, warmthMin int16, temp int16, limit int) ([]domain.ClothingItem, error)
 }
\ No newline at end of file
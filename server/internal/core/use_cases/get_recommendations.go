@@ -9,9 +9,11 @@ import (
 )
 
 // GetRecommendationsInput represents the input for the GetRecommendations use case.
+// Место задаётся либо городом, либо координатами; координаты приоритетнее.
 type GetRecommendationsInput struct {
-	UserID int    `json:"user_id"`
-	City   string `json:"city"`
+	UserID int                 `json:"user_id"`
+	City   string              `json:"city"`
+	Coords *domain.Coordinates `json:"coords,omitempty"`
 }
 
 // GetRecommendationsOutput represents the output for the GetRecommendations use case.
@@ -33,8 +35,10 @@ type getRecommendationsUseCase struct {
 }
 
 // WeatherService defines the interface for weather service.
+// Совпадает с external.WeatherProvider: подходит и отдельный провайдер, и сервис с failover.
 type WeatherService interface {
-	GetWeather(ctx context.Context, city string) (*domain.WeatherData, error)
+	GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error)
+	GetWeatherByCoords(ctx context.Context, coords domain.Coordinates) (*domain.ExtendedWeatherData, error)
 }
 
 // MLService defines the interface for ML service.
@@ -64,13 +68,22 @@ func (uc *getRecommendationsUseCase) Execute(
 	ctx context.Context,
 	input GetRecommendationsInput,
 ) (*GetRecommendationsOutput, error) {
-	// Validate input
-	if input.City == "" {
-		return nil, fmt.Errorf("city is required")
+	// Validate input and get weather data
+	var (
+		weather *domain.ExtendedWeatherData
+		err     error
+	)
+	switch {
+	case input.Coords != nil:
+		if err := input.Coords.Validate(); err != nil {
+			return nil, err
+		}
+		weather, err = uc.weatherService.GetWeatherByCoords(ctx, *input.Coords)
+	case input.City != "":
+		weather, err = uc.weatherService.GetWeather(ctx, input.City)
+	default:
+		return nil, fmt.Errorf("city or coordinates are required")
 	}
-
-	// Get weather data
-	weather, err := uc.weatherService.GetWeather(ctx, input.City)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get weather data: %w", err)
 	}
@@ -85,13 +98,13 @@ func (uc *getRecommendationsUseCase) Execute(
 	_ = userProfile // пока профиль не используется, чтобы не было "declared and not used"
 
 	// Get ML recommendations
-	mlRecommendation, err := uc.mlService.GetRecommendations(ctx, input.UserID, *weather)
+	mlRecommendation, err := uc.mlService.GetRecommendations(ctx, input.UserID, weather.WeatherData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get ML recommendations: %w", err)
 	}
 
 	// Сохранить рекомендацию в БД
-	if _, err := uc.recommendationRepo.CreateRecommendation(ctx, mlRecommendation); err != nil {
+	if _, err := uc.recommendationRepo.CreateRecommendation(ctx, mlRecommendation, domain.RecommendationOriginUser, nil); err != nil {
 		// Не роняем весь use case, но возвращаем обёрнутую ошибку, если хочешь:
 		// return nil, fmt.Errorf("failed to save recommendation: %w", err)
 		// или можно просто залогировать, если сюда добавить logger
@@ -44,5 +44,5 @@ func (uc *RateRecommendationUseCase) Execute(
 	userID, recommendationID, rating int,
 	feedback string,
 ) error {
-	return uc.UserRepository.RateRecommendation(ctx, userID, recommendationID, rating, feedback)
+	return uc.UserRepository.RateRecommendation(ctx, userID, recommendationID, rating, feedback, nil)
 }
@@ -0,0 +1,14 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache — key/value кэш с TTL. Промах — (nil, false, nil); ошибка означает недоступность бэкенда,
+// и вызывающий код должен работать так, будто кэша нет.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, key string) error
+}
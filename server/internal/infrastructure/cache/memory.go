@@ -0,0 +1,69 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memorySweepInterval — как часто вычищаются просроченные записи.
+const memorySweepInterval = time.Minute
+
+type memoryEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache — кэш в памяти процесса. Подходит для одного инстанса и для разработки.
+type MemoryCache struct {
+	mu        sync.RWMutex
+	items     map[string]memoryEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryCache creates an in-process cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		items:     make(map[string]memoryEntry),
+		lastSweep: time.Now(),
+		now:       time.Now,
+	}
+}
+
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.RLock()
+	e, ok := c.items[key]
+	c.mu.RUnlock()
+	if !ok || c.now().After(e.expiresAt) {
+		return nil, false, nil
+	}
+	return e.value, true, nil
+}
+
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := c.now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+
+	// Ленивая уборка вместо отдельной горутины: просроченное удаляется при записи
+	if now.Sub(c.lastSweep) >= memorySweepInterval {
+		for k, e := range c.items {
+			if now.After(e.expiresAt) {
+				delete(c.items, k)
+			}
+		}
+		c.lastSweep = now
+	}
+	return nil
+}
+
+func (c *MemoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	delete(c.items, key)
+	c.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisCache — кэш в Redis, общий для всех инстансов API.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache подключается по URL вида redis://[:password@]host:port/db и проверяет соединение.
+// prefix отделяет ключи приложения от чужих ключей в той же БД Redis.
+func NewRedisCache(ctx context.Context, redisURL, prefix string) (*RedisCache, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse redis url: %w", err)
+	}
+
+	client := redis.NewClient(opts)
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+
+	return &RedisCache{client: client, prefix: prefix}, nil
+}
+
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := c.client.Get(ctx, c.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (c *RedisCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.client.Set(ctx, c.prefix+key, value, ttl).Err()
+}
+
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, c.prefix+key).Err()
+}
+
+// HealthCheck реализует интерфейс health.Checker.
+func (c *RedisCache) HealthCheck() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	return c.client.Ping(ctx).Err()
+}
+
+// Close закрывает пул соединений.
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
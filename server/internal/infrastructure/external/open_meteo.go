@@ -0,0 +1,304 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+const (
+	defaultOpenMeteoURL          = "https://api.open-meteo.com/v1"
+	defaultOpenMeteoGeocodingURL = "https://geocoding-api.open-meteo.com/v1"
+)
+
+// OpenMeteoProvider получает погоду из Open-Meteo: сначала геокодирование города, затем прогноз.
+// Ключ не нужен, поэтому провайдер подходит как резервный при исчерпанной квоте OpenWeatherMap.
+type OpenMeteoProvider struct {
+	baseURL      string
+	geocodingURL string
+	client       *http.Client
+	logger       *zap.Logger
+}
+
+// NewOpenMeteoProvider создаёт провайдера Open-Meteo. Пустые URL — публичные эндпоинты.
+func NewOpenMeteoProvider(baseURL, geocodingURL string, timeout time.Duration, logger *zap.Logger) *OpenMeteoProvider {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenMeteoURL
+	}
+	if geocodingURL == "" {
+		geocodingURL = defaultOpenMeteoGeocodingURL
+	}
+
+	return &OpenMeteoProvider{
+		baseURL:      strings.TrimRight(baseURL, "/"),
+		geocodingURL: strings.TrimRight(geocodingURL, "/"),
+		client: &http.Client{
+			Timeout: timeout,
+		},
+		logger: logger,
+	}
+}
+
+// Name возвращает имя провайдера для логов и circuit breaker.
+func (p *OpenMeteoProvider) Name() string {
+	return "open-meteo"
+}
+
+type openMeteoGeocodingResponse struct {
+	Results []struct {
+		Name      string  `json:"name"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+	} `json:"results"`
+}
+
+type openMeteoForecastResponse struct {
+	Current struct {
+		Temperature         float64 `json:"temperature_2m"`
+		ApparentTemperature float64 `json:"apparent_temperature"`
+		RelativeHumidity    int     `json:"relative_humidity_2m"`
+		WeatherCode         int     `json:"weather_code"`
+		WindSpeed           float64 `json:"wind_speed_10m"`
+	} `json:"current"`
+	Daily struct {
+		Time           []string  `json:"time"`
+		TemperatureMax []float64 `json:"temperature_2m_max"`
+		TemperatureMin []float64 `json:"temperature_2m_min"`
+		WeatherCode    []int     `json:"weather_code"`
+		WindSpeedMax   []float64 `json:"wind_speed_10m_max"`
+	} `json:"daily"`
+}
+
+// GetWeather возвращает доменную погоду по имени города.
+func (p *OpenMeteoProvider) GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error) {
+	coords, name, err := p.geocode(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+	return p.forecast(ctx, coords, name)
+}
+
+// GetDailyForecast возвращает прогноз по дням на days дней начиная с сегодняшнего (по местному времени города).
+func (p *OpenMeteoProvider) GetDailyForecast(ctx context.Context, city string, days int) ([]DailyForecast, error) {
+	coords, _, err := p.geocode(ctx, city)
+	if err != nil {
+		return nil, err
+	}
+
+	q := url.Values{}
+	q.Set("latitude", strconv.FormatFloat(coords.Lat, 'f', 4, 64))
+	q.Set("longitude", strconv.FormatFloat(coords.Lon, 'f', 4, 64))
+	q.Set("daily", "temperature_2m_max,temperature_2m_min,weather_code,wind_speed_10m_max")
+	q.Set("wind_speed_unit", "ms")
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", strconv.Itoa(days))
+
+	var forecast openMeteoForecastResponse
+	if err := p.getJSON(ctx, p.baseURL+"/forecast", q, &forecast); err != nil {
+		return nil, fmt.Errorf("open-meteo forecast: %w", err)
+	}
+
+	d := forecast.Daily
+	out := make([]DailyForecast, 0, len(d.Time))
+	for i, day := range d.Time {
+		if i >= len(d.TemperatureMin) || i >= len(d.TemperatureMax) || i >= len(d.WeatherCode) {
+			break
+		}
+		date, err := time.Parse("2006-01-02", day)
+		if err != nil {
+			return nil, fmt.Errorf("open-meteo forecast: bad date %q: %w", day, err)
+		}
+		f := DailyForecast{
+			Date:      date,
+			MinTemp:   d.TemperatureMin[i],
+			MaxTemp:   d.TemperatureMax[i],
+			Weather:   wmoDescription(d.WeatherCode[i]),
+			Condition: wmoCondition(d.WeatherCode[i]),
+		}
+		if i < len(d.WindSpeedMax) {
+			f.WindSpeed = d.WindSpeedMax[i]
+		}
+		out = append(out, f)
+	}
+	return out, nil
+}
+
+func (p *OpenMeteoProvider) geocode(ctx context.Context, city string) (domain.Coordinates, string, error) {
+	q := url.Values{}
+	q.Set("name", city)
+	q.Set("count", "1")
+	q.Set("language", "ru")
+	q.Set("format", "json")
+
+	var geo openMeteoGeocodingResponse
+	if err := p.getJSON(ctx, p.geocodingURL+"/search", q, &geo); err != nil {
+		return domain.Coordinates{}, "", fmt.Errorf("open-meteo geocoding: %w", err)
+	}
+	if len(geo.Results) == 0 {
+		p.logger.Warn("city not found in Open-Meteo", zap.String("city", city))
+		return domain.Coordinates{}, "", ErrCityNotFound
+	}
+	place := geo.Results[0]
+	return domain.Coordinates{Lat: place.Latitude, Lon: place.Longitude}, place.Name, nil
+}
+
+// GetWeatherByCoords возвращает доменную погоду по координатам. Обратного геокодирования
+// у Open-Meteo нет, поэтому Location — сами координаты.
+func (p *OpenMeteoProvider) GetWeatherByCoords(ctx context.Context, coords domain.Coordinates) (*domain.ExtendedWeatherData, error) {
+	return p.forecast(ctx, coords, coords.String())
+}
+
+func (p *OpenMeteoProvider) forecast(ctx context.Context, coords domain.Coordinates, location string) (*domain.ExtendedWeatherData, error) {
+	q := url.Values{}
+	q.Set("latitude", strconv.FormatFloat(coords.Lat, 'f', 4, 64))
+	q.Set("longitude", strconv.FormatFloat(coords.Lon, 'f', 4, 64))
+	q.Set("current", "temperature_2m,apparent_temperature,relative_humidity_2m,weather_code,wind_speed_10m")
+	q.Set("daily", "temperature_2m_max,temperature_2m_min,weather_code")
+	q.Set("wind_speed_unit", "ms")
+	q.Set("timezone", "auto")
+	q.Set("forecast_days", "1")
+
+	var forecast openMeteoForecastResponse
+	if err := p.getJSON(ctx, p.baseURL+"/forecast", q, &forecast); err != nil {
+		return nil, fmt.Errorf("open-meteo forecast: %w", err)
+	}
+
+	cur := forecast.Current
+	minTemp, maxTemp := cur.Temperature, cur.Temperature
+	if len(forecast.Daily.TemperatureMin) > 0 && len(forecast.Daily.TemperatureMax) > 0 {
+		minTemp, maxTemp = forecast.Daily.TemperatureMin[0], forecast.Daily.TemperatureMax[0]
+	}
+	// Дневной код — самое «тяжёлое» явление за сутки: по нему решаем, будут ли дождь или снег
+	dayCode := cur.WeatherCode
+	if len(forecast.Daily.WeatherCode) > 0 {
+		dayCode = forecast.Daily.WeatherCode[0]
+	}
+
+	weather := &domain.ExtendedWeatherData{
+		WeatherData: domain.WeatherData{
+			Location:       location,
+			Temperature:    cur.Temperature,
+			FeelsLike:      cur.ApparentTemperature,
+			Weather:        wmoDescription(cur.WeatherCode),
+			Humidity:       cur.RelativeHumidity,
+			WindSpeed:      cur.WindSpeed,
+			MinTemp:        minTemp,
+			MaxTemp:        maxTemp,
+			WillRain:       wmoIsRain(dayCode),
+			WillSnow:       wmoIsSnow(dayCode),
+			HourlyForecast: []domain.HourlyWeather{},
+		},
+		Timestamp: time.Now().UTC(),
+	}
+
+	return weather, nil
+}
+
+func (p *OpenMeteoProvider) getJSON(ctx context.Context, endpoint string, q url.Values, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint+"?"+q.Encode(), nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		p.logger.Error("Open-Meteo returned error",
+			zap.String("endpoint", endpoint),
+			zap.Int("status", resp.StatusCode),
+			zap.ByteString("body", body),
+		)
+		return fmt.Errorf("status=%d", resp.StatusCode)
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// wmoDescriptions — коды погоды WMO 4677 (используются Open-Meteo) в формулировках, близких к OpenWeatherMap lang=ru.
+var wmoDescriptions = map[int]string{
+	0:  "ясно",
+	1:  "преимущественно ясно",
+	2:  "переменная облачность",
+	3:  "пасмурно",
+	45: "туман",
+	48: "изморозь",
+	51: "слабая морось",
+	53: "морось",
+	55: "сильная морось",
+	56: "ледяная морось",
+	57: "сильная ледяная морось",
+	61: "небольшой дождь",
+	63: "дождь",
+	65: "сильный дождь",
+	66: "ледяной дождь",
+	67: "сильный ледяной дождь",
+	71: "небольшой снег",
+	73: "снег",
+	75: "сильный снег",
+	77: "снежные зёрна",
+	80: "небольшой ливень",
+	81: "ливень",
+	82: "сильный ливень",
+	85: "небольшой снегопад",
+	86: "сильный снегопад",
+	95: "гроза",
+	96: "гроза с градом",
+	99: "сильная гроза с градом",
+}
+
+func wmoDescription(code int) string {
+	if d, ok := wmoDescriptions[code]; ok {
+		return d
+	}
+	return "переменная облачность"
+}
+
+// wmoCondition сводит код WMO к группе погоды в терминах OpenWeatherMap (clear, clouds, rain, ...).
+func wmoCondition(code int) string {
+	switch {
+	case code <= 1:
+		return "clear"
+	case code <= 3:
+		return "clouds"
+	case code == 45 || code == 48:
+		return "mist"
+	case code >= 51 && code <= 57:
+		return "drizzle"
+	case code >= 95:
+		return "thunderstorm"
+	case wmoIsSnow(code):
+		return "snow"
+	case wmoIsRain(code):
+		return "rain"
+	}
+	return "clouds"
+}
+
+func wmoIsRain(code int) bool {
+	return (code >= 51 && code <= 67) || (code >= 80 && code <= 82) || code >= 95
+}
+
+func wmoIsSnow(code int) bool {
+	return (code >= 71 && code <= 77) || code == 85 || code == 86
+}
@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,26 +16,23 @@ import (
 	"outfitstyle/server/internal/core/domain"
 )
 
-// ErrCityNotFound возвращается, если OpenWeatherMap вернул 404 по городу.
-var ErrCityNotFound = fmt.Errorf("city not found")
-
-// WeatherService инкапсулирует работу с OpenWeatherMap.
-type WeatherService struct {
+// OpenWeatherMapProvider получает погоду из OpenWeatherMap (/weather).
+type OpenWeatherMapProvider struct {
 	baseURL string
 	apiKey  string
 	client  *http.Client
 	logger  *zap.Logger
 }
 
-// NewWeatherService создаёт новый сервис погоды.
-func NewWeatherService(apiKey, baseURL string, timeout time.Duration, logger *zap.Logger) *WeatherService {
+// NewOpenWeatherMapProvider создаёт провайдера OpenWeatherMap.
+func NewOpenWeatherMapProvider(apiKey, baseURL string, timeout time.Duration, logger *zap.Logger) *OpenWeatherMapProvider {
 	if logger == nil {
 		logger = zap.NewNop()
 	}
 
 	baseURL = strings.TrimRight(baseURL, "/")
 
-	return &WeatherService{
+	return &OpenWeatherMapProvider{
 		baseURL: baseURL,
 		apiKey:  apiKey,
 		client: &http.Client{
@@ -68,8 +66,38 @@ type owmCurrentResponse struct {
 	Dt int64 `json:"dt"`
 }
 
+// Name возвращает имя провайдера для логов и circuit breaker.
+func (s *OpenWeatherMapProvider) Name() string {
+	return "openweathermap"
+}
+
 // GetWeather возвращает доменную погоду по имени города.
-func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error) {
+func (s *OpenWeatherMapProvider) GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error) {
+	q := url.Values{}
+	q.Set("q", city)
+
+	return s.current(ctx, q, zap.String("city", city))
+}
+
+// GetWeatherByCoords возвращает доменную погоду по координатам; Location — ближайший
+// населённый пункт по данным OpenWeatherMap.
+func (s *OpenWeatherMapProvider) GetWeatherByCoords(ctx context.Context, coords domain.Coordinates) (*domain.ExtendedWeatherData, error) {
+	q := url.Values{}
+	q.Set("lat", strconv.FormatFloat(coords.Lat, 'f', 4, 64))
+	q.Set("lon", strconv.FormatFloat(coords.Lon, 'f', 4, 64))
+
+	weather, err := s.current(ctx, q, zap.Stringer("coords", coords))
+	if err != nil {
+		return nil, err
+	}
+	// Для точки в море или в глуши название пустое
+	if weather.Location == "" {
+		weather.Location = coords.String()
+	}
+	return weather, nil
+}
+
+func (s *OpenWeatherMapProvider) current(ctx context.Context, q url.Values, query zap.Field) (*domain.ExtendedWeatherData, error) {
 	if s.apiKey == "" || s.baseURL == "" {
 		return nil, fmt.Errorf("weather service is not configured: empty apiKey or baseURL")
 	}
@@ -77,8 +105,6 @@ func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.E
 	endpoint := s.baseURL + "/weather"
 
 	// Собираем query-параметры
-	q := url.Values{}
-	q.Set("q", city)
 	q.Set("appid", s.apiKey)
 	q.Set("units", "metric")
 	q.Set("lang", "ru")
@@ -105,13 +131,13 @@ func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.E
 	// 404 — такого города нет
 	if resp.StatusCode == http.StatusNotFound {
 		s.logger.Warn("city not found in OpenWeatherMap",
-			zap.String("city", city),
+			query,
 			zap.ByteString("body", body),
 		)
 		return nil, ErrCityNotFound
 	}
 
-	// Любой не 200 — ошибка провайдера или ключа
+	// 401/429 — неверный ключ или исчерпана квота: это отказ провайдера, переключаемся на следующий
 	if resp.StatusCode != http.StatusOK {
 		s.logger.Error("OpenWeatherMap returned error",
 			zap.Int("status", resp.StatusCode),
@@ -154,8 +180,8 @@ func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.E
 	return weather, nil
 }
 
-// HealthCheck реализует интерфейс health.Checker.
-func (s *WeatherService) HealthCheck() error {
+// HealthCheck проверяет, что провайдер настроен.
+func (s *OpenWeatherMapProvider) HealthCheck() error {
 	if s.apiKey == "" {
 		return fmt.Errorf("weather service api key is missing")
 	}
@@ -0,0 +1,191 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/infrastructure/cache"
+)
+
+// weatherFetchTimeout ограничивает общий запрос к провайдерам: он не привязан к контексту
+// первого клиента, чтобы его отмена не роняла остальных ожидающих.
+const weatherFetchTimeout = 15 * time.Second
+
+var weatherCacheRequests = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "outfitstyle_weather_cache_requests_total",
+		Help: "Weather lookups by cache result (hit, miss, shared)",
+	},
+	[]string{"result"},
+)
+
+// CachedWeatherProvider кэширует погоду по нормализованному названию места или координатам, объединяет
+// одновременные запросы к одному месту в один (singleflight) и пишет каждое наблюдение в weather_data.
+type CachedWeatherProvider struct {
+	next        WeatherProvider
+	cache       cache.Cache
+	weatherRepo repositories.WeatherRepository
+	ttl         time.Duration
+	group       singleflight.Group
+	logger      *zap.Logger
+}
+
+// NewCachedWeatherProvider оборачивает провайдера кэшем. ttl <= 0 отключает кэш,
+// weatherRepo == nil отключает запись истории.
+func NewCachedWeatherProvider(
+	next WeatherProvider,
+	c cache.Cache,
+	weatherRepo repositories.WeatherRepository,
+	ttl time.Duration,
+	logger *zap.Logger,
+) *CachedWeatherProvider {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &CachedWeatherProvider{
+		next:        next,
+		cache:       c,
+		weatherRepo: weatherRepo,
+		ttl:         ttl,
+		logger:      logger,
+	}
+}
+
+// Name возвращает имя обёрнутого провайдера.
+func (p *CachedWeatherProvider) Name() string {
+	return p.next.Name()
+}
+
+// GetWeather возвращает погоду из кэша или запрашивает её у провайдера.
+func (p *CachedWeatherProvider) GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error) {
+	return p.lookup(ctx, NormalizeLocation(city), func(ctx context.Context) (*domain.ExtendedWeatherData, error) {
+		return p.next.GetWeather(ctx, city)
+	})
+}
+
+// GetWeatherByCoords возвращает погоду по координатам из кэша или у провайдера.
+// Ключ округляется до сотых градуса (~1 км): соседние точки делят одну запись.
+func (p *CachedWeatherProvider) GetWeatherByCoords(ctx context.Context, coords domain.Coordinates) (*domain.ExtendedWeatherData, error) {
+	rounded := domain.Coordinates{Lat: math.Round(coords.Lat*100) / 100, Lon: math.Round(coords.Lon*100) / 100}
+	key := "coords:" + strconv.FormatFloat(rounded.Lat, 'f', 2, 64) + "," + strconv.FormatFloat(rounded.Lon, 'f', 2, 64)
+	return p.lookup(ctx, key, func(ctx context.Context) (*domain.ExtendedWeatherData, error) {
+		return p.next.GetWeatherByCoords(ctx, rounded)
+	})
+}
+
+func (p *CachedWeatherProvider) lookup(
+	ctx context.Context,
+	key string,
+	get func(ctx context.Context) (*domain.ExtendedWeatherData, error),
+) (*domain.ExtendedWeatherData, error) {
+	if weather, ok := p.fromCache(ctx, key); ok {
+		weatherCacheRequests.WithLabelValues("hit").Inc()
+		return weather, nil
+	}
+
+	ch := p.group.DoChan(key, func() (interface{}, error) {
+		fetchCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), weatherFetchTimeout)
+		defer cancel()
+		return p.fetch(fetchCtx, key, get)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Shared {
+			weatherCacheRequests.WithLabelValues("shared").Inc()
+		} else {
+			weatherCacheRequests.WithLabelValues("miss").Inc()
+		}
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		// Копия: у каждого вызывающего свой объект, даже если запрос был общий
+		weather := *res.Val.(*domain.ExtendedWeatherData)
+		return &weather, nil
+	}
+}
+
+// HealthCheck делегирует проверку обёрнутому провайдеру.
+func (p *CachedWeatherProvider) HealthCheck() error {
+	if hc, ok := p.next.(interface{ HealthCheck() error }); ok {
+		return hc.HealthCheck()
+	}
+	return nil
+}
+
+func (p *CachedWeatherProvider) fetch(
+	ctx context.Context,
+	key string,
+	get func(ctx context.Context) (*domain.ExtendedWeatherData, error),
+) (*domain.ExtendedWeatherData, error) {
+	// Между первой проверкой и этим запросом значение мог положить другой инстанс
+	if weather, ok := p.fromCache(ctx, key); ok {
+		return weather, nil
+	}
+
+	weather, err := get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.weatherRepo != nil {
+		if _, err := p.weatherRepo.SaveObservation(ctx, weather); err != nil {
+			p.logger.Warn("Failed to persist weather observation", zap.Error(err), zap.String("location", key))
+		}
+	}
+
+	if p.cache != nil && p.ttl > 0 {
+		if data, err := json.Marshal(weather); err == nil {
+			if err := p.cache.Set(ctx, weatherCacheKey(key), data, p.ttl); err != nil {
+				p.logger.Warn("Failed to cache weather", zap.Error(err), zap.String("location", key))
+			}
+		}
+	}
+
+	return weather, nil
+}
+
+func (p *CachedWeatherProvider) fromCache(ctx context.Context, key string) (*domain.ExtendedWeatherData, bool) {
+	if p.cache == nil || p.ttl <= 0 {
+		return nil, false
+	}
+
+	data, ok, err := p.cache.Get(ctx, weatherCacheKey(key))
+	if err != nil {
+		p.logger.Warn("Weather cache unavailable", zap.Error(err))
+		return nil, false
+	}
+	if !ok {
+		return nil, false
+	}
+
+	var weather domain.ExtendedWeatherData
+	if err := json.Unmarshal(data, &weather); err != nil {
+		p.logger.Warn("Corrupted weather cache entry", zap.Error(err), zap.String("location", key))
+		return nil, false
+	}
+	return &weather, true
+}
+
+// NormalizeLocation приводит название места к ключу кэша: " Санкт-Петербург ", "санкт-петербург"
+// и "САНКТ-ПЕТЕРБУРГ" дают один ключ.
+func NormalizeLocation(city string) string {
+	return strings.Join(strings.Fields(strings.ToLower(city)), " ")
+}
+
+func weatherCacheKey(location string) string {
+	return "weather:" + location
+}
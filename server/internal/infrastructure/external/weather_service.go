@@ -0,0 +1,202 @@
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sony/gobreaker"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/domain"
+)
+
+var (
+	// ErrCityNotFound возвращается, если провайдер не знает такого города.
+	ErrCityNotFound = errors.New("city not found")
+	// ErrNoWeatherProvider возвращается, когда все провайдеры недоступны или их circuit breaker разомкнут.
+	ErrNoWeatherProvider = errors.New("no weather provider available")
+)
+
+// WeatherProvider — источник текущей погоды. Ответ нормализуется в domain.ExtendedWeatherData:
+// температура в °C, ветер в м/с, описание на русском, Location — название найденного места.
+type WeatherProvider interface {
+	Name() string
+	GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error)
+	GetWeatherByCoords(ctx context.Context, coords domain.Coordinates) (*domain.ExtendedWeatherData, error)
+}
+
+// DailyForecast — прогноз на один день. Date — местная дата места (полночь UTC),
+// Condition — группа погоды в терминах OpenWeatherMap: clear, clouds, rain, drizzle, snow, mist, thunderstorm.
+type DailyForecast struct {
+	Date      time.Time
+	MinTemp   float64
+	MaxTemp   float64
+	Weather   string
+	Condition string
+	WindSpeed float64 // максимальный за день, м/с
+}
+
+// DailyForecastProvider — провайдер, который умеет прогноз на несколько дней вперёд.
+type DailyForecastProvider interface {
+	GetDailyForecast(ctx context.Context, city string, days int) ([]DailyForecast, error)
+}
+
+// weatherBackend — провайдер со своим circuit breaker.
+type weatherBackend struct {
+	provider WeatherProvider
+	cb       *gobreaker.CircuitBreaker
+}
+
+// WeatherService опрашивает провайдеров по порядку и переключается на следующий,
+// если текущий вернул ошибку или его circuit breaker разомкнут.
+type WeatherService struct {
+	backends []weatherBackend
+	logger   *zap.Logger
+}
+
+// NewWeatherService создаёт сервис погоды с failover между провайдерами (в порядке приоритета).
+func NewWeatherService(logger *zap.Logger, providers ...WeatherProvider) *WeatherService {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+
+	backends := make([]weatherBackend, 0, len(providers))
+	for _, p := range providers {
+		backends = append(backends, weatherBackend{
+			provider: p,
+			cb: gobreaker.NewCircuitBreaker(gobreaker.Settings{
+				Name:        "Weather:" + p.Name(),
+				MaxRequests: 1,
+				Interval:    time.Minute,
+				Timeout:     time.Minute,
+				ReadyToTrip: func(counts gobreaker.Counts) bool {
+					return counts.ConsecutiveFailures > 2
+				},
+				// Неизвестный город — корректный ответ провайдера, а отмена запроса клиентом — не его вина
+				IsSuccessful: func(err error) bool {
+					return err == nil || errors.Is(err, ErrCityNotFound) || errors.Is(err, context.Canceled)
+				},
+				OnStateChange: func(name string, from gobreaker.State, to gobreaker.State) {
+					logger.Info("circuit breaker state changed",
+						zap.String("name", name),
+						zap.String("from", from.String()),
+						zap.String("to", to.String()))
+				},
+			}),
+		})
+	}
+
+	return &WeatherService{
+		backends: backends,
+		logger:   logger,
+	}
+}
+
+// Name реализует WeatherProvider: сервис сам может быть провайдером.
+func (s *WeatherService) Name() string {
+	return "failover"
+}
+
+// GetWeather возвращает погоду от первого ответившего провайдера.
+// ErrCityNotFound возвращается, если хотя бы один провайдер не нашёл город, а остальные не ответили.
+func (s *WeatherService) GetWeather(ctx context.Context, city string) (*domain.ExtendedWeatherData, error) {
+	return failover(ctx, s, s.backends, zap.String("city", city), func(p WeatherProvider) (*domain.ExtendedWeatherData, error) {
+		return p.GetWeather(ctx, city)
+	})
+}
+
+// GetWeatherByCoords возвращает погоду по координатам от первого ответившего провайдера.
+func (s *WeatherService) GetWeatherByCoords(ctx context.Context, coords domain.Coordinates) (*domain.ExtendedWeatherData, error) {
+	return failover(ctx, s, s.backends, zap.Stringer("coords", coords), func(p WeatherProvider) (*domain.ExtendedWeatherData, error) {
+		return p.GetWeatherByCoords(ctx, coords)
+	})
+}
+
+// GetDailyForecast возвращает прогноз по дням от первого ответившего провайдера из тех,
+// что умеют DailyForecastProvider. Если таких нет — ErrNoWeatherProvider.
+func (s *WeatherService) GetDailyForecast(ctx context.Context, city string, days int) ([]DailyForecast, error) {
+	var backends []weatherBackend
+	for _, b := range s.backends {
+		if _, ok := b.provider.(DailyForecastProvider); ok {
+			backends = append(backends, b)
+		}
+	}
+	return failover(ctx, s, backends, zap.String("city", city), func(p WeatherProvider) ([]DailyForecast, error) {
+		return p.(DailyForecastProvider).GetDailyForecast(ctx, city, days)
+	})
+}
+
+func failover[T any](
+	ctx context.Context,
+	s *WeatherService,
+	backends []weatherBackend,
+	query zap.Field,
+	get func(p WeatherProvider) (T, error),
+) (T, error) {
+	var (
+		zero     T
+		lastErr  error
+		notFound bool
+	)
+
+	for _, b := range backends {
+		if err := ctx.Err(); err != nil {
+			return zero, err
+		}
+
+		res, err := b.cb.Execute(func() (interface{}, error) {
+			return get(b.provider)
+		})
+		if err == nil {
+			return res.(T), nil
+		}
+
+		switch {
+		case errors.Is(err, ErrCityNotFound):
+			// Геокодеры у провайдеров разные — следующий может знать город
+			notFound = true
+		case errors.Is(err, gobreaker.ErrOpenState), errors.Is(err, gobreaker.ErrTooManyRequests):
+			s.logger.Debug("weather provider skipped: circuit breaker open",
+				zap.String("provider", b.provider.Name()))
+		default:
+			s.logger.Warn("weather provider failed, trying next",
+				zap.String("provider", b.provider.Name()),
+				query,
+				zap.Error(err))
+		}
+		lastErr = err
+	}
+
+	if notFound {
+		return zero, ErrCityNotFound
+	}
+	if lastErr == nil {
+		return zero, ErrNoWeatherProvider
+	}
+	return zero, fmt.Errorf("%w: %v", ErrNoWeatherProvider, lastErr)
+}
+
+// HealthCheck реализует интерфейс health.Checker: сервис жив, пока есть хотя бы один
+// настроенный провайдер с неразомкнутым circuit breaker.
+func (s *WeatherService) HealthCheck() error {
+	var problems []error
+	for _, b := range s.backends {
+		if b.cb.State() == gobreaker.StateOpen {
+			problems = append(problems, fmt.Errorf("%s: circuit breaker open", b.provider.Name()))
+			continue
+		}
+		if hc, ok := b.provider.(interface{ HealthCheck() error }); ok {
+			if err := hc.HealthCheck(); err != nil {
+				problems = append(problems, fmt.Errorf("%s: %w", b.provider.Name(), err))
+				continue
+			}
+		}
+		return nil
+	}
+	if len(problems) == 0 {
+		return ErrNoWeatherProvider
+	}
+	return fmt.Errorf("%w: %v", ErrNoWeatherProvider, errors.Join(problems...))
+}
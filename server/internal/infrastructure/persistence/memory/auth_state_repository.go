@@ -0,0 +1,156 @@
+// Package memory — хранилища в памяти процесса для тестов и запуска без БД.
+// Состояние не переживает рестарт и не видно другим репликам.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// AuthStateRepository — AuthStateRepository в памяти процесса.
+type AuthStateRepository struct {
+	mu         sync.Mutex
+	tokens     map[string]domain.AuthToken
+	rateLimits map[string]time.Time // ключ -> когда снова можно
+	failures   map[string]loginFailures
+	lockouts   map[string]time.Time // ключ -> до какого момента
+}
+
+type loginFailures struct {
+	attempts  []time.Time
+	expiresAt time.Time
+}
+
+var _ repositories.AuthStateRepository = (*AuthStateRepository)(nil)
+
+// NewAuthStateRepository creates an in-memory auth state repository.
+func NewAuthStateRepository() *AuthStateRepository {
+	return &AuthStateRepository{
+		tokens:     make(map[string]domain.AuthToken),
+		rateLimits: make(map[string]time.Time),
+		failures:   make(map[string]loginFailures),
+		lockouts:   make(map[string]time.Time),
+	}
+}
+
+func (r *AuthStateRepository) SaveToken(ctx context.Context, token domain.AuthToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[token.Hash] = token
+	return nil
+}
+
+func (r *AuthStateRepository) ConsumeToken(ctx context.Context, hash string, kinds []string) (*domain.AuthToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[hash]
+	if !ok {
+		return nil, nil
+	}
+	for _, kind := range kinds {
+		if token.Kind == kind {
+			delete(r.tokens, hash)
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *AuthStateRepository) AllowAction(ctx context.Context, key string, window time.Duration, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if until, ok := r.rateLimits[key]; ok && until.After(now) {
+		return false, nil
+	}
+	r.rateLimits[key] = now.Add(window)
+	return true, nil
+}
+
+func (r *AuthStateRepository) LockedUntil(ctx context.Context, keys []string, now time.Time) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var until time.Time
+	for _, key := range keys {
+		if t, ok := r.lockouts[key]; ok && t.After(now) && t.After(until) {
+			until = t
+		}
+	}
+	return until, nil
+}
+
+func (r *AuthStateRepository) RecordLoginFailure(
+	ctx context.Context,
+	key string,
+	limit int,
+	window, lockFor time.Duration,
+	now time.Time,
+) (time.Time, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	attempts := []time.Time{now}
+	for _, at := range r.failures[key].attempts {
+		if at.After(now.Add(-window)) {
+			attempts = append(attempts, at)
+		}
+	}
+	if len(attempts) < limit {
+		r.failures[key] = loginFailures{attempts: attempts, expiresAt: now.Add(window)}
+		return time.Time{}, nil
+	}
+
+	delete(r.failures, key)
+	until := now.Add(lockFor)
+	if current := r.lockouts[key]; current.After(until) {
+		until = current
+	}
+	r.lockouts[key] = until
+	return until, nil
+}
+
+func (r *AuthStateRepository) ResetLoginFailures(ctx context.Context, keys []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, key := range keys {
+		delete(r.failures, key)
+	}
+	return nil
+}
+
+func (r *AuthStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for hash, token := range r.tokens {
+		if token.ExpiresAt.Before(now) {
+			delete(r.tokens, hash)
+			deleted++
+		}
+	}
+	for key, until := range r.rateLimits {
+		if until.Before(now) {
+			delete(r.rateLimits, key)
+			deleted++
+		}
+	}
+	for key, f := range r.failures {
+		if f.expiresAt.Before(now) {
+			delete(r.failures, key)
+			deleted++
+		}
+	}
+	for key, until := range r.lockouts {
+		if until.Before(now) {
+			delete(r.lockouts, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+type memorySession struct {
+	domain.Session
+	revoked bool
+}
+
+type memoryRefreshToken struct {
+	sessionID int64
+	used      bool
+}
+
+// SessionRepository — SessionRepository в памяти процесса.
+type SessionRepository struct {
+	mu       sync.Mutex
+	nextID   int64
+	sessions map[int64]*memorySession
+	tokens   map[string]*memoryRefreshToken
+}
+
+var _ repositories.SessionRepository = (*SessionRepository)(nil)
+
+// NewSessionRepository creates an in-memory session repository.
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{
+		sessions: make(map[int64]*memorySession),
+		tokens:   make(map[string]*memoryRefreshToken),
+	}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session domain.Session, tokenHash string) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID++
+	session.ID = r.nextID
+	session.LastUsedAt = session.CreatedAt
+	r.sessions[session.ID] = &memorySession{Session: session}
+	r.tokens[tokenHash] = &memoryRefreshToken{sessionID: session.ID}
+	return &session, nil
+}
+
+func (r *SessionRepository) Rotate(
+	ctx context.Context,
+	oldHash, newHash string,
+	client domain.SessionClient,
+	now, expiresAt time.Time,
+) (*domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[oldHash]
+	if !ok {
+		return nil, repositories.ErrRefreshTokenInvalid
+	}
+	session := r.sessions[token.sessionID]
+	if session == nil || session.revoked || !session.ExpiresAt.After(now) {
+		return nil, repositories.ErrRefreshTokenInvalid
+	}
+	if token.used {
+		session.revoked = true
+		return nil, repositories.ErrRefreshTokenReused
+	}
+
+	token.used = true
+	r.tokens[newHash] = &memoryRefreshToken{sessionID: session.ID}
+	session.Device = client.Device
+	session.IP = client.IP
+	session.LastUsedAt = now
+	session.ExpiresAt = expiresAt
+
+	result := session.Session
+	return &result, nil
+}
+
+func (r *SessionRepository) RevokeByToken(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if token, ok := r.tokens[tokenHash]; ok {
+		if session := r.sessions[token.sessionID]; session != nil {
+			session.revoked = true
+		}
+	}
+	return nil
+}
+
+func (r *SessionRepository) List(ctx context.Context, userID int, now time.Time) ([]domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sessions := []domain.Session{}
+	for _, s := range r.sessions {
+		if s.UserID == userID && !s.revoked && s.ExpiresAt.After(now) {
+			sessions = append(sessions, s.Session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastUsedAt.Equal(sessions[j].LastUsedAt) {
+			return sessions[i].LastUsedAt.After(sessions[j].LastUsedAt)
+		}
+		return sessions[i].ID > sessions[j].ID
+	})
+	return sessions, nil
+}
+
+func (r *SessionRepository) Revoke(ctx context.Context, userID int, sessionID int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.sessions[sessionID]
+	if !ok || s.UserID != userID || s.revoked || !s.ExpiresAt.After(time.Now()) {
+		return false, nil
+	}
+	s.revoked = true
+	return true, nil
+}
+
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	for id, s := range r.sessions {
+		if s.revoked || s.ExpiresAt.Before(now) {
+			delete(r.sessions, id)
+			deleted++
+		}
+	}
+	for hash, token := range r.tokens {
+		if _, ok := r.sessions[token.sessionID]; !ok {
+			delete(r.tokens, hash)
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// TwoFactorRepository — TwoFactorRepository в памяти процесса.
+type TwoFactorRepository struct {
+	mu       sync.Mutex
+	settings map[int]domain.TwoFactor
+	codes    map[int]map[string]bool // пользователь -> хэш -> использован
+}
+
+var _ repositories.TwoFactorRepository = (*TwoFactorRepository)(nil)
+
+// NewTwoFactorRepository creates an in-memory two-factor repository.
+func NewTwoFactorRepository() *TwoFactorRepository {
+	return &TwoFactorRepository{
+		settings: make(map[int]domain.TwoFactor),
+		codes:    make(map[int]map[string]bool),
+	}
+}
+
+func (r *TwoFactorRepository) Get(ctx context.Context, userID int) (*domain.TwoFactor, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.settings[userID]
+	if !ok {
+		return nil, nil
+	}
+	for _, used := range r.codes[userID] {
+		if !used {
+			t.RecoveryCodesLeft++
+		}
+	}
+	return &t, nil
+}
+
+func (r *TwoFactorRepository) SavePending(ctx context.Context, userID int, secret string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if t, ok := r.settings[userID]; ok && t.EnabledAt != nil {
+		return false, nil
+	}
+	r.settings[userID] = domain.TwoFactor{UserID: userID, Secret: secret}
+	return true, nil
+}
+
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID int, step int64, recoveryHashes []string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.settings[userID]
+	if !ok || t.EnabledAt != nil {
+		return false, nil
+	}
+	t.EnabledAt = &at
+	t.LastUsedStep = step
+	r.settings[userID] = t
+
+	codes := make(map[string]bool, len(recoveryHashes))
+	for _, h := range recoveryHashes {
+		codes[h] = false
+	}
+	r.codes[userID] = codes
+	return true, nil
+}
+
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.settings[userID]
+	if !ok || t.EnabledAt == nil || t.LastUsedStep >= step {
+		return false, nil
+	}
+	t.LastUsedStep = step
+	r.settings[userID] = t
+	return true, nil
+}
+
+func (r *TwoFactorRepository) RecoveryCodeHashes(ctx context.Context, userID int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var hashes []string
+	for h, used := range r.codes[userID] {
+		if !used {
+			hashes = append(hashes, h)
+		}
+	}
+	return hashes, nil
+}
+
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	used, ok := r.codes[userID][hash]
+	if !ok || used {
+		return false, nil
+	}
+	r.codes[userID][hash] = true
+	return true, nil
+}
+
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.settings, userID)
+	delete(r.codes, userID)
+	return nil
+}
@@ -0,0 +1,199 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// AchievementRepository implements the AchievementRepository interface for PostgreSQL.
+type AchievementRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewAchievementRepository creates a new achievement repository.
+func NewAchievementRepository(db *DB, logger *zap.Logger) repositories.AchievementRepository {
+	return &AchievementRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListDefinitions returns active achievements that have a rule.
+func (r *AchievementRepository) ListDefinitions(ctx context.Context) ([]domain.AchievementDefinition, error) {
+	query := `
+		SELECT id, code, name, COALESCE(description, ''), COALESCE(icon, ''), rule
+		FROM achievements
+		WHERE active AND rule IS NOT NULL
+		ORDER BY id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query achievements")
+	}
+	defer rows.Close()
+
+	var definitions []domain.AchievementDefinition
+	for rows.Next() {
+		var (
+			d    domain.AchievementDefinition
+			rule []byte
+		)
+		if err := rows.Scan(&d.ID, &d.Code, &d.Name, &d.Description, &d.Icon, &rule); err != nil {
+			return nil, errors.Wrap(err, "failed to scan achievement")
+		}
+		if err := json.Unmarshal(rule, &d.Rule); err != nil {
+			r.logger.Warn("Skipping achievement with invalid rule", zap.String("code", d.Code), zap.Error(err))
+			continue
+		}
+		definitions = append(definitions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating achievements")
+	}
+	return definitions, nil
+}
+
+// UserStats computes rule stats from the source tables.
+func (r *AchievementRepository) UserStats(ctx context.Context, userID int) (map[string]float64, error) {
+	today := utcDate(time.Now())
+
+	// Рекомендации дайджеста пользователь не запрашивал — они не считаются
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM recommendations WHERE user_id = $1 AND origin = 'user'),
+			(SELECT COUNT(*) FROM user_ratings WHERE user_id = $1),
+			(SELECT COUNT(*) FROM user_favorites WHERE user_id = $1),
+			(SELECT COUNT(*) FROM wardrobe_items WHERE user_id = $1),
+			EXISTS (SELECT 1 FROM recommendations WHERE user_id = $1 AND origin = 'user' AND created_at >= $2)
+				OR EXISTS (SELECT 1 FROM user_ratings WHERE user_id = $1 AND created_at >= $2)
+	`
+
+	var (
+		recommendations, ratings, favorites, wardrobeItems int64
+		checkedInToday                                     bool
+	)
+	err := r.db.pool.QueryRow(ctx, query, userID, today).Scan(&recommendations, &ratings, &favorites, &wardrobeItems, &checkedInToday)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to compute user stats")
+	}
+
+	// Серию ведёт ChallengeService по событиям из outbox, а правила проверяются сразу после
+	// запроса — сегодняшний заход мог ещё не дойти до user_streaks
+	var streak domain.Streak
+	err = r.db.pool.QueryRow(ctx, `
+		SELECT current_streak, longest_streak, last_check_in, freeze_tokens
+		FROM user_streaks
+		WHERE user_id = $1
+	`, userID).Scan(&streak.CurrentStreak, &streak.LongestStreak, &streak.LastCheckIn, &streak.FreezeTokens)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, errors.Wrap(err, "failed to get streak")
+	}
+	if checkedInToday {
+		streak.CheckIn(today)
+	}
+	streak.AsOf(today)
+
+	return map[string]float64{
+		domain.StatRecommendations: float64(recommendations),
+		domain.StatRatings:         float64(ratings),
+		domain.StatFavorites:       float64(favorites),
+		domain.StatWardrobeItems:   float64(wardrobeItems),
+		domain.StatStreakDays:      float64(streak.CurrentStreak),
+	}, nil
+}
+
+// ApplyEvent records the event, advances progress and unlocks reached achievements in one transaction.
+func (r *AchievementRepository) ApplyEvent(
+	ctx context.Context,
+	userID int,
+	eventKey string,
+	steps []domain.AchievementStep,
+) ([]domain.Achievement, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO achievement_events (event_key, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (event_key) DO NOTHING
+	`, eventKey, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to record achievement event")
+	}
+	if tag.RowsAffected() == 0 {
+		// Событие уже учтено
+		return nil, nil
+	}
+
+	var unlocked []domain.Achievement
+	for _, step := range steps {
+		var progress int
+		err := tx.QueryRow(ctx, `
+			INSERT INTO user_achievement_progress (user_id, achievement_id, progress, updated_at)
+			VALUES ($1, $2, $3, NOW())
+			ON CONFLICT (user_id, achievement_id) DO UPDATE
+			SET progress = CASE WHEN $4 THEN user_achievement_progress.progress + EXCLUDED.progress
+			                    ELSE GREATEST(user_achievement_progress.progress, EXCLUDED.progress) END,
+			    updated_at = NOW()
+			RETURNING progress
+		`, userID, step.AchievementID, step.Progress, step.Increment).Scan(&progress)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to update achievement progress")
+		}
+		if progress < step.Target {
+			continue
+		}
+
+		rows, err := tx.Query(ctx, `
+			WITH inserted AS (
+				INSERT INTO user_achievements (user_id, achievement_id, unlocked_at)
+				VALUES ($1, $2, NOW())
+				ON CONFLICT (user_id, achievement_id) DO NOTHING
+				RETURNING achievement_id
+			)
+			SELECT a.id, a.code, a.name, COALESCE(a.description, ''), COALESCE(a.icon, ''), a.created_at
+			FROM achievements a
+			JOIN inserted ON inserted.achievement_id = a.id
+		`, userID, step.AchievementID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to unlock achievement")
+		}
+		for rows.Next() {
+			var a domain.Achievement
+			if err := rows.Scan(&a.ID, &a.Code, &a.Name, &a.Description, &a.Icon, &a.CreatedAt); err != nil {
+				rows.Close()
+				return nil, errors.Wrap(err, "failed to scan unlocked achievement")
+			}
+			unlocked = append(unlocked, a)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, errors.Wrap(err, "failed to unlock achievement")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+
+	for _, a := range unlocked {
+		r.logger.Info("🏆 Achievement unlocked",
+			zap.Int64("user_id", int64(userID)),
+			zap.String("achievement_code", a.Code),
+		)
+	}
+	return unlocked, nil
+}
@@ -0,0 +1,178 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// AuthStateRepository implements the AuthStateRepository interface for PostgreSQL.
+type AuthStateRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewAuthStateRepository creates a new auth state repository.
+func NewAuthStateRepository(db *DB, logger *zap.Logger) repositories.AuthStateRepository {
+	return &AuthStateRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveToken stores a one-time token.
+func (r *AuthStateRepository) SaveToken(ctx context.Context, token domain.AuthToken) error {
+	_, err := r.db.pool.Exec(ctx, `
+		INSERT INTO auth_tokens (token_hash, kind, user_id, expires_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (token_hash) DO UPDATE
+		SET kind = EXCLUDED.kind,
+		    user_id = EXCLUDED.user_id,
+		    expires_at = EXCLUDED.expires_at,
+		    created_at = NOW()
+	`, token.Hash, token.Kind, token.UserID, token.ExpiresAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to save auth token")
+	}
+	return nil
+}
+
+// ConsumeToken deletes and returns the token; DELETE makes the consumption single-use across replicas.
+func (r *AuthStateRepository) ConsumeToken(ctx context.Context, hash string, kinds []string) (*domain.AuthToken, error) {
+	token := domain.AuthToken{Hash: hash}
+	err := r.db.pool.QueryRow(ctx, `
+		DELETE FROM auth_tokens
+		WHERE token_hash = $1 AND kind = ANY($2)
+		RETURNING kind, user_id, expires_at
+	`, hash, kinds).Scan(&token.Kind, &token.UserID, &token.ExpiresAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to consume auth token")
+	}
+	return &token, nil
+}
+
+// AllowAction takes the key's slot if the previous one has expired; the upsert is atomic across replicas.
+func (r *AuthStateRepository) AllowAction(ctx context.Context, key string, window time.Duration, now time.Time) (bool, error) {
+	var taken string
+	err := r.db.pool.QueryRow(ctx, `
+		INSERT INTO auth_rate_limits (key, last_at, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET last_at = EXCLUDED.last_at,
+		    expires_at = EXCLUDED.expires_at
+		WHERE auth_rate_limits.expires_at <= EXCLUDED.last_at
+		RETURNING key
+	`, key, now, now.Add(window)).Scan(&taken)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to check rate limit")
+	}
+	return true, nil
+}
+
+// LockedUntil returns the latest active lockout of the keys.
+func (r *AuthStateRepository) LockedUntil(ctx context.Context, keys []string, now time.Time) (time.Time, error) {
+	var until *time.Time
+	err := r.db.pool.QueryRow(ctx, `
+		SELECT MAX(expires_at)
+		FROM auth_lockouts
+		WHERE key = ANY($1) AND expires_at > $2
+	`, keys, now).Scan(&until)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to check login lockout")
+	}
+	if until == nil {
+		return time.Time{}, nil
+	}
+	return *until, nil
+}
+
+// RecordLoginFailure adds a failed attempt; the upsert locks the key's row, so replicas count in turn.
+func (r *AuthStateRepository) RecordLoginFailure(
+	ctx context.Context,
+	key string,
+	limit int,
+	window, lockFor time.Duration,
+	now time.Time,
+) (time.Time, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Попытки старше окна выпадают при каждой новой — окно скользящее
+	var attempts int
+	err = tx.QueryRow(ctx, `
+		INSERT INTO auth_login_failures (key, attempts, expires_at)
+		VALUES ($1, ARRAY[$2::timestamptz], $3)
+		ON CONFLICT (key) DO UPDATE
+		SET attempts = ARRAY(
+		        SELECT a FROM unnest(auth_login_failures.attempts) AS a WHERE a > $4
+		    ) || $2::timestamptz,
+		    expires_at = EXCLUDED.expires_at
+		RETURNING cardinality(attempts)
+	`, key, now, now.Add(window), now.Add(-window)).Scan(&attempts)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to record login failure")
+	}
+	if attempts < limit {
+		if err := tx.Commit(ctx); err != nil {
+			return time.Time{}, errors.Wrap(err, "commit tx")
+		}
+		return time.Time{}, nil
+	}
+
+	var until time.Time
+	err = tx.QueryRow(ctx, `
+		INSERT INTO auth_lockouts (key, locked_at, expires_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (key) DO UPDATE
+		SET locked_at = EXCLUDED.locked_at,
+		    expires_at = GREATEST(auth_lockouts.expires_at, EXCLUDED.expires_at)
+		RETURNING expires_at
+	`, key, now, now.Add(lockFor)).Scan(&until)
+	if err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to lock login")
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM auth_login_failures WHERE key = $1`, key); err != nil {
+		return time.Time{}, errors.Wrap(err, "failed to reset login failures")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return time.Time{}, errors.Wrap(err, "commit tx")
+	}
+	return until, nil
+}
+
+// ResetLoginFailures forgets failed attempts of the keys.
+func (r *AuthStateRepository) ResetLoginFailures(ctx context.Context, keys []string) error {
+	if _, err := r.db.pool.Exec(ctx, `DELETE FROM auth_login_failures WHERE key = ANY($1)`, keys); err != nil {
+		return errors.Wrap(err, "failed to reset login failures")
+	}
+	return nil
+}
+
+// DeleteExpired removes expired tokens, rate limits, failed attempts and lockouts.
+func (r *AuthStateRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	for _, table := range []string{"auth_tokens", "auth_rate_limits", "auth_login_failures", "auth_lockouts"} {
+		tag, err := r.db.pool.Exec(ctx, `DELETE FROM `+table+` WHERE expires_at < $1`, now)
+		if err != nil {
+			return deleted, errors.Wrapf(err, "failed to clean up %s", table)
+		}
+		deleted += tag.RowsAffected()
+	}
+	return deleted, nil
+}
@@ -0,0 +1,340 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// challengeColumns — поля испытания в порядке scanChallenge.
+const challengeColumns = `c.id, c.code, c.name, COALESCE(c.description, ''), COALESCE(c.icon, ''),
+	c.rule, c.month, c.reward_freezes, c.reward_achievement_id`
+
+// ChallengeRepository implements the ChallengeRepository interface for PostgreSQL.
+type ChallengeRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewChallengeRepository creates a new streak and challenge repository.
+func NewChallengeRepository(db *DB, logger *zap.Logger) repositories.ChallengeRepository {
+	return &ChallengeRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// ListChallenges returns active challenges of the month.
+func (r *ChallengeRepository) ListChallenges(ctx context.Context, period time.Time) ([]domain.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges c
+		WHERE c.active AND (c.month IS NULL OR c.month = $1)
+		ORDER BY c.id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, period)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query challenges")
+	}
+	defer rows.Close()
+
+	var challenges []domain.Challenge
+	for rows.Next() {
+		c, err := r.scanChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		if c != nil {
+			challenges = append(challenges, *c)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating challenges")
+	}
+	return challenges, nil
+}
+
+// ListProgress returns active challenges of the month with the user's progress.
+func (r *ChallengeRepository) ListProgress(ctx context.Context, userID int, period time.Time) ([]domain.ChallengeProgress, error) {
+	query := `
+		SELECT ` + challengeColumns + `, COALESCE(p.progress, 0), p.completed_at
+		FROM challenges c
+		LEFT JOIN user_challenge_progress p
+		       ON p.challenge_id = c.id AND p.user_id = $1 AND p.period = $2
+		WHERE c.active AND (c.month IS NULL OR c.month = $2)
+		ORDER BY c.id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, userID, period)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query challenge progress")
+	}
+	defer rows.Close()
+
+	var progress []domain.ChallengeProgress
+	for rows.Next() {
+		var (
+			p    domain.ChallengeProgress
+			rule []byte
+		)
+		err := rows.Scan(
+			&p.ID, &p.Code, &p.Name, &p.Description, &p.Icon,
+			&rule, &p.Month, &p.RewardFreezes, &p.RewardAchievementID,
+			&p.Progress, &p.CompletedAt,
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan challenge progress")
+		}
+		if err := json.Unmarshal(rule, &p.Rule); err != nil {
+			r.logger.Warn("Skipping challenge with invalid rule", zap.String("code", p.Code), zap.Error(err))
+			continue
+		}
+		p.Target = max(p.Rule.Count, 1)
+		progress = append(progress, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating challenge progress")
+	}
+	return progress, nil
+}
+
+// GetStreak returns the user's check-in streak.
+func (r *ChallengeRepository) GetStreak(ctx context.Context, userID int) (*domain.Streak, error) {
+	query := `
+		SELECT current_streak, longest_streak, last_check_in, freeze_tokens, freezes_used
+		FROM user_streaks
+		WHERE user_id = $1
+	`
+
+	var s domain.Streak
+	err := r.db.pool.QueryRow(ctx, query, userID).Scan(
+		&s.CurrentStreak, &s.LongestStreak, &s.LastCheckIn, &s.FreezeTokens, &s.FreezesUsed,
+	)
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, errors.Wrap(err, "failed to get streak")
+	}
+	return &s, nil
+}
+
+// ApplyEvent records the event, checks in, advances challenges and grants rewards in one transaction.
+func (r *ChallengeRepository) ApplyEvent(ctx context.Context, event domain.GamificationEvent) (*domain.GamificationResult, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO gamification_events (event_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (event_id) DO NOTHING
+	`, event.ID, event.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to record gamification event")
+	}
+	if tag.RowsAffected() == 0 {
+		// Событие уже учтено
+		return nil, nil
+	}
+
+	// Серия блокируется: события одного пользователя применяются по очереди
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_streaks (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO NOTHING
+	`, event.UserID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create streak")
+	}
+
+	result := &domain.GamificationResult{}
+	streak := &result.Streak
+	err = tx.QueryRow(ctx, `
+		SELECT current_streak, longest_streak, last_check_in, freeze_tokens, freezes_used
+		FROM user_streaks
+		WHERE user_id = $1
+		FOR UPDATE
+	`, event.UserID).Scan(
+		&streak.CurrentStreak, &streak.LongestStreak, &streak.LastCheckIn, &streak.FreezeTokens, &streak.FreezesUsed,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to lock streak")
+	}
+
+	newDay := event.CheckIn && streak.CheckIn(utcDate(event.At))
+	period := domain.MonthStart(event.At)
+
+	for _, step := range event.Steps {
+		if step.OnCheckIn && !newDay {
+			continue
+		}
+
+		increment := 1
+		if len(step.Values) > 0 {
+			// Засчитываются только значения, впервые встреченные в этом месяце (а с NewOnly — вообще)
+			err := tx.QueryRow(ctx, `
+				WITH inserted AS (
+					INSERT INTO user_challenge_values (user_id, challenge_id, value, period)
+					SELECT $1, $2, v, $3 FROM unnest($4::text[]) AS v
+					ON CONFLICT DO NOTHING
+					RETURNING value
+				)
+				SELECT COUNT(*)
+				FROM inserted i
+				WHERE NOT $5 OR NOT EXISTS (
+					SELECT 1 FROM user_challenge_values p
+					WHERE p.user_id = $1 AND p.challenge_id = $2 AND p.value = i.value AND p.period < $3
+				)
+			`, event.UserID, step.ChallengeID, period, step.Values, step.NewOnly).Scan(&increment)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to record challenge values")
+			}
+			if increment == 0 {
+				continue
+			}
+		}
+
+		var (
+			progress  int
+			completed bool
+		)
+		err := tx.QueryRow(ctx, `
+			INSERT INTO user_challenge_progress (user_id, challenge_id, period, progress, updated_at)
+			VALUES ($1, $2, $3, $4, NOW())
+			ON CONFLICT (user_id, challenge_id, period) DO UPDATE
+			SET progress = user_challenge_progress.progress + EXCLUDED.progress,
+			    updated_at = NOW()
+			RETURNING progress, completed_at IS NOT NULL
+		`, event.UserID, step.ChallengeID, period, increment).Scan(&progress, &completed)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to update challenge progress")
+		}
+		if completed || progress < step.Target {
+			continue
+		}
+
+		challenge, unlocked, err := r.completeChallenge(ctx, tx, event.UserID, step.ChallengeID, period, streak)
+		if err != nil {
+			return nil, err
+		}
+		result.Completed = append(result.Completed, *challenge)
+		result.Unlocked = append(result.Unlocked, unlocked...)
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_streaks
+		SET current_streak = $2,
+		    longest_streak = $3,
+		    last_check_in = $4,
+		    freeze_tokens = $5,
+		    freezes_used = $6
+		WHERE user_id = $1
+	`, event.UserID, streak.CurrentStreak, streak.LongestStreak, streak.LastCheckIn, streak.FreezeTokens, streak.FreezesUsed)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update streak")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+
+	for _, c := range result.Completed {
+		r.logger.Info("🏁 Challenge completed",
+			zap.Int("user_id", event.UserID),
+			zap.String("challenge_code", c.Code),
+		)
+	}
+	return result, nil
+}
+
+// completeChallenge marks the challenge completed and grants its rewards.
+func (r *ChallengeRepository) completeChallenge(
+	ctx context.Context,
+	tx pgx.Tx,
+	userID, challengeID int,
+	period time.Time,
+	streak *domain.Streak,
+) (*domain.Challenge, []domain.Achievement, error) {
+	_, err := tx.Exec(ctx, `
+		UPDATE user_challenge_progress
+		SET completed_at = NOW()
+		WHERE user_id = $1 AND challenge_id = $2 AND period = $3
+	`, userID, challengeID, period)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to complete challenge")
+	}
+
+	row := tx.QueryRow(ctx, `SELECT `+challengeColumns+` FROM challenges c WHERE c.id = $1`, challengeID)
+	challenge, err := r.scanChallenge(row)
+	if err != nil {
+		return nil, nil, err
+	}
+	if challenge == nil {
+		challenge = &domain.Challenge{ID: challengeID}
+	}
+
+	// Заморозки из награды тоже не копятся сверх MaxStreakFreezes
+	streak.FreezeTokens = min(streak.FreezeTokens+challenge.RewardFreezes, domain.MaxStreakFreezes)
+
+	if challenge.RewardAchievementID == nil {
+		return challenge, nil, nil
+	}
+
+	rows, err := tx.Query(ctx, `
+		WITH inserted AS (
+			INSERT INTO user_achievements (user_id, achievement_id, unlocked_at)
+			VALUES ($1, $2, NOW())
+			ON CONFLICT (user_id, achievement_id) DO NOTHING
+			RETURNING achievement_id
+		)
+		SELECT a.id, a.code, a.name, COALESCE(a.description, ''), COALESCE(a.icon, ''), a.created_at
+		FROM achievements a
+		JOIN inserted ON inserted.achievement_id = a.id
+	`, userID, *challenge.RewardAchievementID)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unlock reward achievement")
+	}
+	defer rows.Close()
+
+	var unlocked []domain.Achievement
+	for rows.Next() {
+		var a domain.Achievement
+		if err := rows.Scan(&a.ID, &a.Code, &a.Name, &a.Description, &a.Icon, &a.CreatedAt); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to scan reward achievement")
+		}
+		unlocked = append(unlocked, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to unlock reward achievement")
+	}
+	return challenge, unlocked, nil
+}
+
+// scanChallenge reads challengeColumns; a challenge with an unreadable rule is skipped (nil).
+func (r *ChallengeRepository) scanChallenge(row pgx.Row) (*domain.Challenge, error) {
+	var (
+		c    domain.Challenge
+		rule []byte
+	)
+	err := row.Scan(&c.ID, &c.Code, &c.Name, &c.Description, &c.Icon, &rule, &c.Month, &c.RewardFreezes, &c.RewardAchievementID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to scan challenge")
+	}
+	if err := json.Unmarshal(rule, &c.Rule); err != nil {
+		r.logger.Warn("Skipping challenge with invalid rule", zap.String("code", c.Code), zap.Error(err))
+		return nil, nil
+	}
+	c.Target = max(c.Rule.Count, 1)
+	return &c, nil
+}
@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"outfitstyle/server/internal/core/domain"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ClimateNormalRepo struct {
+	db *pgxpool.Pool
+}
+
+func NewClimateNormalRepo(db *pgxpool.Pool) *ClimateNormalRepo {
+	return &ClimateNormalRepo{db: db}
+}
+
+func (r *ClimateNormalRepo) UpsertMany(ctx context.Context, normals []domain.ClimateNormal) error {
+	const q = `
+INSERT INTO climate_normals
+  (location, month, source, temp_mean, temp_min, temp_max, precip_mm, precip_days, observations, updated_at)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,NOW())
+ON CONFLICT (location, month, source) DO UPDATE SET
+  temp_mean    = EXCLUDED.temp_mean,
+  temp_min     = EXCLUDED.temp_min,
+  temp_max     = EXCLUDED.temp_max,
+  precip_mm    = EXCLUDED.precip_mm,
+  precip_days  = EXCLUDED.precip_days,
+  observations = EXCLUDED.observations,
+  updated_at   = NOW();
+`
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin climate normals tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, n := range normals {
+		precipMM := n.PrecipMM
+		if n.Source == domain.ClimateSourceHistory {
+			// В weather_data нет сумм осадков — у истории precip_mm всегда NULL
+			precipMM = nil
+		}
+		if _, err := tx.Exec(ctx, q, n.Location, n.Month, n.Source, n.TempMean, n.TempMin, n.TempMax,
+			precipMM, n.PrecipDays, n.Observations); err != nil {
+			return fmt.Errorf("upsert climate normal %s/%d: %w", n.Location, n.Month, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+func (r *ClimateNormalRepo) Get(ctx context.Context, location string, month int) ([]domain.ClimateNormal, error) {
+	const q = `
+SELECT location, month, source, temp_mean::float8, temp_min::float8, temp_max::float8,
+       precip_mm::float8, precip_days::float8, observations
+FROM climate_normals
+WHERE location = $1 AND month = $2
+ORDER BY source`
+	rows, err := r.db.Query(ctx, q, location, month)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	normals := []domain.ClimateNormal{}
+	for rows.Next() {
+		var n domain.ClimateNormal
+		if err := rows.Scan(&n.Location, &n.Month, &n.Source, &n.TempMean, &n.TempMin, &n.TempMax,
+			&n.PrecipMM, &n.PrecipDays, &n.Observations); err != nil {
+			return nil, err
+		}
+		normals = append(normals, n)
+	}
+	return normals, rows.Err()
+}
+
+func (r *ClimateNormalRepo) AggregateHistory(ctx context.Context, minDays int) ([]domain.ClimateNormal, error) {
+	// Сначала сводим наблюдения в сутки, затем сутки — в месяц: иначе популярный город с сотней
+	// запросов в день перевесил бы остальные дни. Наблюдения приходят только по запросам
+	// пользователей и в основном днём, поэтому «минимум» суток завышен — это ожидаемо.
+	const q = `
+WITH daily AS (
+  SELECT lower(regexp_replace(btrim(location), '\s+', ' ', 'g')) AS location,
+         (timestamp AT TIME ZONE 'UTC')::date AS day,
+         AVG(temperature) AS t_mean,
+         MIN(temperature) AS t_min,
+         MAX(temperature) AS t_max,
+         BOOL_OR(COALESCE(weather_condition, '') ~* '(дожд|ливен|морос|гроз|снег|rain|drizzle|shower|thunder|snow)') AS wet
+  FROM weather_data
+  WHERE timestamp IS NOT NULL
+  GROUP BY 1, 2
+)
+SELECT location,
+       EXTRACT(MONTH FROM day)::int AS month,
+       AVG(t_mean)::float8,
+       AVG(t_min)::float8,
+       AVG(t_max)::float8,
+       AVG(CASE WHEN wet THEN 1 ELSE 0 END)::float8 AS wet_share,
+       COUNT(*)::int AS days
+FROM daily
+GROUP BY 1, 2
+HAVING COUNT(*) >= $1
+ORDER BY 1, 2`
+	rows, err := r.db.Query(ctx, q, minDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var normals []domain.ClimateNormal
+	for rows.Next() {
+		var (
+			n        domain.ClimateNormal
+			wetShare float64
+		)
+		if err := rows.Scan(&n.Location, &n.Month, &n.TempMean, &n.TempMin, &n.TempMax, &wetShare, &n.Observations); err != nil {
+			return nil, err
+		}
+		n.Source = domain.ClimateSourceHistory
+		n.TempMean = round1(n.TempMean)
+		n.TempMin = round1(n.TempMin)
+		n.TempMax = round1(n.TempMax)
+		n.PrecipDays = round1(wetShare * float64(daysInMonth(n.Month)))
+		normals = append(normals, n)
+	}
+	return normals, rows.Err()
+}
+
+func daysInMonth(month int) int {
+	// Невисокосный год: для нормы лишний день февраля не важен
+	return time.Date(2001, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -1,13 +1,17 @@
-package pg
+package postgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
-	"outfit-style-rec/server/internal/core/domain"
-	"outfit-style-rec/server/internal/core/repo"
+	"outfitstyle/server/internal/core/domain"
+	"outfitstyle/server/internal/core/repo"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -45,9 +49,94 @@ func (r *SubcategorySpecRepo) Get(ctx context.Context, category, subcategory str
 	var spec domain.SubcategorySpec
 	err := r.db.QueryRow(ctx, q, category, subcategory).Scan(&spec.Category, &spec.Subcategory, &spec.WarmthMin, &spec.TempMinReco, &spec.TempMaxReco,
 		&spec.RainOK, &spec.SnowOK, &spec.WindOK)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return spec, repo.ErrSpecNotFound
+	}
 	return spec, err
 }
 
+func (r *SubcategorySpecRepo) Create(ctx context.Context, spec domain.SubcategorySpec) error {
+	const q = `
+INSERT INTO subcategory_specs
+  (category, subcategory, warmth_min, temp_min_reco, temp_max_reco, rain_ok, snow_ok, wind_ok)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8);
+`
+	_, err := r.db.Exec(ctx, q, spec.Category, spec.Subcategory, spec.WarmthMin, spec.TempMinReco, spec.TempMaxReco,
+		spec.RainOK, spec.SnowOK, spec.WindOK)
+	if pgErrorCode(err) == uniqueViolation {
+		return repo.ErrSpecExists
+	}
+	return err
+}
+
+func (r *SubcategorySpecRepo) Update(ctx context.Context, spec domain.SubcategorySpec) error {
+	const q = `
+UPDATE subcategory_specs SET
+  warmth_min    = $3,
+  temp_min_reco = $4,
+  temp_max_reco = $5,
+  rain_ok       = $6,
+  snow_ok       = $7,
+  wind_ok       = $8
+WHERE category = $1 AND subcategory = $2;
+`
+	tag, err := r.db.Exec(ctx, q, spec.Category, spec.Subcategory, spec.WarmthMin, spec.TempMinReco, spec.TempMaxReco,
+		spec.RainOK, spec.SnowOK, spec.WindOK)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrSpecNotFound
+	}
+	return nil
+}
+
+// Delete удаляет норму. FK clothing_items → subcategory_specs объявлен с ON DELETE RESTRICT,
+// поэтому норму, на которую ссылаются вещи, БД удалить не даст — возвращаем repo.ErrSpecInUse.
+func (r *SubcategorySpecRepo) Delete(ctx context.Context, category, subcategory string) error {
+	const q = `DELETE FROM subcategory_specs WHERE category = $1 AND subcategory = $2`
+	tag, err := r.db.Exec(ctx, q, category, subcategory)
+	if pgErrorCode(err) == foreignKeyViolation {
+		return repo.ErrSpecInUse
+	}
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return repo.ErrSpecNotFound
+	}
+	return nil
+}
+
+// UpsertMany создаёт или обновляет нормы одной транзакцией (перенос словаря между окружениями).
+func (r *SubcategorySpecRepo) UpsertMany(ctx context.Context, specs []domain.SubcategorySpec) error {
+	const q = `
+INSERT INTO subcategory_specs
+  (category, subcategory, warmth_min, temp_min_reco, temp_max_reco, rain_ok, snow_ok, wind_ok)
+VALUES ($1,$2,$3,$4,$5,$6,$7,$8)
+ON CONFLICT (category, subcategory) DO UPDATE SET
+  warmth_min    = EXCLUDED.warmth_min,
+  temp_min_reco = EXCLUDED.temp_min_reco,
+  temp_max_reco = EXCLUDED.temp_max_reco,
+  rain_ok       = EXCLUDED.rain_ok,
+  snow_ok       = EXCLUDED.snow_ok,
+  wind_ok       = EXCLUDED.wind_ok;
+`
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin specs tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	for _, spec := range specs {
+		if _, err := tx.Exec(ctx, q, spec.Category, spec.Subcategory, spec.WarmthMin, spec.TempMinReco, spec.TempMaxReco,
+			spec.RainOK, spec.SnowOK, spec.WindOK); err != nil {
+			return fmt.Errorf("upsert spec %s/%s: %w", spec.Category, spec.Subcategory, err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
 type ClothingItemRepo struct {
 	db *pgxpool.Pool
 }
@@ -74,6 +163,7 @@ WHERE category = $1
   AND subcategory = ANY($2::text[])
   AND warmth_level >= $3
   AND $4 BETWEEN min_temp AND max_temp
+  AND NOT EXISTS (SELECT 1 FROM clothing_item_quarantine q WHERE q.item_id = clothing_items.id)
 ORDER BY warmth_level DESC, formality_level ASC, id ASC
 LIMIT $5;
 `
@@ -101,8 +191,7 @@ LIMIT $5;
 }
 
 func (r *ClothingItemRepo) BulkInsert(ctx context.Context, items []domain.ClothingItem) error {
-	// Для больших объёмов лучше COPY FROM, но даю безопасный базовый вариант.
-	// Если хочешь — дам отдельный вариант через pgx.CopyFrom для NDJSON 20k/100k.
+	// Для больших объёмов (импорт NDJSON/CSV на 20k/100k строк) использовать CopyItems.
 	const q = `
 INSERT INTO clothing_items (
   id, name, category, subcategory, gender, style, usage, season, base_colour,
@@ -114,7 +203,7 @@ INSERT INTO clothing_items (
   $17,$18,$19
 );
 `
-	b := &pgxpool.Batch{}
+	b := &pgx.Batch{}
 	for _, it := range items {
 		b.Queue(q,
 			it.ID, it.Name, it.Category, it.Subcategory, it.Gender, it.Style, it.Usage, it.Season, it.BaseColour,
@@ -134,6 +223,192 @@ INSERT INTO clothing_items (
 	return nil
 }
 
+// Stream построчно отдаёт вещи каталога, подходящие под фильтр, не загружая выборку в память.
+// Порядок по id стабилен, чтобы выгрузки одного и того же каталога совпадали.
+func (r *ClothingItemRepo) Stream(ctx context.Context, filter repo.ClothingItemFilter, fn func(domain.ClothingItem) error) error {
+	var (
+		where []string
+		args  []any
+	)
+	if len(filter.Sources) > 0 {
+		args = append(args, filter.Sources)
+		where = append(where, fmt.Sprintf("source = ANY($%d::text[])", len(args)))
+	}
+	if len(filter.Categories) > 0 {
+		args = append(args, filter.Categories)
+		where = append(where, fmt.Sprintf("category = ANY($%d::text[])", len(args)))
+	}
+	if !filter.CreatedAfter.IsZero() {
+		args = append(args, filter.CreatedAfter)
+		where = append(where, fmt.Sprintf("created_at > $%d", len(args)))
+	}
+
+	q := `
+SELECT id, name, category, subcategory, gender, style, usage, season, base_colour,
+       formality_level, warmth_level, min_temp, max_temp, materials, fit, pattern,
+       icon_emoji, source, is_owned, created_at
+FROM clothing_items`
+	if len(where) > 0 {
+		q += "\nWHERE " + strings.Join(where, "\n  AND ")
+	}
+	q += "\nORDER BY id ASC;"
+
+	rows, err := r.db.Query(ctx, q, args...)
+	if err != nil {
+		return fmt.Errorf("query clothing items: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var it domain.ClothingItem
+		if err := rows.Scan(
+			&it.ID, &it.Name, &it.Category, &it.Subcategory, &it.Gender, &it.Style, &it.Usage, &it.Season, &it.BaseColour,
+			&it.Formality, &it.Warmth, &it.MinTemp, &it.MaxTemp, &it.Materials, &it.Fit, &it.Pattern,
+			&it.IconEmoji, &it.Source, &it.IsOwned, &it.CreatedAt,
+		); err != nil {
+			return fmt.Errorf("scan clothing item: %w", err)
+		}
+		if err := fn(it); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *ClothingItemRepo) CountBySubcategory(ctx context.Context, category, subcategory string, warmthMin int16) (int64, int64, error) {
+	const q = `
+SELECT count(*), count(*) FILTER (WHERE ci.warmth_level >= $3 AND q.item_id IS NULL)
+FROM clothing_items ci
+LEFT JOIN clothing_item_quarantine q ON q.item_id = ci.id
+WHERE ci.category = $1 AND ci.subcategory = $2;
+`
+	var total, eligible int64
+	err := r.db.QueryRow(ctx, q, category, subcategory, warmthMin).Scan(&total, &eligible)
+	return total, eligible, err
+}
+
+// Quarantine убирает вещи из подбора, сохраняя причины. Повторный карантин перезаписывает причины.
+func (r *ClothingItemRepo) Quarantine(ctx context.Context, reasons map[int64][]string) (int64, error) {
+	const q = `
+INSERT INTO clothing_item_quarantine (item_id, reasons)
+VALUES ($1, $2)
+ON CONFLICT (item_id) DO UPDATE SET
+  reasons        = EXCLUDED.reasons,
+  quarantined_at = NOW();
+`
+	batch := &pgx.Batch{}
+	for id, rs := range reasons {
+		batch.Queue(q, id, rs)
+	}
+
+	br := r.db.SendBatch(ctx, batch)
+	defer br.Close()
+
+	var n int64
+	for range reasons {
+		tag, err := br.Exec()
+		if err != nil {
+			return n, fmt.Errorf("quarantine item: %w", err)
+		}
+		n += tag.RowsAffected()
+	}
+	return n, nil
+}
+
+// clothingItemCopyColumns — колонки clothing_items, которые пишет COPY (created_at берётся из DEFAULT).
+var clothingItemCopyColumns = []string{
+	"id", "name", "category", "subcategory", "gender", "style", "usage", "season", "base_colour",
+	"formality_level", "warmth_level", "min_temp", "max_temp", "materials", "fit", "pattern",
+	"icon_emoji", "source", "is_owned",
+}
+
+// CopyItems пишет пачку вещей через COPY FROM.
+// Без upsert COPY идёт прямо в clothing_items и падает целиком на первом конфликте id.
+// С upsert строки сначала копируются во временную таблицу, а затем переносятся
+// через INSERT ... ON CONFLICT (id) DO UPDATE — COPY сам по себе конфликты не разрешает.
+func (r *ClothingItemRepo) CopyItems(ctx context.Context, items []domain.ClothingItem, upsert bool) (int64, error) {
+	if len(items) == 0 {
+		return 0, nil
+	}
+
+	rows := make([][]any, 0, len(items))
+	for _, it := range items {
+		rows = append(rows, []any{
+			it.ID, it.Name, it.Category, it.Subcategory, it.Gender, it.Style, it.Usage, it.Season, it.BaseColour,
+			it.Formality, it.Warmth, it.MinTemp, it.MaxTemp, it.Materials, it.Fit, it.Pattern,
+			it.IconEmoji, it.Source, it.IsOwned,
+		})
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin copy tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	target := "clothing_items"
+	if upsert {
+		const createTmp = `
+CREATE TEMP TABLE clothing_items_import
+  (LIKE clothing_items INCLUDING DEFAULTS)
+  ON COMMIT DROP;
+`
+		if _, err := tx.Exec(ctx, createTmp); err != nil {
+			return 0, fmt.Errorf("create import table: %w", err)
+		}
+		target = "clothing_items_import"
+	}
+
+	copied, err := tx.CopyFrom(ctx, pgx.Identifier{target}, clothingItemCopyColumns, pgx.CopyFromRows(rows))
+	if err != nil {
+		return 0, fmt.Errorf("copy into %s: %w", target, err)
+	}
+
+	if upsert {
+		const merge = `
+INSERT INTO clothing_items (
+  id, name, category, subcategory, gender, style, usage, season, base_colour,
+  formality_level, warmth_level, min_temp, max_temp, materials, fit, pattern,
+  icon_emoji, source, is_owned
+)
+SELECT
+  id, name, category, subcategory, gender, style, usage, season, base_colour,
+  formality_level, warmth_level, min_temp, max_temp, materials, fit, pattern,
+  icon_emoji, source, is_owned
+FROM clothing_items_import
+ON CONFLICT (id) DO UPDATE SET
+  name            = EXCLUDED.name,
+  category        = EXCLUDED.category,
+  subcategory     = EXCLUDED.subcategory,
+  gender          = EXCLUDED.gender,
+  style           = EXCLUDED.style,
+  usage           = EXCLUDED.usage,
+  season          = EXCLUDED.season,
+  base_colour     = EXCLUDED.base_colour,
+  formality_level = EXCLUDED.formality_level,
+  warmth_level    = EXCLUDED.warmth_level,
+  min_temp        = EXCLUDED.min_temp,
+  max_temp        = EXCLUDED.max_temp,
+  materials       = EXCLUDED.materials,
+  fit             = EXCLUDED.fit,
+  pattern         = EXCLUDED.pattern,
+  icon_emoji      = EXCLUDED.icon_emoji,
+  source          = EXCLUDED.source,
+  is_owned        = EXCLUDED.is_owned;
+`
+		tag, err := tx.Exec(ctx, merge)
+		if err != nil {
+			return 0, fmt.Errorf("merge imported items: %w", err)
+		}
+		copied = tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit copy tx: %w", err)
+	}
+	return copied, nil
+}
+
 func (r *ClothingItemRepo) GetByID(ctx context.Context, id int64) (domain.ClothingItem, error) {
 	const q = `
 SELECT id, name, category, subcategory, gender, style, usage, season, base_colour,
@@ -149,4 +424,18 @@ WHERE id = $1;
 		&it.IconEmoji, &it.Source, &it.IsOwned, &it.CreatedAt,
 	)
 	return it, err
-}
\ No newline at end of file
+}
+
+// Коды ошибок PostgreSQL, которые репозитории переводят в доменные ошибки.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+func pgErrorCode(err error) string {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return pgErr.Code
+	}
+	return ""
+}
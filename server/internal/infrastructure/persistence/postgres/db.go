@@ -75,6 +75,11 @@ func GetDB() *DB {
 	return dbInstance
 }
 
+// Pool отдаёт пул соединений для репозиториев, работающих с pgxpool напрямую (каталог, планировщик).
+func (d *DB) Pool() *pgxpool.Pool {
+	return d.pool
+}
+
 func (d *DB) Close() {
 	if d.pool != nil {
 		d.pool.Close()
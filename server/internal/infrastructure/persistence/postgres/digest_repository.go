@@ -0,0 +1,167 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// DigestRepository implements the DigestRepository interface for PostgreSQL.
+type DigestRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewDigestRepository creates a new morning digest repository.
+func NewDigestRepository(db *DB, logger *zap.Logger) repositories.DigestRepository {
+	return &DigestRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const digestSubscriptionColumns = `user_id, enabled, to_char(send_time, 'HH24:MI'), timezone, next_run_at, created_at, updated_at`
+
+func scanDigestSubscription(row pgx.Row) (*domain.DigestSubscription, error) {
+	var sub domain.DigestSubscription
+	if err := row.Scan(
+		&sub.UserID,
+		&sub.Enabled,
+		&sub.SendTime,
+		&sub.Timezone,
+		&sub.NextRunAt,
+		&sub.CreatedAt,
+		&sub.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+// GetSubscription returns the user's digest subscription or nil.
+func (r *DigestRepository) GetSubscription(ctx context.Context, userID int) (*domain.DigestSubscription, error) {
+	query := `SELECT ` + digestSubscriptionColumns + ` FROM digest_subscriptions WHERE user_id = $1`
+
+	sub, err := scanDigestSubscription(r.db.pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get digest subscription")
+	}
+	return sub, nil
+}
+
+// SaveSubscription inserts or replaces the user's digest subscription.
+func (r *DigestRepository) SaveSubscription(ctx context.Context, sub *domain.DigestSubscription) error {
+	query := `
+		INSERT INTO digest_subscriptions (user_id, enabled, send_time, timezone, next_run_at)
+		VALUES ($1, $2, $3::time, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET enabled = EXCLUDED.enabled,
+		    send_time = EXCLUDED.send_time,
+		    timezone = EXCLUDED.timezone,
+		    next_run_at = EXCLUDED.next_run_at
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.pool.QueryRow(ctx, query,
+		sub.UserID,
+		sub.Enabled,
+		sub.SendTime,
+		sub.Timezone,
+		sub.NextRunAt,
+	).Scan(&sub.CreatedAt, &sub.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to save digest subscription")
+	}
+	return nil
+}
+
+// ListDue returns enabled subscriptions whose next run is due.
+func (r *DigestRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]domain.DigestSubscription, error) {
+	query := `
+		SELECT ` + digestSubscriptionColumns + `
+		FROM digest_subscriptions
+		WHERE enabled AND next_run_at <= $1
+		ORDER BY next_run_at
+		LIMIT $2
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, now, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list due digests")
+	}
+	defer rows.Close()
+
+	var subs []domain.DigestSubscription
+	for rows.Next() {
+		sub, err := scanDigestSubscription(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan digest subscription")
+		}
+		subs = append(subs, *sub)
+	}
+	return subs, rows.Err()
+}
+
+// ScheduleNext moves the subscription's next run.
+func (r *DigestRepository) ScheduleNext(ctx context.Context, userID int, next time.Time) error {
+	query := `UPDATE digest_subscriptions SET next_run_at = $2 WHERE user_id = $1`
+
+	if _, err := r.db.pool.Exec(ctx, query, userID, next); err != nil {
+		return errors.Wrap(err, "failed to schedule next digest")
+	}
+	return nil
+}
+
+// GetTarget returns the digest recipient with the default saved location.
+func (r *DigestRepository) GetTarget(ctx context.Context, userID int) (*domain.NotificationTarget, error) {
+	query := notificationTargetSelect + `
+		WHERE u.id = $1
+	`
+
+	t, err := scanNotificationTarget(r.db.pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get digest recipient")
+	}
+	return t, nil
+}
+
+// ClaimDelivery records the digest for the local date before it is sent.
+func (r *DigestRepository) ClaimDelivery(ctx context.Context, userID int, day time.Time) (bool, error) {
+	query := `
+		INSERT INTO digest_deliveries (user_id, digest_date)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+
+	tag, err := r.db.pool.Exec(ctx, query, userID, day.Format("2006-01-02"))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to claim digest delivery")
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FinishDelivery records the delivery outcome.
+func (r *DigestRepository) FinishDelivery(ctx context.Context, userID int, day time.Time, status, errText string) error {
+	query := `
+		UPDATE digest_deliveries
+		SET status = $3, error = NULLIF($4, ''), finished_at = NOW()
+		WHERE user_id = $1 AND digest_date = $2
+	`
+
+	if _, err := r.db.pool.Exec(ctx, query, userID, day.Format("2006-01-02"), status, errText); err != nil {
+		return errors.Wrap(err, "failed to finish digest delivery")
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// JobRepository implements the JobRepository interface for PostgreSQL.
+type JobRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewJobRepository creates a new background job repository.
+func NewJobRepository(db *DB, logger *zap.Logger) repositories.JobRepository {
+	return &JobRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Enqueue inserts a queued job unless one with the same kind and unique key exists.
+func (r *JobRepository) Enqueue(ctx context.Context, job *domain.Job) (bool, error) {
+	query := `
+		INSERT INTO jobs (kind, payload, unique_key, max_attempts, run_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5)
+		ON CONFLICT (kind, unique_key) WHERE unique_key IS NOT NULL DO NOTHING
+		RETURNING id, status, created_at
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, job.Kind, []byte(job.Payload), job.UniqueKey, job.MaxAttempts, job.RunAt)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to enqueue job")
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return false, errors.Wrap(rows.Err(), "failed to enqueue job")
+	}
+	if err := rows.Scan(&job.ID, &job.Status, &job.CreatedAt); err != nil {
+		return false, errors.Wrap(err, "failed to scan enqueued job")
+	}
+	return true, nil
+}
+
+// Claim leases ready jobs; SKIP LOCKED lets several workers and servers poll the same table.
+func (r *JobRepository) Claim(ctx context.Context, kinds []string, now, leaseUntil time.Time, limit int) ([]domain.Job, error) {
+	query := `
+		WITH ready AS (
+			SELECT id
+			FROM jobs
+			WHERE kind = ANY($1)
+			  AND ((status = 'queued' AND run_at <= $2) OR (status = 'running' AND locked_until < $2))
+			ORDER BY run_at, id
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE jobs j
+		SET status = 'running', attempts = j.attempts + 1, locked_until = $3
+		FROM ready
+		WHERE j.id = ready.id
+		RETURNING j.id, j.kind, j.payload, COALESCE(j.unique_key, ''), j.status, j.attempts, j.max_attempts,
+		          j.run_at, j.locked_until, COALESCE(j.last_error, ''), j.created_at
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, kinds, now, leaseUntil, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim jobs")
+	}
+	defer rows.Close()
+
+	var jobs []domain.Job
+	for rows.Next() {
+		var j domain.Job
+		if err := rows.Scan(
+			&j.ID,
+			&j.Kind,
+			&j.Payload,
+			&j.UniqueKey,
+			&j.Status,
+			&j.Attempts,
+			&j.MaxAttempts,
+			&j.RunAt,
+			&j.LockedUntil,
+			&j.LastError,
+			&j.CreatedAt,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan job")
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, rows.Err()
+}
+
+// Complete marks the job done.
+func (r *JobRepository) Complete(ctx context.Context, id int64, lease time.Time) error {
+	query := `
+		UPDATE jobs
+		SET status = 'done', locked_until = NULL, last_error = NULL, finished_at = NOW()
+		WHERE id = $1 AND status = 'running' AND locked_until = $2
+	`
+	return r.finish(ctx, "failed to complete job", query, id, lease)
+}
+
+// Retry puts the job back into the queue.
+func (r *JobRepository) Retry(ctx context.Context, id int64, lease, runAt time.Time, errText string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'queued', run_at = $3, locked_until = NULL, last_error = $4
+		WHERE id = $1 AND status = 'running' AND locked_until = $2
+	`
+	return r.finish(ctx, "failed to retry job", query, id, lease, runAt, errText)
+}
+
+// Bury marks the job dead.
+func (r *JobRepository) Bury(ctx context.Context, id int64, lease time.Time, errText string) error {
+	query := `
+		UPDATE jobs
+		SET status = 'dead', locked_until = NULL, last_error = $3, finished_at = NOW()
+		WHERE id = $1 AND status = 'running' AND locked_until = $2
+	`
+	return r.finish(ctx, "failed to bury job", query, id, lease, errText)
+}
+
+// Release returns an interrupted job to the queue without counting the attempt.
+func (r *JobRepository) Release(ctx context.Context, id int64, lease time.Time) error {
+	query := `
+		UPDATE jobs
+		SET status = 'queued', run_at = NOW(), locked_until = NULL, attempts = GREATEST(attempts - 1, 0)
+		WHERE id = $1 AND status = 'running' AND locked_until = $2
+	`
+	return r.finish(ctx, "failed to release job", query, id, lease)
+}
+
+// finish records a job result; the lease check keeps a worker that lost the job
+// from overwriting the run of the worker that re-claimed it.
+func (r *JobRepository) finish(ctx context.Context, errMsg, query string, args ...interface{}) error {
+	tag, err := r.db.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, errMsg)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrJobLeaseLost
+	}
+	return nil
+}
+
+// DeleteDone removes jobs finished before the given time.
+func (r *JobRepository) DeleteDone(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM jobs WHERE status = 'done' AND finished_at < $1`, before)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete done jobs")
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,157 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// OutboxRepository implements the OutboxRepository interface for PostgreSQL.
+type OutboxRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewOutboxRepository creates a new event outbox repository.
+func NewOutboxRepository(db *DB, logger *zap.Logger) repositories.OutboxRepository {
+	return &OutboxRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// batchSender — pgx.Tx или пул: события пишутся в чужой транзакции или сами по себе.
+type batchSender interface {
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// Add writes events that are not tied to another change.
+func (r *OutboxRepository) Add(ctx context.Context, events []domain.OutboxEvent) error {
+	if err := insertOutboxEvents(ctx, r.db.pool, events); err != nil {
+		return errors.Wrap(err, "failed to add outbox events")
+	}
+	return nil
+}
+
+// insertOutboxEvents writes events inside the caller's transaction.
+func insertOutboxEvents(ctx context.Context, tx batchSender, events []domain.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO outbox_events (event_id, user_id, type, data, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+
+	batch := &pgx.Batch{}
+	for _, e := range events {
+		createdAt := e.CreatedAt
+		if createdAt.IsZero() {
+			createdAt = time.Now()
+		}
+		batch.Queue(query, e.EventID, e.UserID, e.Type, []byte(e.Data), createdAt)
+	}
+	return tx.SendBatch(ctx, batch).Close()
+}
+
+// ClaimPending leases due events; SKIP LOCKED lets several servers relay in parallel.
+func (r *OutboxRepository) ClaimPending(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.OutboxEvent, error) {
+	query := `
+		WITH due AS (
+			SELECT id
+			FROM outbox_events
+			WHERE published_at IS NULL AND next_attempt_at <= $1
+			ORDER BY id
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox_events o
+		SET next_attempt_at = $2
+		FROM due
+		WHERE o.id = due.id
+		RETURNING o.id, o.event_id, o.user_id, o.type, o.data, o.created_at, o.attempts,
+		          o.next_attempt_at, COALESCE(o.last_error, '')
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim outbox events")
+	}
+	defer rows.Close()
+
+	var events []domain.OutboxEvent
+	for rows.Next() {
+		var e domain.OutboxEvent
+		if err := rows.Scan(
+			&e.ID,
+			&e.EventID,
+			&e.UserID,
+			&e.Type,
+			&e.Data,
+			&e.CreatedAt,
+			&e.Attempts,
+			&e.NextAttemptAt,
+			&e.LastError,
+		); err != nil {
+			return nil, errors.Wrap(err, "failed to scan outbox event")
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "failed to claim outbox events")
+	}
+
+	// UPDATE ... RETURNING не сохраняет порядок подзапроса
+	sort.Slice(events, func(i, j int) bool { return events[i].ID < events[j].ID })
+	return events, nil
+}
+
+// MarkPublished marks the event as published.
+func (r *OutboxRepository) MarkPublished(ctx context.Context, id int64, lease time.Time) error {
+	query := `
+		UPDATE outbox_events
+		SET published_at = NOW(), attempts = attempts + 1, last_error = NULL
+		WHERE id = $1 AND published_at IS NULL AND next_attempt_at = $2
+	`
+	return r.finish(ctx, "failed to mark outbox event published", query, id, lease)
+}
+
+// MarkFailed records a failed attempt and postpones the event.
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id int64, lease, nextAttemptAt time.Time, errText string) error {
+	query := `
+		UPDATE outbox_events
+		SET attempts = attempts + 1, next_attempt_at = $3, last_error = $4
+		WHERE id = $1 AND published_at IS NULL AND next_attempt_at = $2
+	`
+	return r.finish(ctx, "failed to mark outbox event failed", query, id, lease, nextAttemptAt, errText)
+}
+
+// finish records a publish result; the lease check keeps a relay that lost the event
+// from overwriting the result of the relay that re-claimed it.
+func (r *OutboxRepository) finish(ctx context.Context, errMsg, query string, args ...interface{}) error {
+	tag, err := r.db.pool.Exec(ctx, query, args...)
+	if err != nil {
+		return errors.Wrap(err, errMsg)
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrOutboxLeaseLost
+	}
+	return nil
+}
+
+// DeletePublished removes events published before the given time.
+func (r *OutboxRepository) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM outbox_events WHERE published_at < $1`, before)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to delete published outbox events")
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,179 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// PhotoRepository implements the PhotoRepository interface for PostgreSQL.
+type PhotoRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewPhotoRepository creates a new photo repository.
+func NewPhotoRepository(db *DB, logger *zap.Logger) repositories.PhotoRepository {
+	return &PhotoRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const photoColumns = `user_id, clothing_item_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, uploaded_at`
+
+func scanPhoto(row pgx.Row) (*domain.ItemPhoto, error) {
+	var p domain.ItemPhoto
+	err := row.Scan(
+		&p.UserID,
+		&p.ItemID,
+		&p.StorageKey,
+		&p.ThumbnailKey,
+		&p.ContentType,
+		&p.SizeBytes,
+		&p.Width,
+		&p.Height,
+		&p.UploadedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// IsInWardrobe checks that the item belongs to the user's wardrobe.
+func (r *PhotoRepository) IsInWardrobe(ctx context.Context, userID int, itemID int64) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM wardrobe_items WHERE user_id = $1 AND clothing_item_id = $2
+		)
+	`
+
+	var ok bool
+	if err := r.db.pool.QueryRow(ctx, query, userID, itemID).Scan(&ok); err != nil {
+		return false, errors.Wrap(err, "failed to check wardrobe item")
+	}
+	return ok, nil
+}
+
+// GetPhoto retrieves the photo of a wardrobe item.
+func (r *PhotoRepository) GetPhoto(ctx context.Context, userID int, itemID int64) (*domain.ItemPhoto, error) {
+	query := `
+		SELECT ` + photoColumns + `
+		FROM wardrobe_item_photos
+		WHERE user_id = $1 AND clothing_item_id = $2
+	`
+
+	photo, err := scanPhoto(r.db.pool.QueryRow(ctx, query, userID, itemID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // No photo, not an error
+		}
+		return nil, errors.Wrap(err, "failed to get photo")
+	}
+	return photo, nil
+}
+
+// GetPhotos retrieves photos for several items of one user in a single query.
+func (r *PhotoRepository) GetPhotos(ctx context.Context, userID int, itemIDs []int64) (map[int64]domain.ItemPhoto, error) {
+	photos := make(map[int64]domain.ItemPhoto)
+	if len(itemIDs) == 0 {
+		return photos, nil
+	}
+
+	query := `
+		SELECT ` + photoColumns + `
+		FROM wardrobe_item_photos
+		WHERE user_id = $1 AND clothing_item_id = ANY($2::bigint[])
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, userID, itemIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get photos")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		photo, err := scanPhoto(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan photo")
+		}
+		photos[photo.ItemID] = *photo
+	}
+	return photos, rows.Err()
+}
+
+// SavePhoto inserts or replaces the photo of a wardrobe item and returns the replaced one.
+func (r *PhotoRepository) SavePhoto(ctx context.Context, photo *domain.ItemPhoto) (*domain.ItemPhoto, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	previous, err := scanPhoto(tx.QueryRow(ctx, `
+		SELECT `+photoColumns+`
+		FROM wardrobe_item_photos
+		WHERE user_id = $1 AND clothing_item_id = $2
+		FOR UPDATE
+	`, photo.UserID, photo.ItemID))
+	if err != nil && err != pgx.ErrNoRows {
+		return nil, errors.Wrap(err, "failed to get previous photo")
+	}
+
+	query := `
+		INSERT INTO wardrobe_item_photos
+			(user_id, clothing_item_id, storage_key, thumbnail_key, content_type, size_bytes, width, height, uploaded_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NOW())
+		ON CONFLICT (user_id, clothing_item_id) DO UPDATE SET
+			storage_key = EXCLUDED.storage_key,
+			thumbnail_key = EXCLUDED.thumbnail_key,
+			content_type = EXCLUDED.content_type,
+			size_bytes = EXCLUDED.size_bytes,
+			width = EXCLUDED.width,
+			height = EXCLUDED.height,
+			uploaded_at = NOW()
+		RETURNING uploaded_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		photo.UserID,
+		photo.ItemID,
+		photo.StorageKey,
+		photo.ThumbnailKey,
+		photo.ContentType,
+		photo.SizeBytes,
+		photo.Width,
+		photo.Height,
+	).Scan(&photo.UploadedAt)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to save photo")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to commit photo")
+	}
+	return previous, nil
+}
+
+// DeletePhoto removes the photo of a wardrobe item and returns it.
+func (r *PhotoRepository) DeletePhoto(ctx context.Context, userID int, itemID int64) (*domain.ItemPhoto, error) {
+	query := `
+		DELETE FROM wardrobe_item_photos
+		WHERE user_id = $1 AND clothing_item_id = $2
+		RETURNING ` + photoColumns
+
+	photo, err := scanPhoto(r.db.pool.QueryRow(ctx, query, userID, itemID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to delete photo")
+	}
+	return photo, nil
+}
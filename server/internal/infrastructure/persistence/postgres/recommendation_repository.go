@@ -33,6 +33,8 @@ func NewRecommendationRepository(db *DB, logger *zap.Logger) repositories.Recomm
 func (r *RecommendationRepository) CreateRecommendation(
 	ctx context.Context,
 	rec *domain.RecommendationResponse,
+	origin string,
+	events func(rec *domain.RecommendationResponse) ([]domain.OutboxEvent, error),
 ) (int, error) {
 	tx, err := r.db.pool.Begin(ctx)
 	if err != nil {
@@ -65,9 +67,10 @@ func (r *RecommendationRepository) CreateRecommendation(
 			outfit_score,
 			ml_powered,
 			algorithm,
+			origin,
 			created_at
 		)
-		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12)
+		VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13)
 		RETURNING id
 	`,
 		int(rec.UserID),
@@ -81,6 +84,7 @@ func (r *RecommendationRepository) CreateRecommendation(
 		rec.OutfitScore,
 		rec.MLPowered,
 		rec.Algorithm,
+		origin,
 		createdAt,
 	).Scan(&recommendationID)
 	if err != nil {
@@ -129,11 +133,26 @@ func (r *RecommendationRepository) CreateRecommendation(
 		}
 	}
 
+	// --- INSERT INTO outbox_events ---
+	// ID нужен событиям, поэтому выставляем его до коммита и сбрасываем при откате
+	rec.ID = domain.ID(recommendationID)
+	if events != nil {
+		outbox, err := events(rec)
+		if err == nil {
+			err = insertOutboxEvents(ctx, tx, outbox)
+		}
+		if err != nil {
+			_ = tx.Rollback(ctx)
+			rec.ID = 0
+			return 0, errors.Wrap(err, "insert into outbox_events")
+		}
+	}
+
 	if err := tx.Commit(ctx); err != nil {
+		rec.ID = 0
 		return 0, errors.Wrap(err, "commit tx")
 	}
 
-	rec.ID = domain.ID(recommendationID)
 	return recommendationID, nil
 }
 
@@ -0,0 +1,224 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// sessionColumns — поля сессии в порядке scanSession.
+const sessionColumns = `id, user_id, device, ip, created_at, last_used_at, expires_at`
+
+// SessionRepository implements the SessionRepository interface for PostgreSQL.
+type SessionRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewSessionRepository creates a new refresh token session repository.
+func NewSessionRepository(db *DB, logger *zap.Logger) repositories.SessionRepository {
+	return &SessionRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Create opens a session together with its first refresh token.
+func (r *SessionRepository) Create(ctx context.Context, session domain.Session, tokenHash string) (*domain.Session, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	row := tx.QueryRow(ctx, `
+		INSERT INTO auth_sessions (user_id, device, ip, created_at, last_used_at, expires_at)
+		VALUES ($1, $2, $3, $4, $4, $5)
+		RETURNING `+sessionColumns,
+		session.UserID, session.Device, session.IP, session.CreatedAt, session.ExpiresAt,
+	)
+	created, err := scanSession(row)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create session")
+	}
+
+	if err := insertRefreshToken(ctx, tx, tokenHash, created.ID, created.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return created, nil
+}
+
+// Rotate replaces the refresh token; reuse of a rotated token revokes the session.
+func (r *SessionRepository) Rotate(
+	ctx context.Context,
+	oldHash, newHash string,
+	client domain.SessionClient,
+	now, expiresAt time.Time,
+) (*domain.Session, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Блокируются и токен, и сессия: параллельный refresh тем же токеном увидит used_at
+	var (
+		sessionID int64
+		userID    int
+		usedAt    *time.Time
+		revokedAt *time.Time
+		expires   time.Time
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT s.id, s.user_id, t.used_at, s.revoked_at, s.expires_at
+		FROM refresh_tokens t
+		JOIN auth_sessions s ON s.id = t.session_id
+		WHERE t.token_hash = $1
+		FOR UPDATE
+	`, oldHash).Scan(&sessionID, &userID, &usedAt, &revokedAt, &expires)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, repositories.ErrRefreshTokenInvalid
+		}
+		return nil, errors.Wrap(err, "failed to lock refresh token")
+	}
+	if revokedAt != nil || !expires.After(now) {
+		return nil, repositories.ErrRefreshTokenInvalid
+	}
+
+	if usedAt != nil {
+		// Токен уже заменён — его предъявил кто-то ещё: закрываем всю сессию
+		if _, err := tx.Exec(ctx, `UPDATE auth_sessions SET revoked_at = $2 WHERE id = $1`, sessionID, now); err != nil {
+			return nil, errors.Wrap(err, "failed to revoke session")
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, errors.Wrap(err, "commit tx")
+		}
+		r.logger.Warn("🚨 Refresh token reuse detected, session revoked",
+			zap.Int("user_id", userID),
+			zap.Int64("session_id", sessionID),
+			zap.String("ip", client.IP),
+		)
+		return nil, repositories.ErrRefreshTokenReused
+	}
+
+	if _, err := tx.Exec(ctx, `UPDATE refresh_tokens SET used_at = $2 WHERE token_hash = $1`, oldHash, now); err != nil {
+		return nil, errors.Wrap(err, "failed to mark refresh token used")
+	}
+	if err := insertRefreshToken(ctx, tx, newHash, sessionID, now); err != nil {
+		return nil, err
+	}
+
+	row := tx.QueryRow(ctx, `
+		UPDATE auth_sessions
+		SET device = $2, ip = $3, last_used_at = $4, expires_at = $5
+		WHERE id = $1
+		RETURNING `+sessionColumns,
+		sessionID, client.Device, client.IP, now, expiresAt,
+	)
+	session, err := scanSession(row)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to update session")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "commit tx")
+	}
+	return session, nil
+}
+
+// RevokeByToken revokes the session the refresh token belongs to.
+func (r *SessionRepository) RevokeByToken(ctx context.Context, tokenHash string) error {
+	_, err := r.db.pool.Exec(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE id = (SELECT session_id FROM refresh_tokens WHERE token_hash = $1)
+		  AND revoked_at IS NULL
+	`, tokenHash)
+	if err != nil {
+		return errors.Wrap(err, "failed to revoke session")
+	}
+	return nil
+}
+
+// List returns the user's active sessions.
+func (r *SessionRepository) List(ctx context.Context, userID int, now time.Time) ([]domain.Session, error) {
+	rows, err := r.db.pool.Query(ctx, `
+		SELECT `+sessionColumns+`
+		FROM auth_sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > $2
+		ORDER BY last_used_at DESC, id DESC
+	`, userID, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query sessions")
+	}
+	defer rows.Close()
+
+	sessions := []domain.Session{}
+	for rows.Next() {
+		s, err := scanSession(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan session")
+		}
+		sessions = append(sessions, *s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating sessions")
+	}
+	return sessions, nil
+}
+
+// Revoke revokes an active session of the user.
+func (r *SessionRepository) Revoke(ctx context.Context, userID int, sessionID int64) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx, `
+		UPDATE auth_sessions
+		SET revoked_at = NOW()
+		WHERE id = $1 AND user_id = $2 AND revoked_at IS NULL AND expires_at > NOW()
+	`, sessionID, userID)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to revoke session")
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// DeleteExpired removes expired and revoked sessions; their tokens go by cascade.
+func (r *SessionRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := r.db.pool.Exec(ctx, `
+		DELETE FROM auth_sessions
+		WHERE expires_at < $1 OR revoked_at IS NOT NULL
+	`, now)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to clean up sessions")
+	}
+	return tag.RowsAffected(), nil
+}
+
+func insertRefreshToken(ctx context.Context, tx pgx.Tx, tokenHash string, sessionID int64, at time.Time) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO refresh_tokens (token_hash, session_id, created_at)
+		VALUES ($1, $2, $3)
+	`, tokenHash, sessionID, at)
+	if err != nil {
+		return errors.Wrap(err, "failed to save refresh token")
+	}
+	return nil
+}
+
+func scanSession(row pgx.Row) (*domain.Session, error) {
+	var s domain.Session
+	err := row.Scan(&s.ID, &s.UserID, &s.Device, &s.IP, &s.CreatedAt, &s.LastUsedAt, &s.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
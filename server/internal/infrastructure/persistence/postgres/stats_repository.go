@@ -0,0 +1,403 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// refreshCountsQuery пересчитывает счётчики, которые дёшево посчитать целиком.
+// $2 — момент активности; last_active никогда не сдвигается назад.
+const refreshCountsQuery = `
+	INSERT INTO user_stats (user_id, rating_count, average_rating, favorite_count, achievement_count, last_active)
+	SELECT $1,
+	       (SELECT COUNT(*) FROM user_ratings WHERE user_id = $1),
+	       (SELECT COALESCE(AVG(rating), 0)::float8 FROM user_ratings WHERE user_id = $1),
+	       (SELECT COUNT(*) FROM user_favorites WHERE user_id = $1),
+	       (SELECT COUNT(*) FROM user_achievements WHERE user_id = $1),
+	       $2
+	ON CONFLICT (user_id) DO UPDATE
+	SET rating_count = EXCLUDED.rating_count,
+	    average_rating = EXCLUDED.average_rating,
+	    favorite_count = EXCLUDED.favorite_count,
+	    achievement_count = EXCLUDED.achievement_count,
+	    last_active = GREATEST(user_stats.last_active, EXCLUDED.last_active)
+`
+
+// mostUsedCategoryQuery выбирает самую частую категорию за все сезоны.
+const mostUsedCategoryQuery = `
+	UPDATE user_stats
+	SET most_used_category = COALESCE((
+		SELECT category
+		FROM user_category_stats
+		WHERE user_id = $1
+		GROUP BY category
+		ORDER BY SUM(item_count) DESC, category
+		LIMIT 1
+	), '')
+	WHERE user_id = $1
+`
+
+// StatsRepository implements the StatsRepository interface for PostgreSQL.
+type StatsRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewStatsRepository creates a new user stats repository.
+func NewStatsRepository(db *DB, logger *zap.Logger) repositories.StatsRepository {
+	return &StatsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Get returns user stats with the check-in streak (user_streaks) and the top category of every season.
+func (r *StatsRepository) Get(ctx context.Context, userID int) (*domain.ExtendedUserStats, error) {
+	query := `
+		SELECT st.total_recommendations, st.average_rating, st.rating_count, st.favorite_count,
+		       st.achievement_count, st.last_active, st.most_used_category,
+		       COALESCE(sk.current_streak, 0), COALESCE(sk.longest_streak, 0), sk.last_check_in,
+		       COALESCE(sk.freeze_tokens, 0), st.temperature_sum, st.updated_at
+		FROM user_stats st
+		LEFT JOIN user_streaks sk ON sk.user_id = st.user_id
+		WHERE st.user_id = $1
+	`
+
+	var (
+		stats          domain.ExtendedUserStats
+		streak         domain.Streak
+		temperatureSum float64
+	)
+	err := r.db.pool.QueryRow(ctx, query, userID).Scan(
+		&stats.TotalRecommendations,
+		&stats.AverageRating,
+		&stats.RatingCount,
+		&stats.FavoriteCount,
+		&stats.AchievementCount,
+		&stats.LastActive,
+		&stats.MostUsedCategory,
+		&streak.CurrentStreak,
+		&streak.LongestStreak,
+		&streak.LastCheckIn,
+		&streak.FreezeTokens,
+		&temperatureSum,
+		&stats.UpdatedAt,
+	)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get user stats")
+	}
+
+	// Серию ведёт ChallengeService; прерванная и не покрытая заморозками серия — 0
+	streak.AsOf(utcDate(time.Now()))
+	stats.CurrentStreak, stats.LongestStreak = streak.CurrentStreak, streak.LongestStreak
+	if stats.TotalRecommendations > 0 {
+		avg := temperatureSum / float64(stats.TotalRecommendations)
+		stats.AverageTemperature = &avg
+	}
+
+	rows, err := r.db.pool.Query(ctx, `
+		SELECT DISTINCT ON (season) season, category
+		FROM user_category_stats
+		WHERE user_id = $1 AND item_count > 0
+		ORDER BY season, item_count DESC, category
+	`, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get season categories")
+	}
+	defer rows.Close()
+
+	stats.SeasonCategories = make(map[string]string)
+	for rows.Next() {
+		var season, category string
+		if err := rows.Scan(&season, &category); err != nil {
+			return nil, errors.Wrap(err, "failed to scan season category")
+		}
+		stats.SeasonCategories[season] = category
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating season categories")
+	}
+
+	return &stats, nil
+}
+
+// ApplyRecommendation adds a stored recommendation to its owner's counters in one transaction.
+// Digest recommendations are not counted.
+func (r *StatsRepository) ApplyRecommendation(ctx context.Context, recommendationID int) error {
+	var (
+		userID      int
+		temperature float64
+		createdAt   time.Time
+		origin      string
+	)
+	err := r.db.pool.QueryRow(ctx, `
+		SELECT COALESCE(user_id, 0), COALESCE(temperature, 0)::float8, created_at, origin
+		FROM recommendations
+		WHERE id = $1
+	`, recommendationID).Scan(&userID, &temperature, &createdAt, &origin)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			// Рекомендацию уже удалили — считать нечего
+			return nil
+		}
+		return errors.Wrap(err, "failed to get recommendation")
+	}
+	if userID == 0 || origin != domain.RecommendationOriginUser {
+		return nil
+	}
+
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	// Строка статистики блокируется первой: так ApplyRecommendation и Rebuild
+	// одного пользователя выполняются по очереди
+	if err := lockUserStats(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO user_stats_events (event_key, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (event_key) DO NOTHING
+	`, recommendationStatsKey(recommendationID), userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to record stats event")
+	}
+	if tag.RowsAffected() == 0 {
+		// Рекомендация уже учтена
+		return nil
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_stats
+		SET total_recommendations = total_recommendations + 1,
+		    temperature_sum = temperature_sum + $2,
+		    last_active = GREATEST(last_active, $3)
+		WHERE user_id = $1
+	`, userID, temperature, createdAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to update user stats")
+	}
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_category_stats (user_id, season, category, item_count)
+		SELECT $1, $2, category, COUNT(*)
+		FROM recommendation_items
+		WHERE recommendation_id = $3 AND COALESCE(category, '') <> ''
+		GROUP BY category
+		ON CONFLICT (user_id, season, category) DO UPDATE
+		SET item_count = user_category_stats.item_count + EXCLUDED.item_count
+	`, userID, domain.SeasonOf(createdAt.UTC().Month()), recommendationID)
+	if err != nil {
+		return errors.Wrap(err, "failed to update category stats")
+	}
+
+	if _, err := tx.Exec(ctx, mostUsedCategoryQuery, userID); err != nil {
+		return errors.Wrap(err, "failed to update most used category")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
+	return nil
+}
+
+// RefreshCounts recounts ratings, favorites and achievements.
+func (r *StatsRepository) RefreshCounts(ctx context.Context, userID int, at time.Time) error {
+	if _, err := r.db.pool.Exec(ctx, refreshCountsQuery, userID, at); err != nil {
+		return errors.Wrap(err, "failed to refresh user stats")
+	}
+	return nil
+}
+
+// Rebuild recomputes all user stats from the source tables in one transaction.
+func (r *StatsRepository) Rebuild(ctx context.Context, userID int) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if err := lockUserStats(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	// Пересчёт учитывает все рекомендации пользователя — их события позже ничего не добавят.
+	// Рекомендации дайджеста не считаются ни здесь, ни в ApplyRecommendation
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_stats_events (event_key, user_id)
+		SELECT 'recommendation:' || id, user_id
+		FROM recommendations
+		WHERE user_id = $1 AND origin = 'user'
+		ON CONFLICT (event_key) DO NOTHING
+	`, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to record stats events")
+	}
+
+	var (
+		total          int
+		temperatureSum float64
+	)
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(temperature), 0)::float8
+		FROM recommendations
+		WHERE user_id = $1 AND origin = 'user'
+	`, userID).Scan(&total, &temperatureSum)
+	if err != nil {
+		return errors.Wrap(err, "failed to count recommendations")
+	}
+
+	if err := rebuildCategoryStats(ctx, tx, userID); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `
+		UPDATE user_stats
+		SET total_recommendations = $2,
+		    temperature_sum = $3,
+		    last_active = COALESCE(GREATEST(
+		        (SELECT MAX(created_at) FROM recommendations WHERE user_id = $1 AND origin = 'user'),
+		        (SELECT MAX(created_at) FROM user_ratings WHERE user_id = $1),
+		        (SELECT MAX(created_at) FROM user_favorites WHERE user_id = $1)
+		    ), last_active)
+		WHERE user_id = $1
+	`, userID, total, temperatureSum)
+	if err != nil {
+		return errors.Wrap(err, "failed to update user stats")
+	}
+
+	if _, err := tx.Exec(ctx, mostUsedCategoryQuery, userID); err != nil {
+		return errors.Wrap(err, "failed to update most used category")
+	}
+	// Нулевой момент не сдвигает уже посчитанный last_active
+	if _, err := tx.Exec(ctx, refreshCountsQuery, userID, time.Time{}); err != nil {
+		return errors.Wrap(err, "failed to refresh user stats")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
+	return nil
+}
+
+// ListUserIDs returns ids of all users.
+func (r *StatsRepository) ListUserIDs(ctx context.Context) ([]int, error) {
+	rows, err := r.db.pool.Query(ctx, `SELECT id FROM users ORDER BY id`)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list users")
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, errors.Wrap(err, "failed to scan user id")
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating users")
+	}
+	return ids, nil
+}
+
+// lockUserStats creates the user's stats row if needed and locks it.
+func lockUserStats(ctx context.Context, tx pgx.Tx, userID int) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO user_stats (user_id)
+		VALUES ($1)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to create user stats")
+	}
+
+	_, err = tx.Exec(ctx, `
+		SELECT 1
+		FROM user_stats
+		WHERE user_id = $1
+		FOR UPDATE
+	`, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to lock user stats")
+	}
+	return nil
+}
+
+// rebuildCategoryStats replaces the user's per-season category counts.
+func rebuildCategoryStats(ctx context.Context, tx pgx.Tx, userID int) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM user_category_stats WHERE user_id = $1`, userID); err != nil {
+		return errors.Wrap(err, "failed to clear category stats")
+	}
+
+	// Сезон определяется в Go (domain.SeasonOf), как и при обработке событий
+	rows, err := tx.Query(ctx, `
+		SELECT EXTRACT(MONTH FROM r.created_at AT TIME ZONE 'UTC')::int, ri.category, COUNT(*)
+		FROM recommendations r
+		JOIN recommendation_items ri ON ri.recommendation_id = r.id
+		WHERE r.user_id = $1 AND r.origin = 'user' AND COALESCE(ri.category, '') <> ''
+		GROUP BY 1, 2
+	`, userID)
+	if err != nil {
+		return errors.Wrap(err, "failed to query category stats")
+	}
+
+	type seasonCategory struct{ season, category string }
+	counts := make(map[seasonCategory]int)
+	for rows.Next() {
+		var (
+			month    int
+			category string
+			count    int
+		)
+		if err := rows.Scan(&month, &category, &count); err != nil {
+			rows.Close()
+			return errors.Wrap(err, "failed to scan category stats")
+		}
+		counts[seasonCategory{domain.SeasonOf(time.Month(month)), category}] += count
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "error iterating category stats")
+	}
+	if len(counts) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for k, count := range counts {
+		batch.Queue(`
+			INSERT INTO user_category_stats (user_id, season, category, item_count)
+			VALUES ($1, $2, $3, $4)
+		`, userID, k.season, k.category, count)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return errors.Wrap(err, "failed to store category stats")
+	}
+	return nil
+}
+
+func utcDate(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func recommendationStatsKey(recommendationID int) string {
+	return fmt.Sprintf("recommendation:%d", recommendationID)
+}
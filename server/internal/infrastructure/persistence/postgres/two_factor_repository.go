@@ -0,0 +1,172 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// TwoFactorRepository implements the TwoFactorRepository interface for PostgreSQL.
+type TwoFactorRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewTwoFactorRepository creates a new two-factor authentication repository.
+func NewTwoFactorRepository(db *DB, logger *zap.Logger) repositories.TwoFactorRepository {
+	return &TwoFactorRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// Get returns the user's 2FA settings with the number of unused recovery codes.
+func (r *TwoFactorRepository) Get(ctx context.Context, userID int) (*domain.TwoFactor, error) {
+	t := domain.TwoFactor{UserID: userID}
+	err := r.db.pool.QueryRow(ctx, `
+		SELECT secret, enabled_at, last_used_step,
+		       (SELECT COUNT(*) FROM user_recovery_codes c WHERE c.user_id = f.user_id AND c.used_at IS NULL)
+		FROM user_two_factor f
+		WHERE user_id = $1
+	`, userID).Scan(&t.Secret, &t.EnabledAt, &t.LastUsedStep, &t.RecoveryCodesLeft)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get two-factor settings")
+	}
+	return &t, nil
+}
+
+// SavePending stores the secret of an enrolment unless 2FA is already enabled.
+func (r *TwoFactorRepository) SavePending(ctx context.Context, userID int, secret string) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx, `
+		INSERT INTO user_two_factor (user_id, secret)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET secret = EXCLUDED.secret,
+		    last_used_step = 0,
+		    created_at = NOW()
+		WHERE user_two_factor.enabled_at IS NULL
+	`, userID, secret)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to save two-factor secret")
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Enable confirms a pending enrolment and replaces the recovery codes.
+func (r *TwoFactorRepository) Enable(ctx context.Context, userID int, step int64, recoveryHashes []string, at time.Time) (bool, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return false, errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		UPDATE user_two_factor
+		SET enabled_at = $2, last_used_step = $3
+		WHERE user_id = $1 AND enabled_at IS NULL
+	`, userID, at, step)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to enable two-factor")
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return false, errors.Wrap(err, "failed to delete recovery codes")
+	}
+	_, err = tx.Exec(ctx, `
+		INSERT INTO user_recovery_codes (user_id, code_hash)
+		SELECT $1, h FROM unnest($2::text[]) AS h
+	`, userID, recoveryHashes)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to save recovery codes")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, errors.Wrap(err, "commit tx")
+	}
+	return true, nil
+}
+
+// UseStep accepts a code of the time step once; the condition makes it atomic across replicas.
+func (r *TwoFactorRepository) UseStep(ctx context.Context, userID int, step int64) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx, `
+		UPDATE user_two_factor
+		SET last_used_step = $2
+		WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2
+	`, userID, step)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to accept two-factor code")
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// RecoveryCodeHashes returns the hashes of the user's unused recovery codes.
+func (r *TwoFactorRepository) RecoveryCodeHashes(ctx context.Context, userID int) ([]string, error) {
+	rows, err := r.db.pool.Query(ctx, `
+		SELECT code_hash
+		FROM user_recovery_codes
+		WHERE user_id = $1 AND used_at IS NULL
+	`, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to query recovery codes")
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var h string
+		if err := rows.Scan(&h); err != nil {
+			return nil, errors.Wrap(err, "failed to scan recovery code")
+		}
+		hashes = append(hashes, h)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, errors.Wrap(err, "error iterating recovery codes")
+	}
+	return hashes, nil
+}
+
+// UseRecoveryCode marks an unused recovery code as used.
+func (r *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID int, hash string, at time.Time) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx, `
+		UPDATE user_recovery_codes
+		SET used_at = $3
+		WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL
+	`, userID, hash, at)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to use recovery code")
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// Delete disables 2FA.
+func (r *TwoFactorRepository) Delete(ctx context.Context, userID int) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	if _, err := tx.Exec(ctx, `DELETE FROM user_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return errors.Wrap(err, "failed to delete recovery codes")
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM user_two_factor WHERE user_id = $1`, userID); err != nil {
+		return errors.Wrap(err, "failed to delete two-factor settings")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
+	return nil
+}
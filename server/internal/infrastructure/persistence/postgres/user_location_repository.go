@@ -0,0 +1,308 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// UserLocationRepository implements the UserLocationRepository interface for PostgreSQL.
+type UserLocationRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewUserLocationRepository creates a new saved locations repository.
+func NewUserLocationRepository(db *DB, logger *zap.Logger) repositories.UserLocationRepository {
+	return &UserLocationRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const userLocationColumns = `id, user_id, kind, name, COALESCE(location_id, ''), lat::float8, lon::float8, timezone, is_default, created_at, updated_at`
+
+func scanUserLocation(row pgx.Row) (*domain.UserLocation, error) {
+	var l domain.UserLocation
+	err := row.Scan(
+		&l.ID,
+		&l.UserID,
+		&l.Kind,
+		&l.Name,
+		&l.LocationID,
+		&l.Lat,
+		&l.Lon,
+		&l.Timezone,
+		&l.IsDefault,
+		&l.CreatedAt,
+		&l.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// List returns all saved locations of the user, the default one first.
+func (r *UserLocationRepository) List(ctx context.Context, userID int) ([]domain.UserLocation, error) {
+	query := `
+		SELECT ` + userLocationColumns + `
+		FROM user_locations
+		WHERE user_id = $1
+		ORDER BY is_default DESC, created_at, id
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list user locations")
+	}
+	defer rows.Close()
+
+	locations := []domain.UserLocation{}
+	for rows.Next() {
+		loc, err := scanUserLocation(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan user location")
+		}
+		locations = append(locations, *loc)
+	}
+	return locations, rows.Err()
+}
+
+// Count returns the number of saved locations of the user.
+func (r *UserLocationRepository) Count(ctx context.Context, userID int) (int, error) {
+	var n int
+	if err := r.db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM user_locations WHERE user_id = $1`, userID).Scan(&n); err != nil {
+		return 0, errors.Wrap(err, "failed to count user locations")
+	}
+	return n, nil
+}
+
+// Get returns a saved location of the user.
+func (r *UserLocationRepository) Get(ctx context.Context, userID, id int) (*domain.UserLocation, error) {
+	query := `
+		SELECT ` + userLocationColumns + `
+		FROM user_locations
+		WHERE user_id = $1 AND id = $2
+	`
+
+	loc, err := scanUserLocation(r.db.pool.QueryRow(ctx, query, userID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get user location")
+	}
+	return loc, nil
+}
+
+// GetDefault returns the default location of the user.
+func (r *UserLocationRepository) GetDefault(ctx context.Context, userID int) (*domain.UserLocation, error) {
+	query := `
+		SELECT ` + userLocationColumns + `
+		FROM user_locations
+		WHERE user_id = $1 AND is_default
+	`
+
+	loc, err := scanUserLocation(r.db.pool.QueryRow(ctx, query, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get default user location")
+	}
+	return loc, nil
+}
+
+// Create saves a new location unless the user already has limit of them.
+func (r *UserLocationRepository) Create(ctx context.Context, loc *domain.UserLocation, limit int) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	// Блокировка строки пользователя упорядочивает параллельные Create: подсчёт ниже не устареет до вставки
+	if _, err := tx.Exec(ctx, `SELECT 1 FROM users WHERE id = $1 FOR UPDATE`, loc.UserID); err != nil {
+		return errors.Wrap(err, "failed to lock user")
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM user_locations WHERE user_id = $1`, loc.UserID).Scan(&count); err != nil {
+		return errors.Wrap(err, "failed to count user locations")
+	}
+	if count >= limit {
+		return repositories.ErrTooManyLocations
+	}
+	if count == 0 {
+		loc.IsDefault = true
+	}
+
+	if loc.IsDefault {
+		if err := clearDefaultLocation(ctx, tx, loc.UserID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		INSERT INTO user_locations (user_id, kind, name, location_id, lat, lon, timezone, is_default)
+		VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		loc.UserID,
+		loc.Kind,
+		loc.Name,
+		loc.LocationID,
+		loc.Lat,
+		loc.Lon,
+		loc.Timezone,
+		loc.IsDefault,
+	).Scan(&loc.ID, &loc.CreatedAt, &loc.UpdatedAt)
+	if err != nil {
+		return wrapUserLocationError(err, "failed to create user location")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit user location")
+	}
+	return nil
+}
+
+// Update changes a saved location.
+func (r *UserLocationRepository) Update(ctx context.Context, loc *domain.UserLocation) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	if loc.IsDefault {
+		if err := clearDefaultLocation(ctx, tx, loc.UserID); err != nil {
+			return err
+		}
+	}
+
+	query := `
+		UPDATE user_locations
+		SET kind = $3, name = $4, location_id = NULLIF($5, ''), lat = $6, lon = $7, timezone = $8, is_default = $9
+		WHERE user_id = $1 AND id = $2
+		RETURNING created_at, updated_at
+	`
+
+	err = tx.QueryRow(ctx, query,
+		loc.UserID,
+		loc.ID,
+		loc.Kind,
+		loc.Name,
+		loc.LocationID,
+		loc.Lat,
+		loc.Lon,
+		loc.Timezone,
+		loc.IsDefault,
+	).Scan(&loc.CreatedAt, &loc.UpdatedAt)
+	if err != nil {
+		return wrapUserLocationError(err, "failed to update user location")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "failed to commit user location")
+	}
+	return nil
+}
+
+// Delete removes a saved location and promotes the oldest remaining one if the default was removed.
+func (r *UserLocationRepository) Delete(ctx context.Context, userID, id int) (*domain.UserLocation, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	deleted, err := scanUserLocation(tx.QueryRow(ctx, `
+		DELETE FROM user_locations
+		WHERE user_id = $1 AND id = $2
+		RETURNING `+userLocationColumns,
+		userID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to delete user location")
+	}
+
+	if deleted.IsDefault {
+		_, err := tx.Exec(ctx, `
+			UPDATE user_locations SET is_default = TRUE
+			WHERE id = (
+				SELECT id FROM user_locations WHERE user_id = $1 ORDER BY created_at, id LIMIT 1
+			)
+		`, userID)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to promote default user location")
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to commit user location")
+	}
+	return deleted, nil
+}
+
+// SetDefault marks the location as the user's default.
+func (r *UserLocationRepository) SetDefault(ctx context.Context, userID, id int) (*domain.UserLocation, error) {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to begin transaction")
+	}
+	defer tx.Rollback(ctx)
+
+	if err := clearDefaultLocation(ctx, tx, userID); err != nil {
+		return nil, err
+	}
+
+	loc, err := scanUserLocation(tx.QueryRow(ctx, `
+		UPDATE user_locations SET is_default = TRUE
+		WHERE user_id = $1 AND id = $2
+		RETURNING `+userLocationColumns,
+		userID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil // откат вернёт прежнее место по умолчанию
+		}
+		return nil, errors.Wrap(err, "failed to set default user location")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, errors.Wrap(err, "failed to commit user location")
+	}
+	return loc, nil
+}
+
+// clearDefaultLocation снимает отметку по умолчанию до установки новой: иначе сработает уникальный индекс.
+func clearDefaultLocation(ctx context.Context, tx pgx.Tx, userID int) error {
+	if _, err := tx.Exec(ctx, `UPDATE user_locations SET is_default = FALSE WHERE user_id = $1 AND is_default`, userID); err != nil {
+		return errors.Wrap(err, "failed to clear default user location")
+	}
+	return nil
+}
+
+func wrapUserLocationError(err error, msg string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		switch pgErr.ConstraintName {
+		case "idx_user_locations_user_kind":
+			return repositories.ErrLocationKindTaken
+		case "idx_user_locations_user_default":
+			return repositories.ErrDefaultLocationTaken
+		}
+	}
+	return errors.Wrap(err, msg)
+}
@@ -287,8 +287,8 @@ func (r *UserRepository) GetUserAchievements(ctx context.Context, userID int) ([
 	return achievements, nil
 }
 
-// UnlockAchievement unlocks an achievement for a user.
-func (r *UserRepository) UnlockAchievement(ctx context.Context, userID int, achievementCode string) error {
+// UnlockAchievement unlocks an achievement for a user; false if it was already unlocked.
+func (r *UserRepository) UnlockAchievement(ctx context.Context, userID int, achievementCode string) (bool, error) {
 	// First get the achievement ID
 	var achievementID int
 	err := r.db.pool.QueryRow(ctx, `
@@ -296,19 +296,22 @@ func (r *UserRepository) UnlockAchievement(ctx context.Context, userID int, achi
 	`, achievementCode).Scan(&achievementID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
-			return errors.Errorf("achievement %s not found", achievementCode)
+			return false, errors.Errorf("achievement %s not found", achievementCode)
 		}
-		return errors.Wrap(err, "failed to find achievement")
+		return false, errors.Wrap(err, "failed to find achievement")
 	}
 
 	// Insert the achievement
-	_, err = r.db.pool.Exec(ctx, `
+	tag, err := r.db.pool.Exec(ctx, `
 		INSERT INTO user_achievements (user_id, achievement_id, unlocked_at)
 		VALUES ($1, $2, NOW())
 		ON CONFLICT (user_id, achievement_id) DO NOTHING
 	`, userID, achievementID)
 	if err != nil {
-		return errors.Wrap(err, "failed to unlock achievement")
+		return false, errors.Wrap(err, "failed to unlock achievement")
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
 	}
 
 	r.logger.Info("🏆 Achievement unlocked",
@@ -316,18 +319,24 @@ func (r *UserRepository) UnlockAchievement(ctx context.Context, userID int, achi
 		zap.String("achievement_code", achievementCode),
 	)
 
-	return nil
+	return true, nil
 }
 
 // RateRecommendation saves a user's rating for a recommendation.
-func (r *UserRepository) RateRecommendation(ctx context.Context, userID, recommendationID, rating int, feedback string) error {
+func (r *UserRepository) RateRecommendation(ctx context.Context, userID, recommendationID, rating int, feedback string, events []domain.OutboxEvent) error {
 	if rating < 1 || rating > 5 {
 		return errors.New("rating must be between 1 and 5")
 	}
 
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
 	// Check if recommendation exists and belongs to user
 	var exists bool
-	err := r.db.pool.QueryRow(ctx, `
+	err = tx.QueryRow(ctx, `
 		SELECT EXISTS(
 			SELECT 1 FROM recommendations r
 			JOIN recommendation_items ri ON r.id = ri.recommendation_id
@@ -343,7 +352,7 @@ func (r *UserRepository) RateRecommendation(ctx context.Context, userID, recomme
 	}
 
 	// Insert or update rating
-	_, err = r.db.pool.Exec(ctx, `
+	_, err = tx.Exec(ctx, `
 		INSERT INTO user_ratings (user_id, recommendation_id, rating, feedback, created_at)
 		VALUES ($1, $2, $3, $4, NOW())
 		ON CONFLICT (user_id, recommendation_id) 
@@ -354,7 +363,7 @@ func (r *UserRepository) RateRecommendation(ctx context.Context, userID, recomme
 	}
 
 	// Update user stats
-	_, err = r.db.pool.Exec(ctx, `
+	_, err = tx.Exec(ctx, `
 		UPDATE user_stats
 		SET average_rating = (
 			SELECT AVG(rating) FROM user_ratings WHERE user_id = $1
@@ -365,6 +374,12 @@ func (r *UserRepository) RateRecommendation(ctx context.Context, userID, recomme
 		return errors.Wrap(err, "failed to update average rating")
 	}
 
+	if err := insertOutboxEvents(ctx, tx, events); err != nil {
+		return errors.Wrap(err, "failed to write outbox events")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
 	return nil
 }
 
@@ -408,23 +423,45 @@ func (r *UserRepository) GetUserRatings(ctx context.Context, userID int) ([]doma
 }
 
 // AddFavorite adds a recommendation to user's favorites.
-func (r *UserRepository) AddFavorite(ctx context.Context, userID, recommendationID int) error {
-	_, err := r.db.pool.Exec(ctx, `
-		INSERT INTO favorite_outfits (user_id, recommendation_id, created_at)
+func (r *UserRepository) AddFavorite(ctx context.Context, userID, recommendationID int, events []domain.OutboxEvent) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO user_favorites (user_id, recommendation_id, created_at)
 		VALUES ($1, $2, NOW())
 		ON CONFLICT (user_id, recommendation_id) DO NOTHING
 	`, userID, recommendationID)
 	if err != nil {
 		return errors.Wrap(err, "failed to add favorite")
 	}
+	if tag.RowsAffected() == 0 {
+		// Уже в избранном: событие было при первом добавлении
+		return nil
+	}
 
+	if err := insertOutboxEvents(ctx, tx, events); err != nil {
+		return errors.Wrap(err, "failed to write outbox events")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
 	return nil
 }
 
 // RemoveFavorite removes a recommendation from user's favorites.
-func (r *UserRepository) RemoveFavorite(ctx context.Context, userID, favoriteID int) error {
-	result, err := r.db.pool.Exec(ctx, `
-		DELETE FROM favorite_outfits
+func (r *UserRepository) RemoveFavorite(ctx context.Context, userID, favoriteID int, events []domain.OutboxEvent) error {
+	tx, err := r.db.pool.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "begin tx")
+	}
+	defer func() { _ = tx.Rollback(ctx) }()
+
+	result, err := tx.Exec(ctx, `
+		DELETE FROM user_favorites
 		WHERE id = $1 AND user_id = $2
 	`, favoriteID, userID)
 	if err != nil {
@@ -435,6 +472,12 @@ func (r *UserRepository) RemoveFavorite(ctx context.Context, userID, favoriteID
 		return errors.New("favorite not found or not owned by user")
 	}
 
+	if err := insertOutboxEvents(ctx, tx, events); err != nil {
+		return errors.Wrap(err, "failed to write outbox events")
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return errors.Wrap(err, "commit tx")
+	}
 	return nil
 }
 
@@ -442,7 +485,7 @@ func (r *UserRepository) RemoveFavorite(ctx context.Context, userID, favoriteID
 func (r *UserRepository) GetUserFavorites(ctx context.Context, userID int) ([]domain.FavoriteOutfit, error) {
 	query := `
 		SELECT id, user_id, recommendation_id, created_at
-		FROM favorite_outfits
+		FROM user_favorites
 		WHERE user_id = $1
 		ORDER BY created_at DESC
 	`
@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/alerts"
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// WeatherAlertRepository implements the WeatherAlertRepository interface for PostgreSQL.
+type WeatherAlertRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewWeatherAlertRepository creates a new severe-weather notification repository.
+func NewWeatherAlertRepository(db *DB, logger *zap.Logger) repositories.WeatherAlertRepository {
+	return &WeatherAlertRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// notificationTargetSelect выбирает получателя уведомлений вместе с местом по умолчанию;
+// столбцы разбирает scanNotificationTarget.
+const notificationTargetSelect = `
+		SELECT u.id, u.email, COALESCE(u.location, ''),
+		       l.id, l.kind, l.name, COALESCE(l.location_id, ''), l.lat::float8, l.lon::float8, l.timezone
+		FROM users u
+		LEFT JOIN user_locations l ON l.user_id = u.id AND l.is_default
+`
+
+func scanNotificationTarget(row pgx.Row) (*domain.NotificationTarget, error) {
+	var (
+		t          domain.NotificationTarget
+		locID      *int
+		kind, name *string
+		placeID    *string
+		lat, lon   *float64
+		timezone   *string
+	)
+	if err := row.Scan(&t.UserID, &t.Email, &t.City, &locID, &kind, &name, &placeID, &lat, &lon, &timezone); err != nil {
+		return nil, err
+	}
+	if locID != nil {
+		t.Location = &domain.UserLocation{
+			ID:          *locID,
+			UserID:      t.UserID,
+			Kind:        *kind,
+			Name:        *name,
+			LocationID:  *placeID,
+			Coordinates: domain.Coordinates{Lat: *lat, Lon: *lon},
+			Timezone:    *timezone,
+			IsDefault:   true,
+		}
+	}
+	return &t, nil
+}
+
+// ListTargets returns users with a default saved location or a profile city.
+func (r *WeatherAlertRepository) ListTargets(ctx context.Context, afterUserID, limit int) ([]domain.NotificationTarget, error) {
+	query := notificationTargetSelect + `
+		WHERE u.id > $1
+		  AND (l.id IS NOT NULL OR COALESCE(u.location, '') <> '')
+		ORDER BY u.id
+		LIMIT $2
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, afterUserID, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list weather alert targets")
+	}
+	defer rows.Close()
+
+	var targets []domain.NotificationTarget
+	for rows.Next() {
+		t, err := scanNotificationTarget(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan weather alert target")
+		}
+		targets = append(targets, *t)
+	}
+	return targets, rows.Err()
+}
+
+// MarkNotified records that the user was notified about the alert for the day.
+func (r *WeatherAlertRepository) MarkNotified(ctx context.Context, userID int, kind alerts.Kind, day time.Time) (bool, error) {
+	query := `
+		INSERT INTO weather_alert_notifications (user_id, kind, alert_date)
+		VALUES ($1, $2, $3)
+		ON CONFLICT DO NOTHING
+	`
+
+	tag, err := r.db.pool.Exec(ctx, query, userID, string(kind), day.Format("2006-01-02"))
+	if err != nil {
+		return false, errors.Wrap(err, "failed to mark weather alert notified")
+	}
+	return tag.RowsAffected() == 1, nil
+}
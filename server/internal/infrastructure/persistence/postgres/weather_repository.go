@@ -0,0 +1,63 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// Размеры колонок weather_data из 0001_init_schema.sql
+const (
+	weatherLocationMaxLen  = 100
+	weatherConditionMaxLen = 50
+)
+
+// WeatherRepository implements the WeatherRepository interface for PostgreSQL.
+type WeatherRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewWeatherRepository creates a new weather repository.
+func NewWeatherRepository(db *DB, logger *zap.Logger) repositories.WeatherRepository {
+	return &WeatherRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SaveObservation stores a weather observation.
+func (r *WeatherRepository) SaveObservation(ctx context.Context, weather *domain.ExtendedWeatherData) (int, error) {
+	query := `
+		INSERT INTO weather_data (location, temperature, feels_like, weather_condition, humidity, wind_speed, timestamp)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	var id int
+	err := r.db.pool.QueryRow(ctx, query,
+		truncate(weather.Location, weatherLocationMaxLen),
+		weather.Temperature,
+		weather.FeelsLike,
+		truncate(weather.Weather, weatherConditionMaxLen),
+		weather.Humidity,
+		weather.WindSpeed,
+		weather.Timestamp,
+	).Scan(&id)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to save weather observation")
+	}
+	return id, nil
+}
+
+// truncate обрезает строку по числу символов (VARCHAR(n) считает символы, а не байты).
+func truncate(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n])
+	}
+	return s
+}
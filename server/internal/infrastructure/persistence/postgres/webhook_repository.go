@@ -0,0 +1,323 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"outfitstyle/server/internal/core/application/repositories"
+	"outfitstyle/server/internal/core/domain"
+)
+
+// WebhookRepository implements the WebhookRepository interface for PostgreSQL.
+type WebhookRepository struct {
+	db     *DB
+	logger *zap.Logger
+}
+
+// NewWebhookRepository creates a new webhook repository.
+func NewWebhookRepository(db *DB, logger *zap.Logger) repositories.WebhookRepository {
+	return &WebhookRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+const webhookColumns = `id, user_id, url, description, events, secret, active, created_at, updated_at`
+
+func scanWebhook(row pgx.Row) (*domain.Webhook, error) {
+	var h domain.Webhook
+	err := row.Scan(
+		&h.ID,
+		&h.UserID,
+		&h.URL,
+		&h.Description,
+		&h.Events,
+		&h.Secret,
+		&h.Active,
+		&h.CreatedAt,
+		&h.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]domain.Webhook, error) {
+	rows, err := r.db.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list webhooks")
+	}
+	defer rows.Close()
+
+	hooks := []domain.Webhook{}
+	for rows.Next() {
+		h, err := scanWebhook(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook")
+		}
+		hooks = append(hooks, *h)
+	}
+	return hooks, rows.Err()
+}
+
+// List returns the user's webhooks, oldest first.
+func (r *WebhookRepository) List(ctx context.Context, userID int) ([]domain.Webhook, error) {
+	return r.queryWebhooks(ctx, `SELECT `+webhookColumns+` FROM webhooks WHERE user_id = $1 ORDER BY id`, userID)
+}
+
+// Count returns the number of the user's webhooks.
+func (r *WebhookRepository) Count(ctx context.Context, userID int) (int, error) {
+	var n int
+	if err := r.db.pool.QueryRow(ctx, `SELECT COUNT(*) FROM webhooks WHERE user_id = $1`, userID).Scan(&n); err != nil {
+		return 0, errors.Wrap(err, "failed to count webhooks")
+	}
+	return n, nil
+}
+
+// Get returns a webhook of the user.
+func (r *WebhookRepository) Get(ctx context.Context, userID, id int) (*domain.Webhook, error) {
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE user_id = $1 AND id = $2`
+
+	h, err := scanWebhook(r.db.pool.QueryRow(ctx, query, userID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to get webhook")
+	}
+	return h, nil
+}
+
+// Create saves a new webhook.
+func (r *WebhookRepository) Create(ctx context.Context, hook *domain.Webhook) error {
+	query := `
+		INSERT INTO webhooks (user_id, url, description, events, secret, active)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.pool.QueryRow(ctx, query,
+		hook.UserID,
+		hook.URL,
+		hook.Description,
+		hook.Events,
+		hook.Secret,
+		hook.Active,
+	).Scan(&hook.ID, &hook.CreatedAt, &hook.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to create webhook")
+	}
+	return nil
+}
+
+// Update replaces a webhook.
+func (r *WebhookRepository) Update(ctx context.Context, hook *domain.Webhook) error {
+	query := `
+		UPDATE webhooks
+		SET url = $3, description = $4, events = $5, secret = $6, active = $7
+		WHERE user_id = $1 AND id = $2
+		RETURNING updated_at
+	`
+
+	err := r.db.pool.QueryRow(ctx, query,
+		hook.UserID,
+		hook.ID,
+		hook.URL,
+		hook.Description,
+		hook.Events,
+		hook.Secret,
+		hook.Active,
+	).Scan(&hook.UpdatedAt)
+	if err != nil {
+		return errors.Wrap(err, "failed to update webhook")
+	}
+	return nil
+}
+
+// Delete removes a webhook and its delivery log.
+func (r *WebhookRepository) Delete(ctx context.Context, userID, id int) (bool, error) {
+	tag, err := r.db.pool.Exec(ctx, `DELETE FROM webhooks WHERE user_id = $1 AND id = $2`, userID, id)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to delete webhook")
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// ListSubscribed returns active webhooks of the user subscribed to the event.
+func (r *WebhookRepository) ListSubscribed(ctx context.Context, userID int, event string) ([]domain.Webhook, error) {
+	query := `
+		SELECT ` + webhookColumns + `
+		FROM webhooks
+		WHERE user_id = $1 AND active AND $2 = ANY(events)
+		ORDER BY id
+	`
+	return r.queryWebhooks(ctx, query, userID, event)
+}
+
+// Enqueue inserts pending deliveries.
+func (r *WebhookRepository) Enqueue(ctx context.Context, deliveries []domain.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event, payload)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (webhook_id, event_id) DO NOTHING
+	`
+
+	batch := &pgx.Batch{}
+	for _, d := range deliveries {
+		batch.Queue(query, d.WebhookID, d.EventID, d.Event, []byte(d.Payload))
+	}
+	if err := r.db.pool.SendBatch(ctx, batch).Close(); err != nil {
+		return errors.Wrap(err, "failed to enqueue webhook deliveries")
+	}
+	return nil
+}
+
+// ClaimDue leases due deliveries of active webhooks; SKIP LOCKED lets several servers dispatch in parallel.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now, leaseUntil time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	query := `
+		WITH due AS (
+			SELECT d.id
+			FROM webhook_deliveries d
+			JOIN webhooks w ON w.id = d.webhook_id
+			WHERE d.status = 'pending' AND d.next_attempt_at <= $1 AND w.active
+			ORDER BY d.next_attempt_at
+			LIMIT $3
+			FOR UPDATE OF d SKIP LOCKED
+		)
+		UPDATE webhook_deliveries d
+		SET next_attempt_at = $2
+		FROM due, webhooks w
+		WHERE d.id = due.id AND w.id = d.webhook_id
+		RETURNING d.id, d.webhook_id, d.event_id, d.event, d.payload, d.attempts, d.next_attempt_at, d.created_at, w.url, w.secret
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, now, leaseUntil, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to claim webhook deliveries")
+	}
+	defer rows.Close()
+
+	var deliveries []domain.WebhookDelivery
+	for rows.Next() {
+		d := domain.WebhookDelivery{Status: domain.WebhookDeliveryPending}
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.EventID, &d.Event, &d.Payload, &d.Attempts, &d.NextAttemptAt, &d.CreatedAt, &d.URL, &d.Secret); err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook delivery")
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt stores the outcome of a delivery attempt. The lease check keeps a server whose
+// lease expired from overwriting the attempt of the server that re-claimed the delivery.
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, d *domain.WebhookDelivery, lease time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = $2,
+		    attempts = $3,
+		    next_attempt_at = $4,
+		    last_status_code = NULLIF($5, 0),
+		    last_error = NULLIF($6, ''),
+		    delivered_at = $7
+		WHERE id = $1 AND status = 'pending' AND next_attempt_at = $8
+	`
+
+	tag, err := r.db.pool.Exec(ctx, query,
+		d.ID,
+		d.Status,
+		d.Attempts,
+		d.NextAttemptAt,
+		d.LastStatusCode,
+		d.LastError,
+		d.DeliveredAt,
+		lease,
+	)
+	if err != nil {
+		return errors.Wrap(err, "failed to record webhook attempt")
+	}
+	if tag.RowsAffected() == 0 {
+		return repositories.ErrWebhookLeaseLost
+	}
+	return nil
+}
+
+const webhookDeliveryColumns = `d.id, d.webhook_id, d.event_id, d.event, d.payload, d.status, d.attempts, d.next_attempt_at,
+	COALESCE(d.last_status_code, 0), COALESCE(d.last_error, ''), d.created_at, d.delivered_at`
+
+func scanWebhookDelivery(row pgx.Row) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := row.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.EventID,
+		&d.Event,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastStatusCode,
+		&d.LastError,
+		&d.CreatedAt,
+		&d.DeliveredAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns the delivery log of the user's webhook, newest first.
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, userID, webhookID int, status string, limit, offset int) ([]domain.WebhookDelivery, error) {
+	query := `
+		SELECT ` + webhookDeliveryColumns + `
+		FROM webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		WHERE w.user_id = $1 AND d.webhook_id = $2 AND ($3 = '' OR d.status = $3)
+		ORDER BY d.id DESC
+		LIMIT $4 OFFSET $5
+	`
+
+	rows, err := r.db.pool.Query(ctx, query, userID, webhookID, status, limit, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list webhook deliveries")
+	}
+	defer rows.Close()
+
+	deliveries := []domain.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to scan webhook delivery")
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+// Redeliver puts a delivery back into the queue with a fresh attempt budget.
+func (r *WebhookRepository) Redeliver(ctx context.Context, userID, webhookID int, id int64) (*domain.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries d
+		SET status = 'pending', attempts = 0, next_attempt_at = NOW(), delivered_at = NULL
+		FROM webhooks w
+		WHERE w.id = d.webhook_id AND w.user_id = $1 AND d.webhook_id = $2 AND d.id = $3
+		RETURNING ` + webhookDeliveryColumns
+
+	d, err := scanWebhookDelivery(r.db.pool.QueryRow(ctx, query, userID, webhookID, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "failed to redeliver webhook")
+	}
+	return d, nil
+}
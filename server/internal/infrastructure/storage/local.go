@@ -0,0 +1,147 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MediaPathPrefix — префикс, под которым LocalStorage отдаёт объекты по подписанным ссылкам.
+const MediaPathPrefix = "/media/"
+
+// LocalStorage хранит объекты в каталоге на диске и сам отдаёт их по HMAC-подписанным ссылкам.
+type LocalStorage struct {
+	dir        string
+	baseURL    string
+	signingKey []byte
+}
+
+// NewLocalStorage creates a filesystem-backed storage rooted at dir
+func NewLocalStorage(dir, baseURL string, signingKey []byte) (*LocalStorage, error) {
+	if dir == "" {
+		return nil, errors.New("local storage dir is required")
+	}
+	if len(signingKey) == 0 {
+		return nil, errors.New("storage signing key is required")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create storage dir: %w", err)
+	}
+	return &LocalStorage{
+		dir:        dir,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: signingKey,
+	}, nil
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return fmt.Errorf("create object dir: %w", err)
+	}
+
+	// Пишем во временный файл и переименовываем, чтобы не отдавать недописанный объект
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close object: %w", err)
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	p, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(p); err != nil {
+		if os.IsNotExist(err) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+
+	q := url.Values{}
+	q.Set("expires", expires)
+	q.Set("sig", s.sign(key, expires))
+	return s.baseURL + MediaPathPrefix + key + "?" + q.Encode(), nil
+}
+
+// ServeHTTP отдаёт объект, если подпись верна и срок ссылки не истёк.
+// Монтируется на MediaPathPrefix.
+func (s *LocalStorage) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key, err := cleanKey(strings.TrimPrefix(r.URL.Path, MediaPathPrefix))
+	if err != nil {
+		http.Error(w, "invalid key", http.StatusBadRequest)
+		return
+	}
+
+	expires := r.URL.Query().Get("expires")
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		http.Error(w, "link expired", http.StatusForbidden)
+		return
+	}
+	if !hmac.Equal([]byte(r.URL.Query().Get("sig")), []byte(s.sign(key, expires))) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	p, err := s.path(key)
+	if err != nil {
+		http.Error(w, "invalid key", http.StatusBadRequest)
+		return
+	}
+	if _, err := os.Stat(p); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	// Ссылка живёт ограниченное время — кэшировать дольше нельзя
+	w.Header().Set("Cache-Control", "private, max-age="+strconv.FormatInt(max(exp-time.Now().Unix(), 0), 10))
+	http.ServeFile(w, r, p)
+}
+
+func (s *LocalStorage) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *LocalStorage) path(key string) (string, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
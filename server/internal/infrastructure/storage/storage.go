@@ -0,0 +1,54 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound возвращается, когда объекта с таким ключом нет.
+var ErrNotFound = errors.New("object not found")
+
+// Storage — хранилище бинарных объектов (фото вещей, превью).
+// Ключи — относительные пути вида "wardrobe/42/1001/abcd.jpg".
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	// SignedURL возвращает ссылку на объект, действующую ttl.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// Config — параметры выбора и настройки хранилища.
+type Config struct {
+	Backend string // local | s3
+
+	LocalDir      string
+	PublicBaseURL string // базовый URL API, под которым локальное хранилище отдаёт /media/...
+	SigningKey    string
+
+	S3 S3Config
+}
+
+// New создаёт хранилище по конфигу.
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStorage(cfg.LocalDir, cfg.PublicBaseURL, []byte(cfg.SigningKey))
+	case "s3":
+		return NewS3Storage(cfg.S3, nil)
+	default:
+		return nil, fmt.Errorf("unsupported storage backend %q (must be local or s3)", cfg.Backend)
+	}
+}
+
+// cleanKey нормализует ключ и не даёт выйти за пределы хранилища.
+func cleanKey(key string) (string, error) {
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") || strings.Contains(cleaned, "..") {
+		return "", fmt.Errorf("invalid object key %q", key)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,355 @@
+// Package gazetteer — офлайн-справочник городов для поиска и автодополнения места.
+// Клиент выбирает город из подсказок и передаёт его id, поэтому провайдер погоды
+// получает канонические координаты, а не то, что пользователь набрал с опечаткой.
+package gazetteer
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// bundledCities — крупные города России и СНГ и популярные направления поездок.
+//
+//go:embed cities.csv
+var bundledCities []byte
+
+var citiesHeader = []string{"id", "name", "name_ru", "alternate_names", "country", "lat", "lon", "timezone", "population"}
+
+const (
+	// DefaultLimit и MaxLimit ограничивают число подсказок в Search.
+	DefaultLimit = 10
+	MaxLimit     = 50
+	// minFuzzyLen — более короткие запросы ищутся только по префиксу: на 2–3 буквах опечатка неотличима от другого города.
+	minFuzzyLen = 4
+)
+
+// Place — город справочника.
+type Place struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name"`
+	NameRU         string   `json:"name_ru"`
+	AlternateNames []string `json:"alternate_names,omitempty"`
+	Country        string   `json:"country"`
+	Lat            float64  `json:"lat"`
+	Lon            float64  `json:"lon"`
+	Timezone       string   `json:"timezone"`
+	Population     int      `json:"population"`
+
+	keys []string // нормализованные названия: Name, NameRU, AlternateNames
+}
+
+// DisplayName — название для ответа клиенту: приложение русскоязычное.
+func (p Place) DisplayName() string {
+	if p.NameRU != "" {
+		return p.NameRU
+	}
+	return p.Name
+}
+
+// Match — найденный город и то его название, которое совпало с запросом.
+type Match struct {
+	Place
+	MatchedName string `json:"matched_name"`
+	Distance    int    `json:"distance"` // число правок до совпадения; 0 — точное или префиксное
+	kind        matchKind
+}
+
+type matchKind int
+
+const (
+	matchExact matchKind = iota
+	matchPrefix
+	matchWordPrefix
+	matchFuzzy
+	noMatch
+)
+
+// Gazetteer — справочник в памяти. После загрузки не меняется, поэтому безопасен для конкурентного чтения.
+type Gazetteer struct {
+	places []Place
+	byID   map[string]int
+}
+
+// Bundled загружает встроенный справочник.
+func Bundled() (*Gazetteer, error) {
+	return Load(bytes.NewReader(bundledCities))
+}
+
+// Load читает справочник в формате cities.csv; синонимы в alternate_names разделяются «|».
+func Load(r io.Reader) (*Gazetteer, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = len(citiesHeader)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read header: %w", err)
+	}
+	for i, col := range citiesHeader {
+		if header[i] != col {
+			return nil, fmt.Errorf("unexpected column %d: %q (want %q)", i+1, header[i], col)
+		}
+	}
+
+	g := &Gazetteer{byID: make(map[string]int)}
+	for {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		p, err := parsePlace(rec)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if _, dup := g.byID[p.ID]; dup {
+			return nil, fmt.Errorf("line %d: duplicate id %q", line, p.ID)
+		}
+		g.byID[p.ID] = len(g.places)
+		g.places = append(g.places, p)
+	}
+	return g, nil
+}
+
+func parsePlace(rec []string) (Place, error) {
+	p := Place{
+		ID:       strings.TrimSpace(rec[0]),
+		Name:     strings.TrimSpace(rec[1]),
+		NameRU:   strings.TrimSpace(rec[2]),
+		Country:  strings.TrimSpace(rec[4]),
+		Timezone: strings.TrimSpace(rec[7]),
+	}
+	if p.ID == "" || p.Name == "" {
+		return p, fmt.Errorf("id and name are required")
+	}
+	if p.Timezone == "" {
+		return p, fmt.Errorf("timezone is required")
+	}
+	for _, alt := range strings.Split(rec[3], "|") {
+		if alt = strings.TrimSpace(alt); alt != "" {
+			p.AlternateNames = append(p.AlternateNames, alt)
+		}
+	}
+
+	var err error
+	if p.Lat, err = strconv.ParseFloat(rec[5], 64); err != nil || p.Lat < -90 || p.Lat > 90 {
+		return p, fmt.Errorf("invalid lat %q", rec[5])
+	}
+	if p.Lon, err = strconv.ParseFloat(rec[6], 64); err != nil || p.Lon < -180 || p.Lon > 180 {
+		return p, fmt.Errorf("invalid lon %q", rec[6])
+	}
+	if p.Population, err = strconv.Atoi(rec[8]); err != nil || p.Population < 0 {
+		return p, fmt.Errorf("invalid population %q", rec[8])
+	}
+
+	seen := make(map[string]bool)
+	for _, name := range append([]string{p.Name, p.NameRU}, p.AlternateNames...) {
+		if key := Normalize(name); key != "" && !seen[key] {
+			seen[key] = true
+			p.keys = append(p.keys, key)
+		}
+	}
+	return p, nil
+}
+
+// Len возвращает число городов в справочнике.
+func (g *Gazetteer) Len() int {
+	return len(g.places)
+}
+
+// Get возвращает город по id.
+func (g *Gazetteer) Get(id string) (Place, bool) {
+	i, ok := g.byID[id]
+	if !ok {
+		return Place{}, false
+	}
+	return g.places[i], true
+}
+
+// Resolve находит город по точному (с точностью до нормализации) названию на любом языке.
+// При совпадении у нескольких городов выбирается самый крупный.
+func (g *Gazetteer) Resolve(name string) (Place, bool) {
+	key := Normalize(name)
+	if key == "" {
+		return Place{}, false
+	}
+
+	best := -1
+	for i := range g.places {
+		for _, k := range g.places[i].keys {
+			if k == key && (best < 0 || g.places[i].Population > g.places[best].Population) {
+				best = i
+				break
+			}
+		}
+	}
+	if best < 0 {
+		return Place{}, false
+	}
+	return g.places[best], true
+}
+
+// Search ищет города по началу названия и с опечатками (расстояние Дамерау — Левенштейна).
+// Точные совпадения идут первыми, затем префиксные, затем по началу слова, затем нечёткие;
+// внутри группы — по числу правок и численности населения.
+func (g *Gazetteer) Search(query string, limit int) []Match {
+	q := []rune(Normalize(query))
+	if len(q) == 0 {
+		return nil
+	}
+	if limit <= 0 {
+		limit = DefaultLimit
+	}
+	if limit > MaxLimit {
+		limit = MaxLimit
+	}
+
+	var matches []Match
+	for _, p := range g.places {
+		best := Match{kind: noMatch}
+		for i, key := range p.keys {
+			kind, dist := matchKey(q, []rune(key))
+			if kind < best.kind || (kind == best.kind && dist < best.Distance) {
+				best = Match{Place: p, MatchedName: p.displayKey(i), Distance: dist, kind: kind}
+			}
+		}
+		if best.kind != noMatch {
+			matches = append(matches, best)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		if a.Population != b.Population {
+			return a.Population > b.Population
+		}
+		return a.ID < b.ID
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+	return matches
+}
+
+// displayKey возвращает исходное написание названия, которому соответствует keys[i].
+func (p Place) displayKey(i int) string {
+	key := p.keys[i]
+	for _, name := range append([]string{p.Name, p.NameRU}, p.AlternateNames...) {
+		if Normalize(name) == key {
+			return name
+		}
+	}
+	return key
+}
+
+func matchKey(q, key []rune) (matchKind, int) {
+	if len(q) <= len(key) && string(key[:len(q)]) == string(q) {
+		if len(q) == len(key) {
+			return matchExact, 0
+		}
+		return matchPrefix, 0
+	}
+	for i := 1; i < len(key); i++ {
+		if key[i-1] == ' ' && len(key)-i >= len(q) && string(key[i:i+len(q)]) == string(q) {
+			return matchWordPrefix, 0
+		}
+	}
+
+	maxDist := maxEdits(len(q))
+	if maxDist == 0 {
+		return noMatch, 0
+	}
+	// Для автодополнения сравниваем и с целым названием, и с его началом той же длины, что запрос:
+	// «моксв» — это опечатка в начале «москва»
+	dist := editDistance(q, key)
+	if len(key) > len(q) {
+		if d := editDistance(q, key[:len(q)]); d < dist {
+			dist = d
+		}
+	}
+	if dist <= maxDist {
+		return matchFuzzy, dist
+	}
+	return noMatch, 0
+}
+
+func maxEdits(n int) int {
+	switch {
+	case n < minFuzzyLen:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance — расстояние Дамерау — Левенштейна (вариант optimal string alignment):
+// перестановка соседних букв — одна правка, это самая частая опечатка при наборе.
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(b)]
+}
+
+// Normalize приводит название к ключу поиска: нижний регистр, без диакритики, «ё» как «е»,
+// дефисы, точки и апострофы — пробелы («Ростов-на-Дону» = «ростов на дону»).
+func Normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Диакритика после NFD — отдельные символы; «й» при этом тоже распадается, её возвращаем ниже
+			if r == '\u0306' {
+				b.WriteRune(r)
+			}
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	out := norm.NFC.String(b.String())
+	out = strings.ReplaceAll(out, "ё", "е")
+	return strings.Join(strings.Fields(out), " ")
+}
@@ -0,0 +1,75 @@
+package http
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+type clientIPKey struct{}
+
+// ClientIPMiddleware определяет адрес клиента и кладёт его в контекст запроса (см. ClientIP).
+// X-Forwarded-For учитывается, только если запрос пришёл от доверенного прокси: заголовок
+// разбирается справа налево, пока адреса принадлежат trustedProxies. Без доверенных прокси
+// берётся RemoteAddr — иначе клиент подставлял бы любой адрес сам (по IP ограничиваются входы).
+func ClientIPMiddleware(trustedProxies []*net.IPNet) mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := resolveClientIP(r, trustedProxies)
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), clientIPKey{}, ip)))
+		})
+	}
+}
+
+// ClientIP возвращает адрес клиента, определённый ClientIPMiddleware, а без него — RemoteAddr.
+func ClientIP(r *http.Request) string {
+	if ip, ok := r.Context().Value(clientIPKey{}).(string); ok {
+		return ip
+	}
+	return remoteIP(r)
+}
+
+func resolveClientIP(r *http.Request, trustedProxies []*net.IPNet) string {
+	ip := remoteIP(r)
+	if !isTrustedProxy(ip, trustedProxies) {
+		return ip
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			// Мусор в заголовке: дальше ему верить нельзя, клиент — последний проверенный адрес
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(ip, trustedProxies) {
+			break
+		}
+	}
+	return ip
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+func isTrustedProxy(ip string, trustedProxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
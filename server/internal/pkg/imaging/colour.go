@@ -0,0 +1,283 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// colourSampleSize — до какого размера уменьшаем картинку перед кластеризацией:
+	// 96×96 достаточно для цветов, а k-means остаётся быстрым.
+	colourSampleSize = 96
+	kmeansMaxIter    = 20
+	// backgroundDeltaE — насколько цвет пикселя может отличаться от фона, чтобы считаться фоном.
+	backgroundDeltaE = 12
+	// backgroundBorderShare — доля рамки картинки, которая должна быть одного цвета, чтобы считать его фоном.
+	backgroundBorderShare = 0.6
+	// minForegroundShare — если после удаления фона осталось меньше, фон не вырезаем
+	// (скорее всего, это сама вещь крупным планом).
+	minForegroundShare = 0.1
+)
+
+// Colour — цвет кластера и его доля среди пикселей вещи.
+type Colour struct {
+	R, G, B uint8
+	Share   float64
+}
+
+// Hex возвращает цвет в виде #rrggbb.
+func (c Colour) Hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// lab — цвет в CIELAB: евклидово расстояние в нём близко к воспринимаемой разнице цветов.
+type lab struct{ L, A, B float64 }
+
+// DeltaE — разница цветов по CIE76 (≈2.3 — порог заметности, >50 — совсем разные цвета).
+func DeltaE(c1, c2 Colour) float64 {
+	return toLab(c1.R, c1.G, c1.B).dist(toLab(c2.R, c2.G, c2.B))
+}
+
+// DominantColours находит до k основных цветов картинки k-means'ом в пространстве Lab.
+// Однотонный фон (студийный белый, серый) определяется по рамке и отбрасывается.
+// Результат отсортирован по убыванию доли.
+func DominantColours(data []byte, k int) ([]Colour, error) {
+	if k < 1 {
+		return nil, fmt.Errorf("k must be positive, got %d", k)
+	}
+	if _, err := Inspect(data); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	img := fit(src, colourSampleSize)
+	pixels := foreground(img)
+
+	centroids, counts := kmeans(pixels, k)
+
+	colours := make([]Colour, 0, len(centroids))
+	for i, c := range centroids {
+		if counts[i] == 0 {
+			continue
+		}
+		r, g, b := c.rgb()
+		colours = append(colours, Colour{R: r, G: g, B: b, Share: float64(counts[i]) / float64(len(pixels))})
+	}
+	sort.SliceStable(colours, func(i, j int) bool { return colours[i].Share > colours[j].Share })
+	return colours, nil
+}
+
+// foreground возвращает пиксели вещи в Lab, отбрасывая однотонный фон.
+func foreground(img *image.RGBA) []lab {
+	b := img.Bounds()
+	all := make([]lab, 0, b.Dx()*b.Dy())
+	var border []lab
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[(y-b.Min.Y)*img.Stride:]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			p := row[(x-b.Min.X)*4:]
+			c := toLab(p[0], p[1], p[2])
+			all = append(all, c)
+			if x == b.Min.X || y == b.Min.Y || x == b.Max.X-1 || y == b.Max.Y-1 {
+				border = append(border, c)
+			}
+		}
+	}
+
+	bg, ok := uniformColour(border)
+	if !ok {
+		return all
+	}
+	fg := make([]lab, 0, len(all))
+	for _, c := range all {
+		if c.dist(bg) > backgroundDeltaE {
+			fg = append(fg, c)
+		}
+	}
+	if float64(len(fg)) < minForegroundShare*float64(len(all)) {
+		return all
+	}
+	return fg
+}
+
+// uniformColour проверяет, что большая часть рамки одного цвета, и возвращает этот цвет.
+func uniformColour(border []lab) (lab, bool) {
+	if len(border) == 0 {
+		return lab{}, false
+	}
+	// Медиана по каждой координате устойчива к вещи, касающейся края кадра
+	med := lab{
+		L: median(border, func(c lab) float64 { return c.L }),
+		A: median(border, func(c lab) float64 { return c.A }),
+		B: median(border, func(c lab) float64 { return c.B }),
+	}
+	similar := 0
+	for _, c := range border {
+		if c.dist(med) <= backgroundDeltaE {
+			similar++
+		}
+	}
+	return med, float64(similar) >= backgroundBorderShare*float64(len(border))
+}
+
+func median(cs []lab, f func(lab) float64) float64 {
+	vs := make([]float64, len(cs))
+	for i, c := range cs {
+		vs[i] = f(c)
+	}
+	sort.Float64s(vs)
+	return vs[len(vs)/2]
+}
+
+// kmeans кластеризует пиксели; инициализация k-means++ с фиксированным seed,
+// чтобы одна и та же картинка всегда давала один и тот же результат.
+func kmeans(pixels []lab, k int) ([]lab, []int) {
+	if len(pixels) < k {
+		k = len(pixels)
+	}
+	rng := rand.New(rand.NewSource(1))
+
+	centroids := make([]lab, 0, k)
+	centroids = append(centroids, pixels[rng.Intn(len(pixels))])
+	dist := make([]float64, len(pixels))
+	for len(centroids) < k {
+		var sum float64
+		for i, p := range pixels {
+			d := p.dist(centroids[nearest(centroids, p)])
+			dist[i] = d * d
+			sum += dist[i]
+		}
+		if sum == 0 {
+			break // различных цветов меньше k
+		}
+		target := rng.Float64() * sum
+		i := 0
+		for ; i < len(pixels)-1; i++ {
+			if target -= dist[i]; target <= 0 {
+				break
+			}
+		}
+		centroids = append(centroids, pixels[i])
+	}
+
+	assign := make([]int, len(pixels))
+	counts := make([]int, len(centroids))
+	for iter := 0; iter < kmeansMaxIter; iter++ {
+		changed := iter == 0
+		for i, p := range pixels {
+			if c := nearest(centroids, p); c != assign[i] {
+				assign[i] = c
+				changed = true
+			}
+		}
+		if !changed {
+			break
+		}
+
+		sums := make([]lab, len(centroids))
+		for i := range counts {
+			counts[i] = 0
+		}
+		for i, p := range pixels {
+			c := assign[i]
+			sums[c].L += p.L
+			sums[c].A += p.A
+			sums[c].B += p.B
+			counts[c]++
+		}
+		for c := range centroids {
+			if counts[c] > 0 {
+				n := float64(counts[c])
+				centroids[c] = lab{sums[c].L / n, sums[c].A / n, sums[c].B / n}
+			}
+		}
+	}
+
+	for i := range counts {
+		counts[i] = 0
+	}
+	for _, c := range assign {
+		counts[c]++
+	}
+	return centroids, counts
+}
+
+func nearest(centroids []lab, p lab) int {
+	best, bestDist := 0, math.MaxFloat64
+	for i, c := range centroids {
+		if d := p.dist(c); d < bestDist {
+			best, bestDist = i, d
+		}
+	}
+	return best
+}
+
+func (c lab) dist(o lab) float64 {
+	dl, da, db := c.L-o.L, c.A-o.A, c.B-o.B
+	return math.Sqrt(dl*dl + da*da + db*db)
+}
+
+// D65 white point
+const (
+	whiteX = 0.95047
+	whiteY = 1.0
+	whiteZ = 1.08883
+)
+
+func toLab(r, g, b uint8) lab {
+	lr, lg, lb := linearize(r), linearize(g), linearize(b)
+	x := (0.4124*lr + 0.3576*lg + 0.1805*lb) / whiteX
+	y := (0.2126*lr + 0.7152*lg + 0.0722*lb) / whiteY
+	z := (0.0193*lr + 0.1192*lg + 0.9505*lb) / whiteZ
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return lab{L: 116*fy - 16, A: 500 * (fx - fy), B: 200 * (fy - fz)}
+}
+
+func (c lab) rgb() (uint8, uint8, uint8) {
+	fy := (c.L + 16) / 116
+	fx := fy + c.A/500
+	fz := fy - c.B/200
+	x, y, z := labFInv(fx)*whiteX, labFInv(fy)*whiteY, labFInv(fz)*whiteZ
+	lr := 3.2406*x - 1.5372*y - 0.4986*z
+	lg := -0.9689*x + 1.8758*y + 0.0415*z
+	lb := 0.0557*x - 0.2040*y + 1.0570*z
+	return delinearize(lr), delinearize(lg), delinearize(lb)
+}
+
+func linearize(v uint8) float64 {
+	c := float64(v) / 255
+	if c <= 0.04045 {
+		return c / 12.92
+	}
+	return math.Pow((c+0.055)/1.055, 2.4)
+}
+
+func delinearize(c float64) uint8 {
+	if c <= 0.0031308 {
+		c *= 12.92
+	} else {
+		c = 1.055*math.Pow(c, 1/2.4) - 0.055
+	}
+	return uint8(math.Round(math.Max(0, math.Min(1, c)) * 255))
+}
+
+func labF(t float64) float64 {
+	if t > 216.0/24389 {
+		return math.Cbrt(t)
+	}
+	return (24389.0/27*t + 16) / 116
+}
+
+func labFInv(t float64) float64 {
+	if t3 := t * t * t; t3 > 216.0/24389 {
+		return t3
+	}
+	return (116*t - 16) * 27 / 24389
+}
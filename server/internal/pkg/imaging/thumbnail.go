@@ -0,0 +1,124 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+)
+
+// MaxPixels — предел размера декодируемой картинки (защита от «pixel flood» при небольшом файле).
+const MaxPixels = 40_000_000
+
+// ErrTooLarge — картинка больше MaxPixels.
+var ErrTooLarge = errors.New("image dimensions are too large")
+
+// Info — метаданные картинки без полного декодирования.
+type Info struct {
+	Format string
+	Width  int
+	Height int
+}
+
+// Inspect читает только заголовок картинки.
+func Inspect(data []byte) (Info, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Info{}, fmt.Errorf("decode image header: %w", err)
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 {
+		return Info{}, errors.New("image has zero size")
+	}
+	if cfg.Width*cfg.Height > MaxPixels {
+		return Info{}, ErrTooLarge
+	}
+	return Info{Format: format, Width: cfg.Width, Height: cfg.Height}, nil
+}
+
+// Thumbnail уменьшает картинку так, чтобы она вписалась в size×size, и кодирует в JPEG.
+// Маленькие картинки не увеличиваются.
+func Thumbnail(data []byte, size, quality int) ([]byte, error) {
+	if _, err := Inspect(data); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %w", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeJPEG(&buf, fit(src, size), quality); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeJPEG(w io.Writer, img image.Image, quality int) error {
+	if err := jpeg.Encode(w, img, &jpeg.Options{Quality: quality}); err != nil {
+		return fmt.Errorf("encode jpeg: %w", err)
+	}
+	return nil
+}
+
+// fit уменьшает картинку усреднением по площади (box filter) — без артефактов ближайшего соседа
+// и без зависимостей вне стандартной библиотеки.
+func fit(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+
+	dw, dh := sw, sh
+	if sw > size || sh > size {
+		if sw >= sh {
+			dw, dh = size, max(1, sh*size/sw)
+		} else {
+			dw, dh = max(1, sw*size/sh), size
+		}
+	}
+
+	// Приводим к RGBA один раз: у draw.Draw быстрые пути для YCbCr/Paletted,
+	// дальше работаем с Pix напрямую, без интерфейсного At() на каждый пиксель.
+	rgba := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	if o, ok := src.(interface{ Opaque() bool }); ok && o.Opaque() {
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	} else {
+		// В JPEG нет прозрачности — кладём на белый фон, иначе прозрачное станет чёрным
+		draw.Draw(rgba, rgba.Bounds(), image.White, image.Point{}, draw.Src)
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Over)
+	}
+	if dw == sw && dh == sh {
+		return rgba
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		y0, y1 := y*sh/dh, max((y+1)*sh/dh, y*sh/dh+1)
+		for x := 0; x < dw; x++ {
+			x0, x1 := x*sw/dw, max((x+1)*sw/dw, x*sw/dw+1)
+
+			var r, g, bl, a, n uint32
+			for sy := y0; sy < y1; sy++ {
+				row := rgba.Pix[sy*rgba.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					p := row[sx*4 : sx*4+4]
+					r += uint32(p[0])
+					g += uint32(p[1])
+					bl += uint32(p[2])
+					a += uint32(p[3])
+					n++
+				}
+			}
+
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = uint8(r / n)
+			dst.Pix[o+1] = uint8(g / n)
+			dst.Pix[o+2] = uint8(bl / n)
+			dst.Pix[o+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
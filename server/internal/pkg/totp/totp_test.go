@@ -0,0 +1,94 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcSecret — ключ из приложения B RFC 6238 для HMAC-SHA1 ("12345678901234567890").
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// Векторы RFC 6238 (SHA-1). В RFC коды 8-значные; шестизначный код — их последние 6 цифр.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},          // 94287082
+	{1111111109, "081804"},  // 07081804
+	{1111111111, "050471"},  // 14050471
+	{1234567890, "005924"},  // 89005924
+	{2000000000, "279037"},  // 69279037
+	{20000000000, "353130"}, // 65353130
+}
+
+func TestCodeRFC6238Vectors(t *testing.T) {
+	for _, v := range rfcVectors {
+		got, err := Code(rfcSecret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code(T=%d): %v", v.unix, err)
+		}
+		if got != v.code {
+			t.Errorf("Code(T=%d) = %s, want %s", v.unix, got, v.code)
+		}
+	}
+}
+
+func TestMatchRFC6238Vectors(t *testing.T) {
+	for _, v := range rfcVectors {
+		at := time.Unix(v.unix, 0)
+		step, ok := Match(rfcSecret, v.code, at, 0)
+		if !ok {
+			t.Errorf("Match(T=%d, %s) rejected a valid code", v.unix, v.code)
+			continue
+		}
+		if step != Step(at) {
+			t.Errorf("Match(T=%d) step = %d, want %d", v.unix, step, Step(at))
+		}
+	}
+}
+
+func TestMatchSkewWindow(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	current := Step(now)
+
+	for _, tc := range []struct {
+		offset int64
+		skew   int
+		want   bool
+	}{
+		{0, 0, true},
+		{-1, 0, false},
+		{1, 0, false},
+		{-1, 1, true},
+		{1, 1, true},
+		{-2, 1, false},
+		{2, 1, false},
+		{2, 2, true},
+	} {
+		code, err := Code(rfcSecret, current+tc.offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		step, ok := Match(rfcSecret, code, now, tc.skew)
+		if ok != tc.want {
+			t.Errorf("offset %d, skew %d: ok = %v, want %v", tc.offset, tc.skew, ok, tc.want)
+			continue
+		}
+		if ok && step != current+tc.offset {
+			t.Errorf("offset %d, skew %d: step = %d, want %d", tc.offset, tc.skew, step, current+tc.offset)
+		}
+	}
+}
+
+func TestMatchRejectsMalformedCode(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if _, ok := Match(rfcSecret, code, now, 1); ok {
+			t.Errorf("Match(%q) accepted a malformed code", code)
+		}
+	}
+	if _, ok := Match("not base32!", "287082", now, 1); ok {
+		t.Error("Match accepted a code for an invalid secret")
+	}
+}
@@ -0,0 +1,10 @@
+-- Migration: Add clothing_item_quarantine for items flagged by the catalog quality report (cmd/quality)
+
+-- Вещи в карантине не попадают в подбор (FindCandidatesByPlan), но остаются в каталоге для разбора
+CREATE TABLE clothing_item_quarantine (
+    item_id BIGINT PRIMARY KEY REFERENCES clothing_items(id) ON DELETE CASCADE,
+    reasons TEXT[] NOT NULL DEFAULT '{}',  -- Rule codes and messages from the quality report
+    quarantined_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_clothing_item_quarantine_quarantined_at ON clothing_item_quarantine(quarantined_at);
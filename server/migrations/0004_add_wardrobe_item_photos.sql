@@ -0,0 +1,17 @@
+-- Migration: Add wardrobe_item_photos for user photos of their wardrobe items
+
+-- Фото привязано к паре пользователь + вещь: одна и та же вещь каталога у разных пользователей выглядит по-разному
+CREATE TABLE wardrobe_item_photos (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    clothing_item_id BIGINT NOT NULL REFERENCES clothing_items(id) ON DELETE CASCADE,
+    storage_key TEXT NOT NULL,             -- Object key of the original in the storage backend
+    thumbnail_key TEXT NOT NULL,           -- Object key of the generated JPEG thumbnail
+    content_type VARCHAR(50) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    uploaded_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, clothing_item_id)
+);
+
+CREATE INDEX idx_wardrobe_item_photos_clothing_item_id ON wardrobe_item_photos(clothing_item_id);
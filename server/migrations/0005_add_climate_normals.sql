@@ -0,0 +1,16 @@
+-- Migration: Add climate_normals for outfit plans beyond the forecast horizon (cmd/climate)
+
+-- Месячные нормы по месту: поставляемый набор (source = 'dataset') и агрегат weather_data (source = 'history')
+CREATE TABLE climate_normals (
+    location VARCHAR(100) NOT NULL,  -- Normalized: lower case, single spaces
+    month SMALLINT NOT NULL CHECK (month BETWEEN 1 AND 12),
+    source VARCHAR(20) NOT NULL,
+    temp_mean DECIMAL(5, 2) NOT NULL,
+    temp_min DECIMAL(5, 2) NOT NULL,
+    temp_max DECIMAL(5, 2) NOT NULL,
+    precip_mm DECIMAL(6, 1),          -- NULL for history: weather_data has no precipitation amounts
+    precip_days DECIMAL(4, 1) NOT NULL DEFAULT 0,
+    observations INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (location, month, source)
+);
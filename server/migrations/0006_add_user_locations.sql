@@ -0,0 +1,24 @@
+-- Migration: Add user_locations for saved places (home, work, custom) with a default one
+
+CREATE TABLE user_locations (
+    id SERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL CHECK (kind IN ('home', 'work', 'custom')),
+    name VARCHAR(100) NOT NULL,
+    location_id VARCHAR(64),               -- City gazetteer id, if the place was picked from suggestions
+    lat DECIMAL(8, 5) NOT NULL CHECK (lat BETWEEN -90 AND 90),
+    lon DECIMAL(8, 5) NOT NULL CHECK (lon BETWEEN -180 AND 180),
+    timezone VARCHAR(64) NOT NULL,         -- IANA name, e.g. Europe/Moscow
+    is_default BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_locations_user_id ON user_locations(user_id);
+
+-- Дом и работа — по одному на пользователя, место по умолчанию — одно
+CREATE UNIQUE INDEX idx_user_locations_user_kind ON user_locations(user_id, kind) WHERE kind IN ('home', 'work');
+CREATE UNIQUE INDEX idx_user_locations_user_default ON user_locations(user_id) WHERE is_default;
+
+CREATE TRIGGER update_user_locations_updated_at BEFORE UPDATE ON user_locations
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
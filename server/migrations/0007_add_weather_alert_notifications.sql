@@ -0,0 +1,10 @@
+-- Migration: Add weather_alert_notifications to send each severe-weather alert once per user and day
+
+CREATE TABLE weather_alert_notifications (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('extreme_cold', 'heat', 'storm', 'heavy_snow', 'strong_wind')),
+    alert_date DATE NOT NULL,              -- Local date at the user's location
+    sent_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, kind, alert_date)
+);
+
@@ -0,0 +1,27 @@
+-- Migration: Add outfit digest subscriptions and a delivery log for the morning email digest
+
+CREATE TABLE digest_subscriptions (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    send_time TIME NOT NULL,               -- Local time at timezone, e.g. 07:30
+    timezone VARCHAR(64) NOT NULL,         -- IANA name, e.g. Europe/Moscow
+    next_run_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_digest_subscriptions_due ON digest_subscriptions(next_run_at) WHERE enabled;
+
+CREATE TRIGGER update_digest_subscriptions_updated_at BEFORE UPDATE ON digest_subscriptions
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Строка создаётся до отправки письма: повторный запуск после сбоя не пришлёт дайджест дважды
+CREATE TABLE digest_deliveries (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    digest_date DATE NOT NULL,             -- Local date the digest was scheduled for
+    status VARCHAR(10) NOT NULL DEFAULT 'sending' CHECK (status IN ('sending', 'sent', 'skipped', 'failed')),
+    error TEXT,
+    claimed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (user_id, digest_date)
+);
@@ -0,0 +1,21 @@
+-- Migration: Add jobs, a durable background job queue (workers claim rows with FOR UPDATE SKIP LOCKED)
+
+CREATE TABLE jobs (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    unique_key VARCHAR(200),               -- Optional idempotency key: one job per (kind, unique_key)
+    status VARCHAR(10) NOT NULL DEFAULT 'queued' CHECK (status IN ('queued', 'running', 'done', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,   -- Incremented when a worker claims the job
+    max_attempts INTEGER NOT NULL CHECK (max_attempts > 0),
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMP WITH TIME ZONE, -- Lease of a running job; an expired lease means the worker died
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    finished_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_jobs_kind_unique_key ON jobs(kind, unique_key) WHERE unique_key IS NOT NULL;
+CREATE INDEX idx_jobs_queued ON jobs(run_at) WHERE status = 'queued';
+CREATE INDEX idx_jobs_running ON jobs(locked_until) WHERE status = 'running';
+CREATE INDEX idx_jobs_done ON jobs(finished_at) WHERE status = 'done';
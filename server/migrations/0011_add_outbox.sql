@@ -0,0 +1,17 @@
+-- Migration: Add outbox_events, domain events committed together with the rows they describe
+
+CREATE TABLE outbox_events (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(40) NOT NULL UNIQUE,  -- Stable id seen by subscribers; identical on every relay attempt
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    published_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_outbox_events_pending ON outbox_events(next_attempt_at) WHERE published_at IS NULL;
+CREATE INDEX idx_outbox_events_published ON outbox_events(published_at) WHERE published_at IS NOT NULL;
@@ -0,0 +1,54 @@
+-- Migration: Data-driven achievements. Each achievement carries a JSON rule evaluated on user events;
+-- adding an achievement is an INSERT, no code change.
+--
+-- Rule format (achievements.rule):
+--   {"event": "rating.submitted", "count": 10}                                     -- 10 matching events
+--   {"event": "recommendation.created", "where": {"temperature": {"lt": -10}}}     -- condition on event data
+--   {"stat": "recommendation_streak_days", "min": 7}                              -- user stat threshold
+-- Conditions: eq, lt, lte, gt, gte, in. Stats: recommendations, ratings, favorites, wardrobe_items,
+-- recommendation_streak_days.
+
+-- The repository has always looked achievements up by code; older seeds used name as the code
+ALTER TABLE achievements ADD COLUMN code VARCHAR(50);
+UPDATE achievements SET code = name WHERE code IS NULL;
+ALTER TABLE achievements ALTER COLUMN code SET NOT NULL;
+ALTER TABLE achievements ADD CONSTRAINT achievements_code_key UNIQUE (code);
+
+ALTER TABLE achievements ADD COLUMN rule JSONB;                        -- NULL: unlocked only explicitly
+ALTER TABLE achievements ADD COLUMN active BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Progress towards counted and stat rules
+CREATE TABLE user_achievement_progress (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    achievement_id INTEGER NOT NULL REFERENCES achievements(id) ON DELETE CASCADE,
+    progress INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, achievement_id)
+);
+
+-- Events already applied to progress: a retried request or job never counts twice
+CREATE TABLE achievement_events (
+    event_key VARCHAR(100) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO achievements (code, name, description, icon, rule)
+VALUES
+  ('first_recommendation', 'Первая рекомендация', 'Получите первую рекомендацию', '👕',
+   '{"event": "recommendation.created"}'),
+  ('cold_warrior', 'Холодный воин', 'Получите рекомендацию при температуре ниже -10°C', '🥶',
+   '{"event": "recommendation.created", "where": {"temperature": {"lt": -10}}}'),
+  ('rainy_day', 'Дождливый день', 'Получите рекомендацию в дождливый день', '🌧️',
+   '{"event": "recommendation.created", "where": {"will_rain": {"eq": true}}}'),
+  ('heat_master', 'Мастер жары', 'Получите рекомендацию при температуре от +30°C', '🔥',
+   '{"event": "recommendation.created", "where": {"temperature": {"gte": 30}}}'),
+  ('critic', 'Критик', 'Оцените 10 рекомендаций', '⭐',
+   '{"event": "rating.submitted", "count": 10}'),
+  ('week_streak', 'Неделя в образе', 'Получайте рекомендации 7 дней подряд', '📅',
+   '{"event": "recommendation.created", "stat": "recommendation_streak_days", "min": 7}'),
+  ('collector', 'Коллекционер', 'Соберите в гардеробе 20 вещей', '🧺',
+   '{"event": "wardrobe.item_added", "stat": "wardrobe_items", "min": 20}')
+ON CONFLICT (code) DO UPDATE
+SET name = EXCLUDED.name, description = EXCLUDED.description, icon = EXCLUDED.icon, rule = EXCLUDED.rule
+WHERE achievements.rule IS NULL;
@@ -0,0 +1,40 @@
+-- Migration: Add user_stats, per-user statistics maintained from domain events.
+-- Recompute from source tables with: go run ./cmd/rebuild-stats
+
+CREATE TABLE user_stats (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    total_recommendations INTEGER NOT NULL DEFAULT 0,
+    average_rating DOUBLE PRECISION NOT NULL DEFAULT 0,
+    rating_count INTEGER NOT NULL DEFAULT 0,
+    favorite_count INTEGER NOT NULL DEFAULT 0,
+    achievement_count INTEGER NOT NULL DEFAULT 0,
+    last_active TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    most_used_category VARCHAR(50) NOT NULL DEFAULT '',
+    current_streak INTEGER NOT NULL DEFAULT 0,   -- Consecutive UTC days with recommendations ending on last_streak_date
+    longest_streak INTEGER NOT NULL DEFAULT 0,
+    last_streak_date DATE,
+    temperature_sum DOUBLE PRECISION NOT NULL DEFAULT 0, -- Sum over total_recommendations, for the average
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_user_stats_updated_at BEFORE UPDATE ON user_stats
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Recommended items per category and season (by UTC month, northern hemisphere)
+CREATE TABLE user_category_stats (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    season VARCHAR(10) NOT NULL CHECK (season IN ('winter', 'spring', 'summer', 'autumn')),
+    category VARCHAR(50) NOT NULL,
+    item_count INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, season, category)
+);
+
+-- Recommendations already added to the counters ('recommendation:<id>'): neither a relay
+-- retry nor an event arriving after a rebuild counts twice
+CREATE TABLE user_stats_events (
+    event_key VARCHAR(64) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_stats_events_user_id ON user_stats_events(user_id);
@@ -0,0 +1,81 @@
+-- Migration: Daily check-in streaks with freeze tokens and data-driven monthly challenges.
+--
+-- A check-in is a day (UTC) with a viewed (recommendation.created) or rated (rating.submitted) recommendation.
+-- Missed days are covered by freeze tokens while they last; a token is earned every 7 streak days (at most 2).
+--
+-- Challenge rule format (challenges.rule), counted per calendar month (UTC):
+--   {"event": "rating.submitted", "count": 10}                                  -- 10 matching events
+--   {"event": "check_in", "count": 20}                                          -- 20 check-in days
+--   {"event": "recommendation.created", "distinct": "owned_item_ids", "count": 20} -- 20 distinct values
+--   {"event": "recommendation.created", "distinct": "styles", "new_only": true}  -- a value not seen in earlier months
+-- "where" takes the same conditions as achievement rules.
+
+CREATE TABLE user_streaks (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    current_streak INTEGER NOT NULL DEFAULT 0,
+    longest_streak INTEGER NOT NULL DEFAULT 0,
+    last_check_in DATE,
+    freeze_tokens INTEGER NOT NULL DEFAULT 0 CHECK (freeze_tokens >= 0),
+    freezes_used INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TRIGGER update_user_streaks_updated_at BEFORE UPDATE ON user_streaks
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE challenges (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    icon VARCHAR(10),
+    rule JSONB NOT NULL,
+    month DATE CHECK (month = date_trunc('month', month)::date), -- NULL: every month
+    reward_freezes INTEGER NOT NULL DEFAULT 0 CHECK (reward_freezes >= 0),
+    reward_achievement_id INTEGER REFERENCES achievements(id) ON DELETE SET NULL,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE user_challenge_progress (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    challenge_id INTEGER NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    period DATE NOT NULL,                                 -- First day of the month
+    progress INTEGER NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (user_id, challenge_id, period)
+);
+
+-- Values counted by "distinct" rules
+CREATE TABLE user_challenge_values (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    challenge_id INTEGER NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    value VARCHAR(100) NOT NULL,
+    period DATE NOT NULL,
+    PRIMARY KEY (user_id, challenge_id, value, period)
+);
+
+-- Events already applied to streaks and challenges: a relay retry never counts twice
+CREATE TABLE gamification_events (
+    event_id VARCHAR(40) PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    processed_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+INSERT INTO achievements (code, name, description, icon)
+VALUES ('style_explorer', 'Исследователь стиля', 'Попробуйте новый стиль в ежемесячном испытании', '🧭')
+ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO challenges (code, name, description, icon, rule, reward_freezes, reward_achievement_id)
+VALUES
+  ('wardrobe_20', 'Весь гардероб в деле', 'Носите за месяц 20 разных вещей своего гардероба из рекомендаций', '🧥',
+   '{"event": "recommendation.created", "distinct": "owned_item_ids", "count": 20}', 1, NULL),
+  ('new_style', 'Новый стиль', 'Попробуйте стиль, которого не было в прошлые месяцы', '🧭',
+   '{"event": "recommendation.created", "distinct": "styles", "new_only": true}', 0,
+   (SELECT id FROM achievements WHERE code = 'style_explorer')),
+  ('check_in_20', 'Двадцать дней', 'Заходите за образом 20 дней в месяц', '📆',
+   '{"event": "check_in", "count": 20}', 1, NULL),
+  ('critic_month', 'Критик месяца', 'Оцените 10 рекомендаций за месяц', '⭐',
+   '{"event": "rating.submitted", "count": 10}', 0, NULL)
+ON CONFLICT (code) DO NOTHING;
@@ -0,0 +1,29 @@
+-- Migration: Auth state shared by all API replicas: one-time codes and tokens, revoked refresh tokens
+-- and rate limits. Codes and tokens are stored as SHA-256 hashes; expired rows are removed by the server.
+
+CREATE TABLE auth_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('registration', 'login', 'password_reset')),
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_auth_tokens_expires_at ON auth_tokens(expires_at);
+
+CREATE TABLE revoked_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL, -- After this the token is invalid anyway
+    revoked_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_revoked_tokens_expires_at ON revoked_tokens(expires_at);
+
+-- One action per key per window (e.g. a password reset email per address every 5 minutes)
+CREATE TABLE auth_rate_limits (
+    key VARCHAR(255) PRIMARY KEY,
+    last_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_auth_rate_limits_expires_at ON auth_rate_limits(expires_at);
@@ -0,0 +1,29 @@
+-- Migration: Refresh token sessions. A session is a family of refresh tokens: each use rotates the token,
+-- and presenting an already rotated token revokes the whole family. Tokens are stored as SHA-256 hashes.
+-- Revoking a session replaces the revoked_tokens blacklist.
+
+CREATE TABLE auth_sessions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    device VARCHAR(255) NOT NULL DEFAULT '', -- User-Agent of the last refresh
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL, -- Moved forward on every rotation
+    revoked_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_auth_sessions_user_id ON auth_sessions(user_id);
+CREATE INDEX idx_auth_sessions_expires_at ON auth_sessions(expires_at);
+
+-- Every token ever issued to the family; rotated tokens are kept (used_at set) to detect reuse
+CREATE TABLE refresh_tokens (
+    token_hash VARCHAR(64) PRIMARY KEY,
+    session_id BIGINT NOT NULL REFERENCES auth_sessions(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    used_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens(session_id);
+
+DROP TABLE IF EXISTS revoked_tokens;
@@ -0,0 +1,22 @@
+-- Migration: Optional TOTP two-factor authentication (RFC 6238) with one-time recovery codes.
+-- A row without enabled_at is a pending enrolment waiting for the first code.
+
+CREATE TABLE user_two_factor (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL, -- Base32; needed to compute codes, so it cannot be hashed
+    enabled_at TIMESTAMP WITH TIME ZONE,
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- Time step of the last accepted code; older codes are rejected
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE user_recovery_codes (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL, -- bcrypt of the normalized code
+    used_at TIMESTAMP WITH TIME ZONE,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+-- Login challenge issued after the password when 2FA is enabled
+ALTER TABLE auth_tokens DROP CONSTRAINT IF EXISTS auth_tokens_kind_check;
+ALTER TABLE auth_tokens ADD CONSTRAINT auth_tokens_kind_check
+    CHECK (kind IN ('registration', 'login', 'password_reset', 'two_factor'));
@@ -0,0 +1,18 @@
+-- Migration: Login throttling shared by all API replicas. Failed attempts are counted per account and per IP
+-- over a sliding window; reaching the limit locks the key until expires_at.
+
+CREATE TABLE auth_login_failures (
+    key VARCHAR(255) PRIMARY KEY, -- login:account:<email> or login:ip:<address>
+    attempts TIMESTAMP WITH TIME ZONE[] NOT NULL, -- Failed attempts inside the window
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL -- Last attempt + window
+);
+
+CREATE INDEX idx_auth_login_failures_expires_at ON auth_login_failures(expires_at);
+
+CREATE TABLE auth_lockouts (
+    key VARCHAR(255) PRIMARY KEY,
+    locked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX idx_auth_lockouts_expires_at ON auth_lockouts(expires_at);
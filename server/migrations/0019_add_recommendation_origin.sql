@@ -0,0 +1,5 @@
+-- Migration: Who asked for a recommendation. Recommendations the server builds on its own (the morning
+-- digest) are kept in history but are not check-ins and do not count towards challenges, stats or achievements.
+
+ALTER TABLE recommendations
+    ADD COLUMN origin VARCHAR(20) NOT NULL DEFAULT 'user' CHECK (origin IN ('user', 'digest'));
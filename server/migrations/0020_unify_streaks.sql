@@ -0,0 +1,15 @@
+-- Migration: One streak for everything. Daily check-in streaks live in user_streaks (0014); user_stats
+-- no longer keeps its own copy, and achievement rules use the check-in streak as "streak_days".
+
+ALTER TABLE user_stats
+    DROP COLUMN current_streak,
+    DROP COLUMN longest_streak,
+    DROP COLUMN last_streak_date;
+
+UPDATE achievements
+SET rule = jsonb_set(rule, '{stat}', '"streak_days"')
+WHERE rule->>'stat' = 'recommendation_streak_days';
+
+UPDATE achievements
+SET description = 'Заходите за образом 7 дней подряд'
+WHERE code = 'week_streak';
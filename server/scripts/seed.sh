@@ -38,14 +38,7 @@ VALUES
   ('accessories', 'Аксессуары')
 ON CONFLICT (name) DO NOTHING;
 
--- Insert sample achievements
-INSERT INTO achievements (name, description, icon) 
-VALUES 
-  ('first_recommendation', 'Первая рекомендация', '👕'),
-  ('cold_warrior', 'Холодный воин', '🥶'),
-  ('rainy_day', 'Дождливый день', '🌧️'),
-  ('heat_master', 'Мастер жары', '🔥')
-ON CONFLICT (name) DO NOTHING;
+-- Achievements are seeded by migrations/0012_add_achievement_rules.sql
 EOF
 
 echo "✅ Database seeding completed!"
\ No newline at end of file